@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+// runCommand runs the subcommand named by args[0], reporting whether there
+// was one:
+//
+//	server backup [-o file]
+//	server restore [-mode merge|replace] <file|->
+func runCommand(args []string, backupService services.BackupService) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+	switch args[0] {
+	case "backup":
+		return true, backupCommand(args[1:], backupService)
+	case "restore":
+		return true, restoreCommand(args[1:], backupService)
+	}
+	return false, nil
+}
+
+func backupCommand(args []string, service services.BackupService) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	output := flags.String("o", "", "write the backup to this file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	backup, err := service.Export()
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(backup)
+}
+
+func restoreCommand(args []string, service services.BackupService) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	mode := flags.String("mode", string(models.RestoreMerge), "merge or replace")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: restore [-mode merge|replace] <file|->")
+	}
+
+	var r io.Reader = os.Stdin
+	if name := flags.Arg(0); name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	var backup models.Backup
+	if err := json.NewDecoder(r).Decode(&backup); err != nil {
+		return fmt.Errorf("reading backup: %w", err)
+	}
+	report, err := service.Restore(&backup, models.RestoreMode(*mode))
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"log"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/industrix-todo-app/backend/internal/config"
@@ -10,6 +13,7 @@ import (
 	"github.com/industrix-todo-app/backend/internal/models"
 	"github.com/industrix-todo-app/backend/internal/repository"
 	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/industrix-todo-app/backend/internal/storage"
 )
 
 func main() {
@@ -26,21 +30,92 @@ func main() {
 	}
 
 	// Auto migrate models
-	if err := db.AutoMigrate(&models.Category{}, &models.Todo{}); err != nil {
+	if err := db.AutoMigrate(&models.Category{}, &models.Status{}, &models.Todo{}, &models.Tag{}, &models.CollectionChange{}, &models.IdempotencyRecord{}, &models.TodoDependency{}, &models.Comment{}, &models.Attachment{}, &models.TimeEntry{}, &models.ChecklistItem{}, &models.Template{}, &models.CalendarToken{}, &models.CalendarResource{}, &models.SmartList{}); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
 	// Initialize repositories
 	categoryRepo := repository.NewCategoryRepository(db)
 	todoRepo := repository.NewTodoRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	statusRepo := repository.NewStatusRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	timeEntryRepo := repository.NewTimeEntryRepository(db)
+	checklistRepo := repository.NewChecklistRepository(db)
+	templateRepo := repository.NewTemplateRepository(db)
+	backupRepo := repository.NewBackupRepository(db)
+	calendarTokenRepo := repository.NewCalendarTokenRepository(db)
+	calendarResourceRepo := repository.NewCalendarResourceRepository(db)
+	smartListRepo := repository.NewSmartListRepository(db)
+
+	// Open attachment storage
+	blobStore, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to open attachment storage: %v", err)
+	}
 
 	// Initialize services
 	categoryService := services.NewCategoryService(categoryRepo)
-	todoService := services.NewTodoService(todoRepo)
+	attachmentService := services.NewAttachmentService(attachmentRepo, todoRepo, blobStore, cfg.AttachmentMaxSize, cfg.AttachmentAllowedTypes)
+	todoService := services.NewTodoService(todoRepo, categoryRepo, statusRepo, attachmentService, cfg.EnforceBlockers)
+	statusService := services.NewStatusService(statusRepo, todoRepo, todoService)
+	commentService := services.NewCommentService(commentRepo, todoRepo)
+	timeTrackingService := services.NewTimeTrackingService(timeEntryRepo, todoRepo)
+	checklistService := services.NewChecklistService(checklistRepo, todoRepo)
+	templateService := services.NewTemplateService(templateRepo, todoRepo, categoryRepo, checklistRepo, todoService)
+	backupService := services.NewBackupService(backupRepo)
+	calendarService := services.NewCalendarService(calendarTokenRepo)
+	caldavService := services.NewCalDAVService(categoryRepo, calendarResourceRepo, todoService)
+	smartListService := services.NewSmartListService(smartListRepo, categoryRepo, statusRepo, todoService)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo, cfg.IdempotencyTTL)
+
+	// Seed the board and give existing todos a status
+	if err := statusService.EnsureDefaults(); err != nil {
+		log.Fatalf("Failed to seed statuses: %v", err)
+	}
+
+	// Seed the built-in smart lists
+	if err := smartListService.EnsureDefaults(); err != nil {
+		log.Fatalf("Failed to seed smart lists: %v", err)
+	}
+
+	// Give todos created before manual ordering a position
+	if err := todoService.BackfillPositions(); err != nil {
+		log.Fatalf("Failed to backfill todo positions: %v", err)
+	}
+
+	// Run a backup or restore instead of serving when asked to
+	if ran, err := runCommand(os.Args[1:], backupService); ran {
+		if err != nil {
+			log.Fatalf("%s failed: %v", os.Args[1], err)
+		}
+		return
+	}
+
+	// Purge expired idempotency records in the background
+	go func() {
+		for range time.Tick(time.Hour) {
+			if _, err := idempotencyService.PurgeExpired(); err != nil {
+				log.Printf("Failed to purge idempotency records: %v", err)
+			}
+		}
+	}()
 
 	// Initialize handlers
-	categoryHandler := handlers.NewCategoryHandler(categoryService)
-	todoHandler := handlers.NewTodoHandler(todoService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService, cfg.RequireIfMatch)
+	todoHandler := handlers.NewTodoHandler(todoService, cfg.RequireIfMatch)
+	statusHandler := handlers.NewStatusHandler(statusService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService, cfg.AttachmentMaxSize)
+	timeTrackingHandler := handlers.NewTimeTrackingHandler(timeTrackingService)
+	checklistHandler := handlers.NewChecklistHandler(checklistService)
+	templateHandler := handlers.NewTemplateHandler(templateService)
+	backupHandler := handlers.NewBackupHandler(backupService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService, todoService)
+	caldavHandler := handlers.NewCalDAVHandler(caldavService, calendarService)
+	smartListHandler := handlers.NewSmartListHandler(smartListService)
+	idempotency := handlers.Idempotency(idempotencyService)
 
 	// Setup Gin router
 	r := gin.Default()
@@ -49,9 +124,11 @@ func main() {
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, If-None-Match, If-Modified-Since, Idempotency-Key, X-User, Range")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, Last-Modified, Idempotent-Replayed, Content-Disposition, Content-Range, Accept-Ranges")
 
-		if c.Request.Method == "OPTIONS" {
+		// CalDAV clients expect OPTIONS to describe the server
+		if c.Request.Method == "OPTIONS" && !strings.HasPrefix(c.Request.URL.Path, "/caldav") {
 			c.AbortWithStatus(204)
 			return
 		}
@@ -66,9 +143,10 @@ func main() {
 		categories := api.Group("/categories")
 		{
 			categories.GET("", categoryHandler.GetAll)
-			categories.POST("", categoryHandler.Create)
+			categories.POST("", idempotency, categoryHandler.Create)
 			categories.GET("/:id", categoryHandler.GetByID)
 			categories.PUT("/:id", categoryHandler.Update)
+			categories.PATCH("/:id", categoryHandler.Patch)
 			categories.DELETE("/:id", categoryHandler.Delete)
 		}
 
@@ -76,13 +154,96 @@ func main() {
 		todos := api.Group("/todos")
 		{
 			todos.GET("", todoHandler.GetAll)
-			todos.POST("", todoHandler.Create)
+			todos.POST("", idempotency, todoHandler.Create)
+			todos.POST("/bulk", idempotency, todoHandler.Bulk)
+			todos.POST("/quick", idempotency, todoHandler.QuickAdd)
+			todos.GET("/export", todoHandler.Export)
+			todos.POST("/import", idempotency, todoHandler.Import)
 			todos.GET("/:id", todoHandler.GetByID)
 			todos.PUT("/:id", todoHandler.Update)
+			todos.PATCH("/:id", todoHandler.Patch)
 			todos.DELETE("/:id", todoHandler.Delete)
 			todos.PATCH("/:id/complete", todoHandler.ToggleComplete)
+			todos.POST("/:id/move", todoHandler.Move)
+			todos.GET("/:id/blockers", todoHandler.GetBlockers)
+			todos.POST("/:id/blockers", todoHandler.AddBlocker)
+			todos.DELETE("/:id/blockers/:blockerId", todoHandler.RemoveBlocker)
+			todos.GET("/:id/blocking", todoHandler.GetBlocking)
+			todos.GET("/:id/comments", commentHandler.GetAll)
+			todos.POST("/:id/comments", commentHandler.Create)
+			todos.PUT("/:id/comments/:commentId", commentHandler.Update)
+			todos.DELETE("/:id/comments/:commentId", commentHandler.Delete)
+			todos.GET("/:id/attachments", attachmentHandler.GetAll)
+			todos.POST("/:id/attachments", attachmentHandler.Upload)
+			todos.GET("/:id/attachments/:attachmentId", attachmentHandler.Download)
+			todos.DELETE("/:id/attachments/:attachmentId", attachmentHandler.Delete)
+			todos.POST("/:id/timer/start", timeTrackingHandler.StartTimer)
+			todos.POST("/:id/timer/stop", timeTrackingHandler.StopTimer)
+			todos.GET("/:id/time-entries", timeTrackingHandler.GetEntries)
+			todos.POST("/:id/time-entries", timeTrackingHandler.CreateEntry)
+			todos.DELETE("/:id/time-entries/:entryId", timeTrackingHandler.DeleteEntry)
+			todos.GET("/:id/checklist", checklistHandler.GetAll)
+			todos.POST("/:id/checklist", checklistHandler.Create)
+			todos.PATCH("/:id/checklist/:itemId", checklistHandler.Update)
+			todos.POST("/:id/checklist/:itemId/move", checklistHandler.Move)
+			todos.DELETE("/:id/checklist/:itemId", checklistHandler.Delete)
+			todos.POST("/:id/template", templateHandler.SaveTodo)
 		}
+
+		// Template routes
+		templates := api.Group("/templates")
+		{
+			templates.GET("", templateHandler.GetAll)
+			templates.POST("", templateHandler.Create)
+			templates.GET("/:id", templateHandler.GetByID)
+			templates.PUT("/:id", templateHandler.Update)
+			templates.DELETE("/:id", templateHandler.Delete)
+			templates.POST("/:id/instantiate", idempotency, templateHandler.Instantiate)
+		}
+
+		// Smart list routes
+		smartLists := api.Group("/smart-lists")
+		{
+			smartLists.GET("", smartListHandler.GetAll)
+			smartLists.POST("", smartListHandler.Create)
+			smartLists.GET("/:id", smartListHandler.GetByID)
+			smartLists.PUT("/:id", smartListHandler.Update)
+			smartLists.DELETE("/:id", smartListHandler.Delete)
+			smartLists.GET("/:id/todos", smartListHandler.Todos)
+		}
+
+		// Status routes
+		statuses := api.Group("/statuses")
+		{
+			statuses.GET("", statusHandler.GetAll)
+			statuses.POST("", statusHandler.Create)
+			statuses.PUT("/:id", statusHandler.Update)
+			statuses.DELETE("/:id", statusHandler.Delete)
+		}
+
+		// Board routes
+		api.GET("/board", statusHandler.Board)
+
+		// Time tracking routes
+		api.GET("/timer", timeTrackingHandler.RunningTimer)
+		api.GET("/reports/time", timeTrackingHandler.Report)
+
+		// Calendar routes
+		api.GET("/calendar.ics", calendarHandler.Feed)
+		api.POST("/calendar/token", calendarHandler.CreateToken)
+		api.DELETE("/calendar/token", calendarHandler.RevokeToken)
+
+		// Backup routes
+		api.GET("/backup", backupHandler.Export)
+		api.POST("/backup/restore", backupHandler.Restore)
+	}
+
+	// CalDAV routes
+	for _, method := range handlers.CalDAVMethods {
+		r.Handle(method, "/caldav/*path", caldavHandler.Serve)
 	}
+	r.GET("/.well-known/caldav", caldavHandler.WellKnown)
+	r.Handle("PROPFIND", "/.well-known/caldav", caldavHandler.WellKnown)
 
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
@@ -2,6 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,6 +16,36 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	ServerPort string
+
+	// RequireIfMatch makes PUT, PATCH and DELETE fail with 428 unless the
+	// client sends an If-Match header
+	RequireIfMatch bool
+
+	// IdempotencyTTL is how long responses to requests sent with an
+	// Idempotency-Key header are kept for replay
+	IdempotencyTTL time.Duration
+
+	// EnforceBlockers refuses to complete todos whose blockers are still
+	// open
+	EnforceBlockers bool
+
+	// AttachmentMaxSize is the largest upload accepted, in bytes
+	AttachmentMaxSize int64
+
+	// AttachmentAllowedTypes lists the media types accepted for uploads;
+	// entries such as "image/*" match a whole family
+	AttachmentAllowedTypes []string
+
+	// StorageBackend selects where attachment blobs live: "local" keeps
+	// them below StorageDir, "s3" in an S3-compatible bucket
+	StorageBackend string
+	StorageDir     string
+
+	S3Endpoint        string
+	S3Bucket          string
+	S3Region          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
 }
 
 func Load() (*Config, error) {
@@ -26,6 +59,22 @@ func Load() (*Config, error) {
 		DBPassword: getEnv("DB_PASSWORD", "postgres"),
 		DBName:     getEnv("DB_NAME", "industrix_todo"),
 		ServerPort: getEnv("SERVER_PORT", "8080"),
+
+		RequireIfMatch:  getEnvBool("REQUIRE_IF_MATCH", false),
+		IdempotencyTTL:  getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		EnforceBlockers: getEnvBool("ENFORCE_BLOCKERS", false),
+
+		AttachmentMaxSize:      getEnvInt64("ATTACHMENT_MAX_SIZE", 10<<20),
+		AttachmentAllowedTypes: getEnvList("ATTACHMENT_ALLOWED_TYPES", "image/*,application/pdf,text/plain,text/csv,application/zip"),
+
+		StorageBackend: getEnv("STORAGE_BACKEND", "local"),
+		StorageDir:     getEnv("STORAGE_DIR", "./uploads"),
+
+		S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+		S3Bucket:          getEnv("S3_BUCKET", ""),
+		S3Region:          getEnv("S3_REGION", "us-east-1"),
+		S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
 	}, nil
 }
 
@@ -35,3 +84,35 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value, err := strconv.ParseInt(os.Getenv(key), 10, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvList splits a comma separated value, dropping empty entries
+func getEnvList(key, defaultValue string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+// multipartOverhead leaves room for the multipart framing around the file
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	service services.AttachmentService
+	maxSize int64
+}
+
+func NewAttachmentHandler(service services.AttachmentService, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{service: service, maxSize: maxSize}
+}
+
+// Upload stores the multipart "file" field as an attachment of a todo
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+multipartOverhead)
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrAttachmentTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "multipart field \"file\" is required"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.Upload(uint(todoID), currentUser(c), services.AttachmentUpload{
+		FileName: header.Filename,
+		Size:     header.Size,
+		Body:     file,
+	})
+	if err != nil {
+		respondAttachmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// GetAll lists the attachments of a todo
+func (h *AttachmentHandler) GetAll(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	attachments, err := h.service.List(uint(todoID))
+	if err != nil {
+		respondAttachmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+// Download streams an attachment, honouring Range requests
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	todoID, attachmentID, ok := attachmentParams(c)
+	if !ok {
+		return
+	}
+
+	attachment, object, err := h.service.Open(todoID, attachmentID)
+	if err != nil {
+		respondAttachmentError(c, err)
+		return
+	}
+	defer object.Close()
+
+	c.Header("Content-Type", attachment.ContentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, attachment.FileName, attachment.CreatedAt, object)
+}
+
+// Delete removes an attachment and its blob
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	todoID, attachmentID, ok := attachmentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(todoID, attachmentID); err != nil {
+		respondAttachmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "attachment deleted successfully"})
+}
+
+func attachmentParams(c *gin.Context) (todoID, attachmentID uint, ok bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return 0, 0, false
+	}
+	aid, err := strconv.ParseUint(c.Param("attachmentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment ID"})
+		return 0, 0, false
+	}
+	return uint(id), uint(aid), true
+}
+
+func respondAttachmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTodoNotFound), errors.Is(err, services.ErrAttachmentNotFound):
+		respondError(c, http.StatusNotFound, err)
+	case errors.Is(err, services.ErrAttachmentTooLarge):
+		respondError(c, http.StatusRequestEntityTooLarge, err)
+	case errors.Is(err, services.ErrAttachmentTypeNotAllowed):
+		respondError(c, http.StatusUnsupportedMediaType, err)
+	case errors.Is(err, services.ErrAttachmentEmpty):
+		respondError(c, http.StatusBadRequest, err)
+	default:
+		respondError(c, http.StatusInternalServerError, err)
+	}
+}
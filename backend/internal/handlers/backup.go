@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+// maxBackupSize bounds the size of a restored backup
+const maxBackupSize = 100 << 20
+
+var errBackupTooLarge = errors.New("backups must be at most 100 MB")
+
+type BackupHandler struct {
+	service services.BackupService
+}
+
+func NewBackupHandler(service services.BackupService) *BackupHandler {
+	return &BackupHandler{service: service}
+}
+
+// Export downloads a backup of all data
+func (h *BackupHandler) Export(c *gin.Context) {
+	backup, err := h.service.Export()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("todo-backup-%s.json", backup.ExportedAt.Format("20060102"))
+	c.Header("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	c.JSON(http.StatusOK, backup)
+}
+
+// Restore loads a backup sent as the request body. mode=merge (the default)
+// keeps existing data and skips duplicates; mode=replace deletes it first
+func (h *BackupHandler) Restore(c *gin.Context) {
+	mode := models.RestoreMode(c.DefaultQuery("mode", string(models.RestoreMerge)))
+	if mode != models.RestoreMerge && mode != models.RestoreReplace {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrInvalidRestoreMode.Error()})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBackupSize)
+	var backup models.Backup
+	if err := json.NewDecoder(c.Request.Body).Decode(&backup); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errBackupTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.Restore(&backup, mode)
+	if err != nil {
+		respondBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func respondBackupError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnsupportedBackup), errors.Is(err, services.ErrInvalidRestoreMode):
+		respondError(c, http.StatusBadRequest, err)
+	case errors.Is(err, services.ErrRestoreAttachments):
+		respondError(c, http.StatusConflict, err)
+	default:
+		respondError(c, http.StatusInternalServerError, err)
+	}
+}
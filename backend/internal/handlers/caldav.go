@@ -0,0 +1,573 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/ical"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+// CalDAVMethods are the methods CalDAVHandler.Serve answers
+var CalDAVMethods = []string{"OPTIONS", "PROPFIND", "REPORT", "GET", "HEAD", "PUT", "DELETE"}
+
+const (
+	caldavRoot = "/caldav/"
+	caldavHome = caldavRoot + "calendars/"
+)
+
+var (
+	errInvalidDAVBody    = errors.New("request body is not a valid WebDAV request")
+	errInvalidTimeRange  = errors.New("time-range start and end must be UTC date-times")
+	errUnsupportedReport = errors.New("only calendar-query and calendar-multiget reports are supported")
+)
+
+// Properties we serve
+var (
+	propResourceType          = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	propDisplayName           = xml.Name{Space: nsDAV, Local: "displayname"}
+	propCurrentUserPrincipal  = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	propPrincipalURL          = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	propCurrentUserPrivileges = xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}
+	propSupportedReports      = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+	propETag                  = xml.Name{Space: nsDAV, Local: "getetag"}
+	propContentType           = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	propLastModified          = xml.Name{Space: nsDAV, Local: "getlastmodified"}
+	propCalendarHomeSet       = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	propSupportedComponents   = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	propCalendarData          = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	propCTag                  = xml.Name{Space: nsCS, Local: "getctag"}
+	propCalendarColor         = xml.Name{Space: nsICal, Local: "calendar-color"}
+)
+
+// caldavKind is the kind of resource a CalDAV path names
+type caldavKind int
+
+const (
+	caldavPrincipal caldavKind = iota
+	caldavCalendarHome
+	caldavCalendar
+	caldavObject
+)
+
+type caldavTarget struct {
+	kind       caldavKind
+	calendarID uint
+	name       string
+}
+
+// CalDAVHandler serves a CalDAV subset under /caldav so task apps can sync
+// todos both ways. /caldav/ is the principal, /caldav/calendars/ holds a
+// calendar per category and each todo is an object in its category's
+// calendar. Clients sign in with HTTP Basic, using a calendar token as
+// the password
+type CalDAVHandler struct {
+	service  services.CalDAVService
+	calendar services.CalendarService
+}
+
+func NewCalDAVHandler(service services.CalDAVService, calendar services.CalendarService) *CalDAVHandler {
+	return &CalDAVHandler{service: service, calendar: calendar}
+}
+
+// WellKnown points clients looking for a CalDAV server at the principal
+func (h *CalDAVHandler) WellKnown(c *gin.Context) {
+	c.Redirect(http.StatusMovedPermanently, caldavRoot)
+}
+
+// Serve dispatches every request below /caldav on its method
+func (h *CalDAVHandler) Serve(c *gin.Context) {
+	if c.Request.Method == http.MethodOptions {
+		c.Header("DAV", "1, 3, calendar-access")
+		c.Header("Allow", strings.Join(CalDAVMethods, ", "))
+		c.Status(http.StatusOK)
+		return
+	}
+	if !h.authenticate(c) {
+		return
+	}
+	target, ok := parseCalDAVPath(c.Param("path"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
+		return
+	}
+
+	switch c.Request.Method {
+	case "PROPFIND":
+		h.propfind(c, target)
+	case "REPORT":
+		h.report(c, target)
+	case http.MethodGet, http.MethodHead:
+		h.get(c, target)
+	case http.MethodPut:
+		h.put(c, target)
+	case http.MethodDelete:
+		h.delete(c, target)
+	default:
+		caldavMethodNotAllowed(c)
+	}
+}
+
+func (h *CalDAVHandler) authenticate(c *gin.Context) bool {
+	if _, token, ok := c.Request.BasicAuth(); ok {
+		if _, err := h.calendar.Authenticate(token); err == nil {
+			return true
+		}
+	}
+	c.Header("WWW-Authenticate", `Basic realm="Todos"`)
+	c.JSON(http.StatusUnauthorized, gin.H{"error": services.ErrCalendarTokenInvalid.Error()})
+	return false
+}
+
+func (h *CalDAVHandler) propfind(c *gin.Context, target caldavTarget) {
+	body, err := readDAVBody(c)
+	if err == nil && body != nil && body.XMLName != (xml.Name{Space: nsDAV, Local: "propfind"}) {
+		err = errInvalidDAVBody
+	}
+	if err != nil {
+		respondDAVBodyError(c, err)
+		return
+	}
+	// No body, allprop and propname all list every property
+	var requested []xml.Name
+	if body != nil {
+		if prop := body.child(nsDAV, "prop"); prop != nil {
+			requested = prop.names()
+		}
+	}
+	withData := containsName(requested, propCalendarData)
+	children := c.GetHeader("Depth") != "0"
+
+	var responses []davResponse
+	switch target.kind {
+	case caldavPrincipal:
+		responses = append(responses, principalResponse())
+		if children {
+			responses = append(responses, calendarHomeResponse())
+		}
+	case caldavCalendarHome:
+		responses = append(responses, calendarHomeResponse())
+		if children {
+			calendars, err := h.service.Calendars()
+			if err != nil {
+				respondCalDAVError(c, err)
+				return
+			}
+			for i := range calendars {
+				responses = append(responses, calendarResponse(&calendars[i]))
+			}
+		}
+	case caldavCalendar:
+		calendar, err := h.service.Calendar(target.calendarID)
+		if err != nil {
+			respondCalDAVError(c, err)
+			return
+		}
+		responses = append(responses, calendarResponse(calendar))
+		if children {
+			objects, err := h.service.Objects(target.calendarID, models.CalDAVQuery{})
+			if err != nil {
+				respondCalDAVError(c, err)
+				return
+			}
+			for i := range objects {
+				responses = append(responses, objectResponse(target.calendarID, &objects[i], withData))
+			}
+		}
+	case caldavObject:
+		object, err := h.service.Object(target.calendarID, target.name)
+		if err != nil {
+			respondCalDAVError(c, err)
+			return
+		}
+		responses = append(responses, objectResponse(target.calendarID, object, withData))
+	}
+	writeMultistatus(c, responses, requested)
+}
+
+func (h *CalDAVHandler) report(c *gin.Context, target caldavTarget) {
+	if target.kind != caldavCalendar {
+		c.JSON(http.StatusForbidden, gin.H{"error": errUnsupportedReport.Error()})
+		return
+	}
+	body, err := readDAVBody(c)
+	if err == nil && body == nil {
+		err = errInvalidDAVBody
+	}
+	if err != nil {
+		respondDAVBodyError(c, err)
+		return
+	}
+	var requested []xml.Name
+	if prop := body.child(nsDAV, "prop"); prop != nil {
+		requested = prop.names()
+	}
+	withData := containsName(requested, propCalendarData)
+
+	var responses []davResponse
+	switch body.XMLName {
+	case xml.Name{Space: nsCalDAV, Local: "calendar-query"}:
+		query, matches, err := parseCalendarQuery(body.child(nsCalDAV, "filter"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if _, err := h.service.Calendar(target.calendarID); err != nil {
+			respondCalDAVError(c, err)
+			return
+		}
+		if matches {
+			objects, err := h.service.Objects(target.calendarID, query)
+			if err != nil {
+				respondCalDAVError(c, err)
+				return
+			}
+			for i := range objects {
+				responses = append(responses, objectResponse(target.calendarID, &objects[i], withData))
+			}
+		}
+	case xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}:
+		if _, err := h.service.Calendar(target.calendarID); err != nil {
+			respondCalDAVError(c, err)
+			return
+		}
+		for _, href := range body.children(nsDAV, "href") {
+			hrefText := strings.TrimSpace(href.Text)
+			name, ok := objectNameFromHref(target.calendarID, hrefText)
+			if !ok {
+				responses = append(responses, davResponse{href: hrefText, status: http.StatusNotFound})
+				continue
+			}
+			object, err := h.service.Object(target.calendarID, name)
+			if errors.Is(err, services.ErrCalDAVObjectNotFound) {
+				responses = append(responses, davResponse{href: hrefText, status: http.StatusNotFound})
+				continue
+			}
+			if err != nil {
+				respondCalDAVError(c, err)
+				return
+			}
+			responses = append(responses, objectResponse(target.calendarID, object, withData))
+		}
+	default:
+		c.JSON(http.StatusForbidden, gin.H{"error": errUnsupportedReport.Error()})
+		return
+	}
+	writeMultistatus(c, responses, requested)
+}
+
+func (h *CalDAVHandler) get(c *gin.Context, target caldavTarget) {
+	if target.kind != caldavObject {
+		caldavMethodNotAllowed(c)
+		return
+	}
+	object, err := h.service.Object(target.calendarID, target.name)
+	if err != nil {
+		respondCalDAVError(c, err)
+		return
+	}
+
+	data, err := calendarData(object)
+	if err != nil {
+		respondCalDAVError(c, err)
+		return
+	}
+	c.Header("ETag", versionETag(object.Todo.Version))
+	c.Header("Last-Modified", object.Todo.UpdatedAt.UTC().Format(http.TimeFormat))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", data)
+}
+
+// put stores a VTODO. The todo is rewritten from what it kept of the body,
+// so no ETag is returned and clients fetch the stored version
+func (h *CalDAVHandler) put(c *gin.Context, target caldavTarget) {
+	if target.kind != caldavObject {
+		caldavMethodNotAllowed(c)
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c, false)
+	if err != nil {
+		c.JSON(ifMatchStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	createOnly := strings.TrimSpace(c.GetHeader("If-None-Match")) == "*"
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, davMaxBody))
+	if err != nil {
+		respondDAVBodyError(c, err)
+		return
+	}
+
+	object, created, err := h.service.Put(target.calendarID, target.name, data, expectedVersion, createOnly)
+	if err != nil {
+		respondCalDAVError(c, err)
+		return
+	}
+	if created {
+		c.Header("Location", objectHref(target.calendarID, object.Name))
+		c.Status(http.StatusCreated)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CalDAVHandler) delete(c *gin.Context, target caldavTarget) {
+	if target.kind != caldavObject {
+		caldavMethodNotAllowed(c)
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c, false)
+	if err != nil {
+		c.JSON(ifMatchStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.service.Delete(target.calendarID, target.name, expectedVersion); err != nil {
+		respondCalDAVError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// parseCalDAVPath reads the resource a path below /caldav names
+func parseCalDAVPath(path string) (caldavTarget, bool) {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return caldavTarget{kind: caldavPrincipal}, true
+	}
+	parts := strings.Split(path, "/")
+	if parts[0] != "calendars" || len(parts) > 3 {
+		return caldavTarget{}, false
+	}
+	if len(parts) == 1 {
+		return caldavTarget{kind: caldavCalendarHome}, true
+	}
+
+	id, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return caldavTarget{}, false
+	}
+	if len(parts) == 2 {
+		return caldavTarget{kind: caldavCalendar, calendarID: uint(id)}, true
+	}
+	return caldavTarget{kind: caldavObject, calendarID: uint(id), name: parts[2]}, true
+}
+
+// parseCalendarQuery reads the filter of a calendar-query. Only the VTODO
+// parts we can answer are applied: the completion state, through COMPLETED
+// or STATUS, and a time range, which is matched against DUE. Other prop
+// filters are ignored, so the result may hold more than was asked for.
+// matches is false when nothing can match, such as for VEVENTs
+func parseCalendarQuery(filter *davNode) (query models.CalDAVQuery, matches bool, err error) {
+	if filter == nil {
+		return query, true, nil
+	}
+	vcalendar := filter.child(nsCalDAV, "comp-filter")
+	if vcalendar == nil {
+		return query, true, nil
+	}
+	if !strings.EqualFold(vcalendar.attr("name"), "VCALENDAR") || vcalendar.child(nsCalDAV, "is-not-defined") != nil {
+		return query, false, nil
+	}
+
+	for _, comp := range vcalendar.children(nsCalDAV, "comp-filter") {
+		if !strings.EqualFold(comp.attr("name"), "VTODO") || comp.child(nsCalDAV, "is-not-defined") != nil {
+			return query, false, nil
+		}
+		if timeRange := comp.child(nsCalDAV, "time-range"); timeRange != nil {
+			if query.DueFrom, err = parseTimeRangeBound(timeRange.attr("start")); err != nil {
+				return query, false, err
+			}
+			if query.DueTo, err = parseTimeRangeBound(timeRange.attr("end")); err != nil {
+				return query, false, err
+			}
+		}
+
+		for _, prop := range comp.children(nsCalDAV, "prop-filter") {
+			completed, ok := completionFilter(prop)
+			if !ok {
+				return query, false, nil
+			}
+			if completed == nil {
+				continue
+			}
+			if query.Completed != nil && *query.Completed != *completed {
+				return query, false, nil
+			}
+			query.Completed = completed
+		}
+	}
+	return query, true, nil
+}
+
+// completionFilter reads the completion state a COMPLETED or STATUS
+// prop-filter asks for, nil when it does not narrow it. ok is false when
+// no todo can match
+func completionFilter(prop *davNode) (completed *bool, ok bool) {
+	undefined := prop.child(nsCalDAV, "is-not-defined") != nil
+	switch strings.ToUpper(prop.attr("name")) {
+	case "COMPLETED":
+		state := !undefined
+		return &state, true
+	case "STATUS":
+		// Every todo has a STATUS
+		if undefined {
+			return nil, false
+		}
+		match := prop.child(nsCalDAV, "text-match")
+		if match == nil {
+			return nil, true
+		}
+		negate := match.attr("negate-condition") == "yes"
+		switch strings.ToUpper(strings.TrimSpace(match.Text)) {
+		case "COMPLETED":
+			state := !negate
+			return &state, true
+		case "NEEDS-ACTION":
+			state := negate
+			return &state, true
+		default:
+			// Todos are never IN-PROCESS or CANCELLED
+			return nil, negate
+		}
+	}
+	return nil, true
+}
+
+func parseTimeRangeBound(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("20060102T150405Z", value)
+	if err != nil {
+		return nil, errInvalidTimeRange
+	}
+	return &t, nil
+}
+
+// objectNameFromHref returns the name of an object of a calendar from its
+// href, which may be a full URL
+func objectNameFromHref(calendarID uint, href string) (string, bool) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+	name, ok := strings.CutPrefix(u.Path, calendarHref(calendarID))
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return name, true
+}
+
+func principalResponse() davResponse {
+	return davResponse{href: caldavRoot, props: davProps{
+		propResourceType:         "<d:collection/><d:principal/>",
+		propDisplayName:          "Todos",
+		propCurrentUserPrincipal: davHref(caldavRoot),
+		propPrincipalURL:         davHref(caldavRoot),
+		propCalendarHomeSet:      davHref(caldavHome),
+	}}
+}
+
+func calendarHomeResponse() davResponse {
+	return davResponse{href: caldavHome, props: davProps{
+		propResourceType:         "<d:collection/>",
+		propDisplayName:          "Calendars",
+		propCurrentUserPrincipal: davHref(caldavRoot),
+	}}
+}
+
+func calendarResponse(calendar *models.CalDAVCalendar) davResponse {
+	ctag := fmt.Sprintf("%d-%d", calendar.State.LastModified.UnixNano(), calendar.State.Count)
+	return davResponse{href: calendarHref(calendar.Category.ID), props: davProps{
+		propResourceType:          "<d:collection/><c:calendar/>",
+		propDisplayName:           escapeXML(calendar.Category.Name),
+		propCurrentUserPrincipal:  davHref(caldavRoot),
+		propCurrentUserPrivileges: "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>",
+		propSupportedReports: "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>",
+		propSupportedComponents: `<c:comp name="VTODO"/>`,
+		propCTag:                ctag,
+		propCalendarColor:       escapeXML(calendar.Category.Color),
+	}}
+}
+
+// objectResponse lists the properties of an object, with its calendar data
+// only when asked for
+func objectResponse(calendarID uint, object *models.CalDAVObject, withData bool) davResponse {
+	props := davProps{
+		propResourceType: "",
+		propETag:         escapeXML(versionETag(object.Todo.Version)),
+		propContentType:  "text/calendar; charset=utf-8; component=VTODO",
+		propLastModified: object.Todo.UpdatedAt.UTC().Format(http.TimeFormat),
+	}
+	if withData {
+		if data, err := calendarData(object); err == nil {
+			props[propCalendarData] = escapeXML(string(data))
+		}
+	}
+	return davResponse{href: objectHref(calendarID, object.Name), props: props}
+}
+
+func calendarData(object *models.CalDAVObject) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := ical.NewEncoder(&buf).Encode(services.CalDAVData(object)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func calendarHref(id uint) string {
+	return fmt.Sprintf("%s%d/", caldavHome, id)
+}
+
+func objectHref(calendarID uint, name string) string {
+	return calendarHref(calendarID) + url.PathEscape(name)
+}
+
+func containsName(names []xml.Name, name xml.Name) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func caldavMethodNotAllowed(c *gin.Context) {
+	c.Header("Allow", strings.Join(CalDAVMethods, ", "))
+	c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "method not allowed on this resource"})
+}
+
+func respondDAVBodyError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidDAVBody.Error()})
+}
+
+func respondCalDAVError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCalDAVCalendarNotFound), errors.Is(err, services.ErrCalDAVObjectNotFound),
+		errors.Is(err, services.ErrTodoNotFound):
+		respondError(c, http.StatusNotFound, err)
+	case errors.Is(err, services.ErrVersionConflict), errors.Is(err, services.ErrCalDAVObjectExists):
+		respondError(c, http.StatusPreconditionFailed, err)
+	case errors.Is(err, services.ErrCalDAVUIDConflict), errors.Is(err, services.ErrCalDAVReservedName):
+		respondError(c, http.StatusConflict, err)
+	case errors.Is(err, services.ErrCalDAVInvalidData):
+		respondError(c, http.StatusBadRequest, err)
+	default:
+		respondError(c, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bufio"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/ical"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+type CalendarHandler struct {
+	service services.CalendarService
+	todos   services.TodoService
+}
+
+func NewCalendarHandler(service services.CalendarService, todos services.TodoService) *CalendarHandler {
+	return &CalendarHandler{service: service, todos: todos}
+}
+
+// CreateToken issues a feed token for the current user, replacing any
+// previous one. The token is only shown in this response
+func (h *CalendarHandler) CreateToken(c *gin.Context) {
+	user := currentUser(c)
+	token, err := h.service.CreateToken(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	feedURL := url.URL{
+		Scheme:   scheme,
+		Host:     c.Request.Host,
+		Path:     "/api/calendar.ics",
+		RawQuery: url.Values{"token": {token}}.Encode(),
+	}
+	c.JSON(http.StatusCreated, models.CalendarTokenResponse{
+		User:    user,
+		Token:   token,
+		FeedURL: feedURL.String(),
+	})
+}
+
+// RevokeToken revokes the current user's feed token
+func (h *CalendarHandler) RevokeToken(c *gin.Context) {
+	if err := h.service.RevokeToken(currentUser(c)); err != nil {
+		if errors.Is(err, services.ErrCalendarTokenNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "calendar token revoked successfully"})
+}
+
+// Feed serves the todos with a due date as an iCalendar feed, authorized by
+// the token query parameter since calendar apps cannot send headers. The
+// list filters of GET /api/todos apply, and events=true adds a VEVENT on
+// each due date
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	if _, err := h.service.Authenticate(c.Query("token")); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	filter := parseTodoFilter(c)
+	hasDueDate := true
+	filter.HasDueDate = &hasDueDate
+	events, _ := strconv.ParseBool(c.Query("events"))
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", "inline; filename=\"todos.ics\"")
+	w := bufio.NewWriter(c.Writer)
+	enc := ical.NewEncoder(w)
+	enc.Begin("VCALENDAR")
+	enc.Property(ical.Property{Name: "VERSION", Value: "2.0"})
+	enc.Property(ical.Property{Name: "PRODID", Value: services.CalendarProductID})
+	enc.Property(ical.Property{Name: "CALSCALE", Value: "GREGORIAN"})
+	enc.Property(ical.Property{Name: "METHOD", Value: "PUBLISH"})
+	enc.Property(ical.Property{Name: "X-WR-CALNAME", Value: "Todos"})
+	enc.Property(ical.Property{Name: "REFRESH-INTERVAL", Params: map[string]string{"VALUE": "DURATION"}, Value: "PT1H"})
+
+	rows := 0
+	err := h.todos.Export(filter, func(todo *models.Todo) error {
+		enc.Encode(services.TodoVTODO(todo))
+		if events {
+			enc.Encode(services.TodoVEvent(todo))
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return enc.Err()
+	})
+	if err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			c.Writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		// The feed has started; all we can do is cut it short
+		c.Error(err)
+		return
+	}
+	enc.End("VCALENDAR")
+	w.Flush()
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -10,24 +11,25 @@ import (
 )
 
 type CategoryHandler struct {
-	service services.CategoryService
+	service        services.CategoryService
+	requireIfMatch bool
 }
 
-func NewCategoryHandler(service services.CategoryService) *CategoryHandler {
-	return &CategoryHandler{service: service}
+func NewCategoryHandler(service services.CategoryService, requireIfMatch bool) *CategoryHandler {
+	return &CategoryHandler{service: service, requireIfMatch: requireIfMatch}
 }
 
 // Create creates a new category
 func (h *CategoryHandler) Create(c *gin.Context) {
 	var req models.CreateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, bindingError(err))
 		return
 	}
 
 	category, err := h.service.Create(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -36,6 +38,16 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 
 // GetAll returns all categories
 func (h *CategoryHandler) GetAll(c *gin.Context) {
+	state, err := h.service.ListState()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if notModified(c, listETag(c, state), state.LastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	categories, err := h.service.GetAll()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -59,6 +71,7 @@ func (h *CategoryHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", versionETag(category.Version))
 	c.JSON(http.StatusOK, category)
 }
 
@@ -70,18 +83,55 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c, h.requireIfMatch)
+	if err != nil {
+		c.JSON(ifMatchStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
 	var req models.UpdateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, bindingError(err))
 		return
 	}
 
-	category, err := h.service.Update(uint(id), req)
+	category, err := h.service.Update(uint(id), req, version)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		h.respondWriteError(c, uint(id), err)
+		return
+	}
+
+	c.Header("ETag", versionETag(category.Version))
+	c.JSON(http.StatusOK, category)
+}
+
+// Patch applies a JSON merge patch to a category
+func (h *CategoryHandler) Patch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		return
+	}
+
+	version, err := ifMatchVersion(c, h.requireIfMatch)
+	if err != nil {
+		c.JSON(ifMatchStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	var req models.PatchCategoryRequest
+	if err := bindMergePatch(c, &req); err != nil {
+		respondError(c, patchStatus(err), bindingError(err))
+		return
+	}
+
+	category, err := h.service.Patch(uint(id), req, version)
+	if err != nil {
+		h.respondWriteError(c, uint(id), err)
 		return
 	}
 
+	c.Header("ETag", versionETag(category.Version))
 	c.JSON(http.StatusOK, category)
 }
 
@@ -93,10 +143,28 @@ func (h *CategoryHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	version, err := ifMatchVersion(c, h.requireIfMatch)
+	if err != nil {
+		c.JSON(ifMatchStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Delete(uint(id), version); err != nil {
+		h.respondWriteError(c, uint(id), err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "category deleted successfully"})
 }
+
+// respondWriteError reports a failed write, answering version conflicts with
+// the current category
+func (h *CategoryHandler) respondWriteError(c *gin.Context, id uint, err error) {
+	if errors.Is(err, services.ErrVersionConflict) {
+		if current, getErr := h.service.GetByID(id); getErr == nil {
+			respondPreconditionFailed(c, current.Version, current)
+			return
+		}
+	}
+	respondError(c, http.StatusNotFound, err)
+}
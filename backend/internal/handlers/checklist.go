@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+type ChecklistHandler struct {
+	service services.ChecklistService
+}
+
+func NewChecklistHandler(service services.ChecklistService) *ChecklistHandler {
+	return &ChecklistHandler{service: service}
+}
+
+// GetAll returns a todo's checklist in order
+func (h *ChecklistHandler) GetAll(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	items, err := h.service.List(uint(todoID))
+	if err != nil {
+		respondChecklistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// Create appends an item to a todo's checklist
+func (h *ChecklistHandler) Create(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	var req models.CreateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	item, err := h.service.Add(uint(todoID), req)
+	if err != nil {
+		respondChecklistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+// Update renames, checks or unchecks an item
+func (h *ChecklistHandler) Update(c *gin.Context) {
+	todoID, itemID, ok := checklistParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	item, err := h.service.Update(todoID, itemID, req)
+	if err != nil {
+		respondChecklistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// Move reorders an item relative to other items of the checklist
+func (h *ChecklistHandler) Move(c *gin.Context) {
+	todoID, itemID, ok := checklistParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.MoveChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	item, err := h.service.Move(todoID, itemID, req)
+	if err != nil {
+		respondChecklistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// Delete removes an item from a checklist
+func (h *ChecklistHandler) Delete(c *gin.Context) {
+	todoID, itemID, ok := checklistParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(todoID, itemID); err != nil {
+		respondChecklistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "checklist item deleted successfully"})
+}
+
+func checklistParams(c *gin.Context) (todoID, itemID uint, ok bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return 0, 0, false
+	}
+	iid, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid checklist item ID"})
+		return 0, 0, false
+	}
+	return uint(id), uint(iid), true
+}
+
+func respondChecklistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTodoNotFound), errors.Is(err, services.ErrChecklistItemNotFound):
+		respondError(c, http.StatusNotFound, err)
+	case errors.Is(err, services.ErrChecklistFull):
+		respondError(c, http.StatusConflict, err)
+	default:
+		respondError(c, http.StatusInternalServerError, err)
+	}
+}
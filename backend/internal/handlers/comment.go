@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+type CommentHandler struct {
+	service services.CommentService
+}
+
+func NewCommentHandler(service services.CommentService) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+// Create adds a comment to a todo
+func (h *CommentHandler) Create(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	comment, err := h.service.Create(uint(todoID), currentUser(c), req)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// GetAll returns a todo's comments, oldest first, with pagination
+func (h *CommentHandler) GetAll(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	response, err := h.service.List(uint(todoID), page, limit)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Update edits the body of a comment
+func (h *CommentHandler) Update(c *gin.Context) {
+	todoID, commentID, ok := commentParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	comment, err := h.service.Update(todoID, commentID, currentUser(c), req)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// Delete removes a comment
+func (h *CommentHandler) Delete(c *gin.Context) {
+	todoID, commentID, ok := commentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(todoID, commentID, currentUser(c)); err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "comment deleted successfully"})
+}
+
+func commentParams(c *gin.Context) (todoID, commentID uint, ok bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return 0, 0, false
+	}
+	cid, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment ID"})
+		return 0, 0, false
+	}
+	return uint(id), uint(cid), true
+}
+
+func respondCommentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTodoNotFound), errors.Is(err, services.ErrCommentNotFound):
+		respondError(c, http.StatusNotFound, err)
+	case errors.Is(err, services.ErrCommentForbidden):
+		respondError(c, http.StatusForbidden, err)
+	default:
+		respondError(c, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import "strings"
+
+// csvFormulaPrefixes start cells that spreadsheets run as formulas
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvText guards user text written to a CSV file meant for spreadsheets:
+// a cell that would run as a formula gets a leading ' so it is shown as
+// text instead
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+// GetBlockers returns the todos blocking a todo
+func (h *TodoHandler) GetBlockers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	todos, err := h.service.Blockers(uint(id))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, todos)
+}
+
+// GetBlocking returns the todos a todo is blocking
+func (h *TodoHandler) GetBlocking(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	todos, err := h.service.Blocking(uint(id))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, todos)
+}
+
+// AddBlocker marks a todo as blocked by another todo
+func (h *TodoHandler) AddBlocker(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	var req models.AddBlockerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	todos, err := h.service.AddBlocker(uint(id), req.BlockerID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, todos)
+}
+
+// RemoveBlocker unlinks a blocker from a todo
+func (h *TodoHandler) RemoveBlocker(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+	blockerID, err := strconv.ParseUint(c.Param("blockerId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid blocker ID"})
+		return
+	}
+
+	if err := h.service.RemoveBlocker(uint(id), uint(blockerID)); err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "blocker removed successfully"})
+}
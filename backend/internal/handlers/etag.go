@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+var (
+	errIfMatchRequired = errors.New("If-Match header is required for this request")
+	errInvalidIfMatch  = errors.New("If-Match header must be a version ETag or *")
+)
+
+// versionETag renders a resource version as a strong ETag
+func versionETag(version uint) string {
+	return strconv.Quote(strconv.FormatUint(uint64(version), 10))
+}
+
+// ifMatchVersion reads the version expected by the If-Match header. A nil
+// version means the request is unconditional, either because the header is
+// absent or because it is "*"
+func ifMatchVersion(c *gin.Context, required bool) (*uint, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		if required {
+			return nil, errIfMatchRequired
+		}
+		return nil, nil
+	}
+	if header == "*" {
+		return nil, nil
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return nil, errInvalidIfMatch
+	}
+	version, err := strconv.ParseUint(unquoted, 10, 32)
+	if err != nil {
+		return nil, errInvalidIfMatch
+	}
+	v := uint(version)
+	return &v, nil
+}
+
+// ifMatchStatus maps If-Match parsing errors to their HTTP status
+func ifMatchStatus(err error) int {
+	if errors.Is(err, errIfMatchRequired) {
+		return http.StatusPreconditionRequired
+	}
+	return http.StatusBadRequest
+}
+
+// respondPreconditionFailed answers a lost update with 412 and the current
+// representation so the client can merge and retry
+func respondPreconditionFailed(c *gin.Context, version uint, current interface{}) {
+	c.Header("ETag", versionETag(version))
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":   "resource was modified by another request",
+		"current": current,
+	})
+}
+
+// listETag derives a weak ETag for a list from its query parameters and the
+// state of the rows behind it
+func listETag(c *gin.Context, state models.ListState) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%d",
+		c.Request.URL.Query().Encode(), state.LastModified.UnixNano(), state.Count)))
+	return `W/"` + hex.EncodeToString(sum[:10]) + `"`
+}
+
+// notModified sets the caching validators on the response and reports
+// whether the client's cached copy is still current. If-None-Match takes
+// precedence over If-Modified-Since as required by RFC 7232
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if header := c.GetHeader("If-None-Match"); header != "" {
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if header := c.GetHeader("If-Modified-Since"); header != "" && !lastModified.IsZero() {
+		if since, err := http.ParseTime(header); err == nil {
+			return !lastModified.Truncate(time.Second).After(since)
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyMaxBody bounds the body hashed for a keyed request. The import
+// upload is the largest body an idempotent route accepts
+const idempotencyMaxBody = maxImportSize + multipartOverhead
+
+var errIdempotentBodyTooLarge = errors.New("request body is too large")
+
+// responseRecorder keeps a copy of the response body written by a handler
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency replays the stored response when a request is retried with the
+// same Idempotency-Key header and body. Requests without the header pass
+// through untouched. The body is read into memory to hash it, so it is
+// capped before the handler sees it
+func Idempotency(service services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, idempotencyMaxBody))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": errIdempotentBodyTooLarge.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// The query takes part too, so a dry run never replays as the real thing
+		hash := sha256.Sum256(append([]byte(c.Request.Method+" "+c.Request.URL.RequestURI()+"\n"), body...))
+		scope := c.FullPath()
+
+		record, err := service.Begin(scope, key, hex.EncodeToString(hash[:]))
+		switch {
+		case errors.Is(err, services.ErrIdempotencyKeyReused):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrIdempotencyInProgress):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrIdempotencyKeyTooLong):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if record.Completed() {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.StatusCode, "application/json; charset=utf-8", record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		// Release the key if the handler panics, so retries are not refused
+		// until the claim goes stale
+		defer func() {
+			if r := recover(); r != nil {
+				if err := service.Abandon(scope, key); err != nil {
+					log.Printf("Failed to release idempotency key %q: %v", key, err)
+				}
+				panic(r)
+			}
+		}()
+		c.Next()
+
+		// Server errors are not cached so the client can retry them
+		if recorder.Status() >= http.StatusInternalServerError {
+			err = service.Abandon(scope, key)
+		} else {
+			err = service.Complete(scope, key, recorder.Status(), recorder.body.Bytes())
+		}
+		if err != nil {
+			log.Printf("Failed to store idempotent response for key %q: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+var (
+	errUnsupportedPatchType = errors.New("patch requests must use " + mergePatchContentType)
+	errPatchNotObject       = errors.New("merge patch must be a JSON object")
+)
+
+// bindMergePatch decodes an RFC 7396 merge patch body into req. Plain
+// application/json is accepted too since most clients send it by default
+func bindMergePatch(c *gin.Context, req interface{}) error {
+	if contentType := c.GetHeader("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergePatchContentType && mediaType != "application/json") {
+			return errUnsupportedPatchType
+		}
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		return err
+	}
+	if body = bytes.TrimSpace(body); len(body) == 0 || body[0] != '{' {
+		return errPatchNotObject
+	}
+
+	return json.Unmarshal(body, req)
+}
+
+// patchStatus maps merge patch decoding errors to their HTTP status
+func patchStatus(err error) int {
+	if errors.Is(err, errUnsupportedPatchType) {
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+type SmartListHandler struct {
+	service services.SmartListService
+}
+
+func NewSmartListHandler(service services.SmartListService) *SmartListHandler {
+	return &SmartListHandler{service: service}
+}
+
+// Create saves a new smart list
+func (h *SmartListHandler) Create(c *gin.Context) {
+	var req models.SmartListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	list, err := h.service.Create(req)
+	if err != nil {
+		respondSmartListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, list)
+}
+
+// GetAll returns the built-in smart lists followed by the saved ones
+func (h *SmartListHandler) GetAll(c *gin.Context) {
+	lists, err := h.service.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, lists)
+}
+
+// GetByID returns a smart list by ID
+func (h *SmartListHandler) GetByID(c *gin.Context) {
+	id, ok := smartListID(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.service.GetByID(id)
+	if err != nil {
+		respondSmartListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// Update replaces a smart list
+func (h *SmartListHandler) Update(c *gin.Context) {
+	id, ok := smartListID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SmartListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	list, err := h.service.Update(id, req)
+	if err != nil {
+		respondSmartListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// Delete deletes a smart list
+func (h *SmartListHandler) Delete(c *gin.Context) {
+	id, ok := smartListID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		respondSmartListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "smart list deleted successfully"})
+}
+
+// Todos returns the todos matching a smart list, paginated like the todo
+// list
+func (h *SmartListHandler) Todos(c *gin.Context) {
+	id, ok := smartListID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	response, err := h.service.Todos(id, page, limit, c.Query("time_zone"))
+	if err != nil {
+		respondSmartListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func smartListID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid smart list ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondSmartListError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrSmartListNotFound):
+		respondError(c, http.StatusNotFound, err)
+	case errors.Is(err, services.ErrSmartListBuiltIn):
+		respondError(c, http.StatusConflict, err)
+	default:
+		respondError(c, http.StatusInternalServerError, err)
+	}
+}
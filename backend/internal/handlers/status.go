@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+type StatusHandler struct {
+	service services.StatusService
+}
+
+func NewStatusHandler(service services.StatusService) *StatusHandler {
+	return &StatusHandler{service: service}
+}
+
+// Create creates a new status
+func (h *StatusHandler) Create(c *gin.Context) {
+	var req models.CreateStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	status, err := h.service.Create(req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, status)
+}
+
+// GetAll returns all statuses in board order
+func (h *StatusHandler) GetAll(c *gin.Context) {
+	statuses, err := h.service.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, statuses)
+}
+
+// Update updates a status
+func (h *StatusHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status ID"})
+		return
+	}
+
+	var req models.UpdateStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	status, err := h.service.Update(uint(id), req)
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Delete deletes a status that has no todos
+func (h *StatusHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status ID"})
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "status deleted successfully"})
+}
+
+// Board returns todos grouped by status, accepting the todo list filters
+func (h *StatusHandler) Board(c *gin.Context) {
+	columns, err := h.service.Board(parseTodoFilter(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"columns": columns})
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+type TemplateHandler struct {
+	service services.TemplateService
+}
+
+func NewTemplateHandler(service services.TemplateService) *TemplateHandler {
+	return &TemplateHandler{service: service}
+}
+
+// Create creates a new template
+func (h *TemplateHandler) Create(c *gin.Context) {
+	var req models.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	template, err := h.service.Create(req)
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// GetAll returns all templates
+func (h *TemplateHandler) GetAll(c *gin.Context) {
+	templates, err := h.service.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetByID returns a template by ID
+func (h *TemplateHandler) GetByID(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.service.GetByID(id)
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// Update replaces a template
+func (h *TemplateHandler) Update(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	template, err := h.service.Update(id, req)
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// Delete deletes a template
+func (h *TemplateHandler) Delete(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "template deleted successfully"})
+}
+
+// Instantiate creates the todos of a template in one transaction
+func (h *TemplateHandler) Instantiate(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional: a template without variables needs none
+	var req models.InstantiateTemplateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, bindingError(err))
+			return
+		}
+	}
+
+	todos, err := h.service.Instantiate(id, req)
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, todos)
+}
+
+// SaveTodo saves an existing todo as a new template
+func (h *TemplateHandler) SaveTodo(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	var req models.SaveAsTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	template, err := h.service.SaveTodo(uint(todoID), req)
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func templateID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondTemplateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTemplateNotFound), errors.Is(err, services.ErrTodoNotFound):
+		respondError(c, http.StatusNotFound, err)
+	default:
+		respondError(c, http.StatusInternalServerError, err)
+	}
+}
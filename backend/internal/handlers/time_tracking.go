@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+var errInvalidReportDate = errors.New("from and to must be dates (YYYY-MM-DD) or RFC 3339 timestamps")
+
+type TimeTrackingHandler struct {
+	service services.TimeTrackingService
+}
+
+func NewTimeTrackingHandler(service services.TimeTrackingService) *TimeTrackingHandler {
+	return &TimeTrackingHandler{service: service}
+}
+
+// StartTimer starts the current user's timer on a todo
+func (h *TimeTrackingHandler) StartTimer(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	// The body is optional; it only carries a note
+	var req models.StartTimerRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, bindingError(err))
+			return
+		}
+	}
+
+	entry, err := h.service.Start(uint(todoID), currentUser(c), req)
+	if err != nil {
+		respondTimeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// StopTimer stops the current user's timer on a todo
+func (h *TimeTrackingHandler) StopTimer(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	entry, err := h.service.Stop(uint(todoID), currentUser(c))
+	if err != nil {
+		respondTimeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// RunningTimer returns the current user's running timer, or 204 when none
+// is running
+func (h *TimeTrackingHandler) RunningTimer(c *gin.Context) {
+	entry, err := h.service.Running(currentUser(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if entry == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// GetEntries lists the time tracked on a todo, newest first
+func (h *TimeTrackingHandler) GetEntries(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	entries, err := h.service.ListEntries(uint(todoID))
+	if err != nil {
+		respondTimeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// CreateEntry records time tracked manually
+func (h *TimeTrackingHandler) CreateEntry(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	var req models.CreateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	entry, err := h.service.AddEntry(uint(todoID), currentUser(c), req)
+	if err != nil {
+		respondTimeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// DeleteEntry removes a time entry
+func (h *TimeTrackingHandler) DeleteEntry(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+	entryID, err := strconv.ParseUint(c.Param("entryId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid time entry ID"})
+		return
+	}
+
+	if err := h.service.DeleteEntry(uint(todoID), uint(entryID), currentUser(c)); err != nil {
+		respondTimeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "time entry deleted successfully"})
+}
+
+// Report returns tracked totals per todo, category or day, as JSON or as a
+// CSV download with format=csv
+func (h *TimeTrackingHandler) Report(c *gin.Context) {
+	filter := models.TimeReportFilter{
+		GroupBy:  models.TimeReportGroup(c.Query("group_by")),
+		User:     c.Query("user"),
+		TimeZone: c.Query("time_zone"),
+	}
+	// Dates are read in time_zone; an invalid one is rejected by the service
+	loc, err := time.LoadLocation(filter.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		id, err := strconv.ParseUint(categoryID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+			return
+		}
+		catID := uint(id)
+		filter.CategoryID = &catID
+	}
+
+	if filter.From, err = parseReportTime(c.Query("from"), loc, false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.To, err = parseReportTime(c.Query("to"), loc, true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.Report(filter)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"time-report-%s.csv\"", report.GroupBy))
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{string(report.GroupBy), "label", "total_seconds", "hours", "estimate_minutes"})
+	for _, row := range report.Rows {
+		estimate := ""
+		if row.EstimateMinutes != nil {
+			estimate = strconv.Itoa(*row.EstimateMinutes)
+		}
+		w.Write([]string{
+			row.Key,
+			csvText(row.Label),
+			strconv.FormatInt(row.TotalSeconds, 10),
+			fmt.Sprintf("%.2f", float64(row.TotalSeconds)/3600),
+			estimate,
+		})
+	}
+	w.Write([]string{"total", "", strconv.FormatInt(report.TotalSeconds, 10), fmt.Sprintf("%.2f", float64(report.TotalSeconds)/3600), ""})
+	w.Flush()
+}
+
+// parseReportTime accepts a date, which starts at midnight in loc, or an
+// RFC 3339 timestamp. A date used as the upper bound covers that whole day
+func parseReportTime(value string, loc *time.Location, upper bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return nil, errInvalidReportDate
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+func respondTimeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTodoNotFound), errors.Is(err, services.ErrTimeEntryNotFound):
+		respondError(c, http.StatusNotFound, err)
+	case errors.Is(err, services.ErrTimerRunning), errors.Is(err, services.ErrTimerNotRunning):
+		respondError(c, http.StatusConflict, err)
+	case errors.Is(err, services.ErrTimeEntryForbidden):
+		respondError(c, http.StatusForbidden, err)
+	default:
+		respondError(c, http.StatusInternalServerError, err)
+	}
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/industrix-todo-app/backend/internal/models"
@@ -10,35 +13,87 @@ import (
 )
 
 type TodoHandler struct {
-	service services.TodoService
+	service        services.TodoService
+	requireIfMatch bool
 }
 
-func NewTodoHandler(service services.TodoService) *TodoHandler {
-	return &TodoHandler{service: service}
+func NewTodoHandler(service services.TodoService, requireIfMatch bool) *TodoHandler {
+	return &TodoHandler{service: service, requireIfMatch: requireIfMatch}
 }
 
 // Create creates a new todo
 func (h *TodoHandler) Create(c *gin.Context) {
 	var req models.CreateTodoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, bindingError(err))
 		return
 	}
 
 	todo, err := h.service.Create(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, todo)
 }
 
+// QuickAdd creates a todo from one line of text and returns what was
+// understood from it along with the todo
+func (h *TodoHandler) QuickAdd(c *gin.Context) {
+	var req models.QuickAddRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	response, err := h.service.QuickAdd(req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if response.DryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, response)
+}
+
 // GetAll returns all todos with pagination and filters
 func (h *TodoHandler) GetAll(c *gin.Context) {
+	filter := parseTodoFilter(c)
+
+	// Relative due windows, which queries may also use, move with the
+	// clock, so their results are not validated by the state of the todos
+	// alone
+	if filter.Due == "" && filter.Query == "" {
+		state, err := h.service.ListState(filter)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if notModified(c, listETag(c, state), state.LastModified) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	response, err := h.service.GetAll(filter)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseTodoFilter reads the list filters shared by every endpoint that
+// selects todos from the query string
+func parseTodoFilter(c *gin.Context) models.TodoFilter {
 	filter := models.TodoFilter{
 		Search:    c.Query("search"),
-		SortBy:    c.Query("sort_by"),
+		SortBy:    c.DefaultQuery("sort_by", c.Query("sort")),
 		SortOrder: c.Query("sort_order"),
 	}
 
@@ -75,13 +130,83 @@ func (h *TodoHandler) GetAll(c *gin.Context) {
 		filter.Priority = models.Priority(priority)
 	}
 
-	response, err := h.service.GetAll(filter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	// Parse status_id
+	if statusID := c.Query("status_id"); statusID != "" {
+		if id, err := strconv.ParseUint(statusID, 10, 32); err == nil {
+			stID := uint(id)
+			filter.StatusID = &stID
+		}
 	}
 
-	c.JSON(http.StatusOK, response)
+	// Parse custom field filters given as cf.<key>=<value>
+	for param, values := range c.Request.URL.Query() {
+		key, ok := strings.CutPrefix(param, "cf.")
+		if !ok || !services.IsValidCustomFieldKey(key) || len(values) == 0 {
+			continue
+		}
+		if filter.CustomFields == nil {
+			filter.CustomFields = map[string]string{}
+		}
+		filter.CustomFields[key] = values[0]
+	}
+
+	// Parse blocked
+	if blocked := c.Query("blocked"); blocked != "" {
+		if b, err := strconv.ParseBool(blocked); err == nil {
+			filter.Blocked = &b
+		}
+	}
+
+	// Parse has_due_date
+	if hasDueDate := c.Query("has_due_date"); hasDueDate != "" {
+		if b, err := strconv.ParseBool(hasDueDate); err == nil {
+			filter.HasDueDate = &b
+		}
+	}
+
+	// Parse time_zone, in which due windows and due dates are read
+	loc := time.UTC
+	if timeZone := c.Query("time_zone"); timeZone != "" {
+		if l, err := time.LoadLocation(timeZone); err == nil {
+			filter.TimeZone, loc = timeZone, l
+		}
+	}
+
+	// Parse due window
+	if due := models.DueWindow(c.Query("due")); services.IsValidDueWindow(due) {
+		filter.Due = due
+	}
+
+	// Parse due_from and due_to; a date includes the whole day
+	if dueFrom := c.Query("due_from"); dueFrom != "" {
+		if t, _, err := parseFilterTime(dueFrom, loc); err == nil {
+			filter.DueFrom = &t
+		}
+	}
+	if dueTo := c.Query("due_to"); dueTo != "" {
+		if t, isDate, err := parseFilterTime(dueTo, loc); err == nil {
+			if isDate {
+				t = t.AddDate(0, 0, 1)
+			}
+			filter.DueTo = &t
+		}
+	}
+
+	// Parse q, the query expression; it is checked by the service so the
+	// client learns where it went wrong
+	filter.Query = c.Query("q")
+
+	return filter
+}
+
+// parseFilterTime reads an RFC 3339 time or a YYYY-MM-DD date, which
+// stands for its midnight in loc
+func parseFilterTime(value string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
 }
 
 // GetByID returns a todo by ID
@@ -98,6 +223,11 @@ func (h *TodoHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	if notModified(c, versionETag(todo.Version), todo.UpdatedAt) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, todo)
 }
 
@@ -109,18 +239,55 @@ func (h *TodoHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c, h.requireIfMatch)
+	if err != nil {
+		c.JSON(ifMatchStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
 	var req models.UpdateTodoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, bindingError(err))
 		return
 	}
 
-	todo, err := h.service.Update(uint(id), req)
+	todo, err := h.service.Update(uint(id), req, version)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		h.respondWriteError(c, uint(id), err)
+		return
+	}
+
+	c.Header("ETag", versionETag(todo.Version))
+	c.JSON(http.StatusOK, todo)
+}
+
+// Patch applies a JSON merge patch to a todo
+func (h *TodoHandler) Patch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	version, err := ifMatchVersion(c, h.requireIfMatch)
+	if err != nil {
+		c.JSON(ifMatchStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	var req models.PatchTodoRequest
+	if err := bindMergePatch(c, &req); err != nil {
+		respondError(c, patchStatus(err), bindingError(err))
+		return
+	}
+
+	todo, err := h.service.Patch(uint(id), req, version)
+	if err != nil {
+		h.respondWriteError(c, uint(id), err)
 		return
 	}
 
+	c.Header("ETag", versionETag(todo.Version))
 	c.JSON(http.StatusOK, todo)
 }
 
@@ -132,8 +299,14 @@ func (h *TodoHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	version, err := ifMatchVersion(c, h.requireIfMatch)
+	if err != nil {
+		c.JSON(ifMatchStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Delete(uint(id), version); err != nil {
+		h.respondWriteError(c, uint(id), err)
 		return
 	}
 
@@ -150,9 +323,66 @@ func (h *TodoHandler) ToggleComplete(c *gin.Context) {
 
 	todo, err := h.service.ToggleComplete(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		h.respondWriteError(c, uint(id), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, todo)
+}
+
+// respondWriteError reports a failed write, answering version conflicts with
+// the current todo
+func (h *TodoHandler) respondWriteError(c *gin.Context, id uint, err error) {
+	if errors.Is(err, services.ErrVersionConflict) {
+		if current, getErr := h.service.GetByID(id); getErr == nil {
+			respondPreconditionFailed(c, current.Version, current)
+			return
+		}
+	}
+	respondError(c, http.StatusNotFound, err)
+}
+
+// Bulk applies one action to many todos in a single transaction
+func (h *TodoHandler) Bulk(c *gin.Context) {
+	var req models.BulkTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	response, err := h.service.Bulk(req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	status := http.StatusOK
+	if response.RolledBack {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, response)
+}
+
+// Move places a todo before and/or after other todos in the manual order
+func (h *TodoHandler) Move(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo ID"})
+		return
+	}
+
+	var req models.MoveTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, bindingError(err))
+		return
+	}
+
+	todo, err := h.service.Move(uint(id), req)
+	if err != nil {
+		h.respondWriteError(c, uint(id), err)
 		return
 	}
 
+	c.Header("ETag", versionETag(todo.Version))
 	c.JSON(http.StatusOK, todo)
 }
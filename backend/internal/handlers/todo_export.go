@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+// exportFlushRows is how often a streamed export is flushed to the client
+const exportFlushRows = 100
+
+// todoCSVHeader names the exported columns. The import maps them back
+// without configuration
+var todoCSVHeader = []string{
+	"id", "title", "description", "completed", "priority", "due_date", "category",
+	"status", "tags", "estimate_minutes", "created_at", "updated_at",
+}
+
+// todoWriter writes exported todos in one format. Flush sends what has
+// been written so far and Close ends the export
+type todoWriter interface {
+	Write(todo *models.Todo) error
+	Flush() error
+	Close() error
+}
+
+// Export streams every todo matching the list filters as a download, as
+// CSV (format=csv, the default), todo.txt (format=todotxt) or a Markdown
+// task list (format=markdown) grouped by group_by
+func (h *TodoHandler) Export(c *gin.Context) {
+	var w todoWriter
+	switch c.DefaultQuery("format", "csv") {
+	case "csv":
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", "attachment; filename=\"todos.csv\"")
+		w = newCSVTodoWriter(c.Writer)
+	case "todotxt":
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		c.Header("Content-Disposition", "attachment; filename=\"todo.txt\"")
+		w = &todoTxtWriter{w: bufio.NewWriter(c.Writer)}
+	case "markdown":
+		document, err := services.NewTodoMarkdown(models.MarkdownGroup(c.Query("group_by")))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		c.Header("Content-Type", "text/markdown; charset=utf-8")
+		c.Header("Content-Disposition", "attachment; filename=\"todos.md\"")
+		w = &markdownTodoWriter{w: c.Writer, document: document}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv, todotxt or markdown"})
+		return
+	}
+	filter := parseTodoFilter(c)
+
+	rows := 0
+	err := h.service.Export(filter, func(todo *models.Todo) error {
+		if err := w.Write(todo); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			c.Writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		// The download has started; all we can do is cut it short
+		c.Error(err)
+		return
+	}
+	w.Close()
+}
+
+type csvTodoWriter struct {
+	w *csv.Writer
+}
+
+func newCSVTodoWriter(w io.Writer) *csvTodoWriter {
+	writer := csv.NewWriter(w)
+	writer.Write(todoCSVHeader)
+	return &csvTodoWriter{w: writer}
+}
+
+func (w *csvTodoWriter) Write(todo *models.Todo) error {
+	return w.w.Write(todoCSVRecord(todo))
+}
+
+func (w *csvTodoWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *csvTodoWriter) Close() error {
+	return w.Flush()
+}
+
+type todoTxtWriter struct {
+	w *bufio.Writer
+}
+
+func (w *todoTxtWriter) Write(todo *models.Todo) error {
+	_, err := w.w.WriteString(services.FormatTodoTxt(todo) + "\n")
+	return err
+}
+
+func (w *todoTxtWriter) Flush() error {
+	return w.w.Flush()
+}
+
+func (w *todoTxtWriter) Close() error {
+	return w.Flush()
+}
+
+// markdownTodoWriter holds todos until the export ends, since sections
+// are only complete once every todo has been read
+type markdownTodoWriter struct {
+	w        io.Writer
+	document *services.TodoMarkdown
+}
+
+func (w *markdownTodoWriter) Write(todo *models.Todo) error {
+	w.document.Add(todo)
+	return nil
+}
+
+func (w *markdownTodoWriter) Flush() error {
+	return nil
+}
+
+func (w *markdownTodoWriter) Close() error {
+	return w.document.Write(w.w)
+}
+
+func todoCSVRecord(todo *models.Todo) []string {
+	dueDate, category, status, estimate := "", "", "", ""
+	if todo.DueDate != nil {
+		dueDate = todo.DueDate.UTC().Format(time.RFC3339)
+	}
+	if todo.Category != nil {
+		category = todo.Category.Name
+	}
+	if todo.Status != nil {
+		status = todo.Status.Name
+	}
+	if todo.EstimateMinutes != nil {
+		estimate = strconv.Itoa(*todo.EstimateMinutes)
+	}
+	tags := make([]string, len(todo.Tags))
+	for i, tag := range todo.Tags {
+		tags[i] = tag.Name
+	}
+
+	return []string{
+		strconv.FormatUint(uint64(todo.ID), 10),
+		csvText(todo.Title),
+		csvText(todo.Description),
+		strconv.FormatBool(todo.Completed),
+		string(todo.Priority),
+		dueDate,
+		csvText(category),
+		csvText(status),
+		csvText(strings.Join(tags, ",")),
+		estimate,
+		todo.CreatedAt.UTC().Format(time.RFC3339),
+		todo.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
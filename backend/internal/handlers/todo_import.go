@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+// maxImportSize bounds the size of an imported file
+const maxImportSize = 10 << 20
+
+var (
+	errImportFileEmpty      = errors.New("the file is empty")
+	errImportFileTooLarge   = errors.New("imported files must be at most 10 MB")
+	errImportMappingInvalid = errors.New("mapping must be a JSON object of column names to fields")
+)
+
+// Import creates todos from a CSV (format=csv, the default), todo.txt
+// (format=todotxt) or iCalendar (format=ics) file, or from an export of
+// Todoist, Trello or Microsoft To Do (format=todoist, trello or mstodo),
+// sent either as the multipart field "file" or as the request body. For
+// CSV the optional mapping (JSON, as a query parameter or form field)
+// assigns columns to fields. Todoist CSV files do not name their project,
+// so their todos go to the category parameter, which defaults to the name
+// of the uploaded file. dry_run=true only reports what would be imported
+func (h *TodoHandler) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize+multipartOverhead)
+
+	var body io.Reader = c.Request.Body
+	format := c.DefaultQuery("format", "csv")
+	mappingJSON := c.Query("mapping")
+	category := c.Query("category")
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, header, err := c.Request.FormFile("file")
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errImportFileTooLarge.Error()})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": "multipart field \"file\" is required"})
+			return
+		}
+		defer file.Close()
+		body = file
+		if mapping := c.Request.FormValue("mapping"); mapping != "" {
+			mappingJSON = mapping
+		}
+		if value := c.Request.FormValue("dry_run"); value != "" {
+			dryRun, _ = strconv.ParseBool(value)
+		}
+		if value := c.Request.FormValue("format"); value != "" {
+			format = value
+		}
+		if value := c.Request.FormValue("category"); value != "" {
+			category = value
+		}
+		if category == "" {
+			category = strings.TrimSuffix(header.Filename, filepath.Ext(header.Filename))
+		}
+	}
+
+	var report *models.TodoImportReport
+	var err error
+	switch format {
+	case "csv":
+		report, err = h.importCSV(c, body, mappingJSON, dryRun)
+	case "todotxt":
+		report, err = h.importTodoTxt(c, body, dryRun)
+	case "ics":
+		report, err = h.importCalendar(c, body, dryRun)
+	case models.ImportSourceTodoist, models.ImportSourceTrello, models.ImportSourceMicrosoftTodo:
+		report, err = h.importExternal(c, body, format, category, dryRun)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv, todotxt, ics, todoist, trello or mstodo"})
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	switch {
+	case report.Committed:
+		c.JSON(http.StatusCreated, report)
+	case report.Invalid > 0 && !report.DryRun:
+		c.JSON(http.StatusUnprocessableEntity, report)
+	default:
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// importCSV imports a CSV file. Errors are responded to before being
+// returned
+func (h *TodoHandler) importCSV(c *gin.Context, body io.Reader, mappingJSON string, dryRun bool) (*models.TodoImportReport, error) {
+	var mapping map[string]string
+	if mappingJSON != "" {
+		if err := json.Unmarshal([]byte(mappingJSON), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errImportMappingInvalid.Error()})
+			return nil, err
+		}
+	}
+
+	header, records, err := readImportCSV(body)
+	if err != nil {
+		respondImportReadError(c, err)
+		return nil, err
+	}
+
+	report, err := h.service.Import(models.TodoImportRequest{
+		Header:  header,
+		Records: records,
+		Mapping: mapping,
+		DryRun:  dryRun,
+	})
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return nil, err
+	}
+	return report, nil
+}
+
+// importTodoTxt imports a todo.txt file. Errors are responded to before
+// being returned
+func (h *TodoHandler) importTodoTxt(c *gin.Context, body io.Reader, dryRun bool) (*models.TodoImportReport, error) {
+	lines, err := readImportLines(body)
+	if err != nil {
+		respondImportReadError(c, err)
+		return nil, err
+	}
+
+	report, err := h.service.ImportTodoTxt(models.TodoTxtImportRequest{Lines: lines, DryRun: dryRun})
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return nil, err
+	}
+	return report, nil
+}
+
+// importCalendar imports the VTODOs of an iCalendar file. Errors are
+// responded to before being returned
+func (h *TodoHandler) importCalendar(c *gin.Context, body io.Reader, dryRun bool) (*models.TodoImportReport, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		respondImportReadError(c, err)
+		return nil, err
+	}
+
+	report, err := h.service.ImportCalendar(models.CalendarImportRequest{Data: data, DryRun: dryRun})
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return nil, err
+	}
+	return report, nil
+}
+
+// importExternal imports a file exported by another app. Errors are
+// responded to before being returned
+func (h *TodoHandler) importExternal(c *gin.Context, body io.Reader, source, category string, dryRun bool) (*models.TodoImportReport, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		respondImportReadError(c, err)
+		return nil, err
+	}
+
+	report, err := h.service.ImportExternal(models.ExternalImportRequest{
+		Source:   source,
+		Data:     data,
+		Category: strings.TrimSpace(category),
+		DryRun:   dryRun,
+	})
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return nil, err
+	}
+	return report, nil
+}
+
+func respondImportReadError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errImportFileTooLarge.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// readImportLines returns the lines of a text file
+func readImportLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportSize)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errImportFileEmpty
+	}
+	lines[0] = strings.TrimPrefix(lines[0], "\ufeff")
+	return lines, nil
+}
+
+// readImportCSV returns the header and records of a CSV file. Rows may have
+// fewer or more fields than the header
+func readImportCSV(r io.Reader) ([]string, [][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errImportFileEmpty
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	// Spreadsheet apps often start UTF-8 files with a byte order mark
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, records, nil
+}
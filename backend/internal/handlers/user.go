@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// anonymousUser is reported for requests that do not identify their sender
+	anonymousUser = "anonymous"
+	// maxUserLength is the length in characters user names are cut to
+	maxUserLength = 100
+)
+
+// currentUser returns the name the client sent in the X-User header. There
+// are no accounts yet, so the header is trusted as is
+func currentUser(c *gin.Context) string {
+	if user := strings.TrimSpace(c.GetHeader("X-User")); user != "" {
+		// Cut by characters so a multi-byte character is never split
+		if runes := []rune(user); len(runes) > maxUserLength {
+			user = strings.TrimSpace(string(runes[:maxUserLength]))
+		}
+		return user
+	}
+	return anonymousUser
+}
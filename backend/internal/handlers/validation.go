@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/industrix-todo-app/backend/internal/services"
+)
+
+func init() {
+	// Report binding failures with the JSON field names clients send
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// bindingError converts a request binding failure into the same field-level
+// shape the services use, falling back to the raw error when the body could
+// not be decoded at all
+func bindingError(err error) error {
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		verr := &services.ValidationError{}
+		for _, fe := range fieldErrs {
+			verr.Fields = append(verr.Fields, services.FieldError{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Message: bindingMessage(fe),
+			})
+		}
+		return verr
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &services.ValidationError{Fields: []services.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s must be a %s", typeErr.Field, typeErr.Type.Kind()),
+		}}}
+	}
+
+	return err
+}
+
+func bindingMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "min":
+		return fmt.Sprintf("%s must be at least %s characters", fe.Field(), fe.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s characters", fe.Field(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", fe.Field(), strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "hexcolor":
+		return fmt.Sprintf("%s must be a hex color such as #3B82F6", fe.Field())
+	default:
+		return fmt.Sprintf("%s is invalid", fe.Field())
+	}
+}
+
+// respondError writes err with the given status, as a 400 listing every
+// invalid field when err is a validation error, as a 400 pointing at the
+// offending position when err is a query error, or as a 409 when err is a
+// workflow conflict
+func respondError(c *gin.Context, status int, err error) {
+	var verr *services.ValidationError
+	if errors.As(err, &verr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validation failed", "fields": verr.Fields})
+		return
+	}
+	var qerr *services.QueryError
+	if errors.As(err, &qerr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": qerr.Error(), "position": qerr.Position})
+		return
+	}
+	if errors.Is(err, services.ErrWIPLimitReached) || errors.Is(err, services.ErrStatusInUse) ||
+		errors.Is(err, services.ErrTodoBlocked) || errors.Is(err, services.ErrDependencyCycle) {
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// XML namespaces of the WebDAV properties we serve
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+	nsICal   = "http://apple.com/ns/ical/"
+)
+
+// davPrefixes are declared on every multistatus; properties in other
+// namespaces declare theirs inline
+var davPrefixes = map[string]string{
+	nsDAV:    "d",
+	nsCalDAV: "c",
+	nsCS:     "cs",
+	nsICal:   "ic",
+}
+
+// davMaxBody bounds WebDAV request bodies
+const davMaxBody = 1 << 20
+
+// davNode is any element of a WebDAV request body
+type davNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []davNode  `xml:",any"`
+	Text     string     `xml:",chardata"`
+}
+
+// child returns the first child element with the given name
+func (n *davNode) child(space, local string) *davNode {
+	for i := range n.Children {
+		if n.Children[i].XMLName.Space == space && n.Children[i].XMLName.Local == local {
+			return &n.Children[i]
+		}
+	}
+	return nil
+}
+
+// children returns every child element with the given name
+func (n *davNode) children(space, local string) []*davNode {
+	var nodes []*davNode
+	for i := range n.Children {
+		if n.Children[i].XMLName.Space == space && n.Children[i].XMLName.Local == local {
+			nodes = append(nodes, &n.Children[i])
+		}
+	}
+	return nodes
+}
+
+func (n *davNode) attr(local string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// names lists the names of the child elements, as the prop element of a
+// PROPFIND or REPORT does
+func (n *davNode) names() []xml.Name {
+	names := make([]xml.Name, len(n.Children))
+	for i, child := range n.Children {
+		names[i] = child.XMLName
+	}
+	return names
+}
+
+// readDAVBody parses the XML body of a request, returning nil for an
+// empty one
+func readDAVBody(c *gin.Context) (*davNode, error) {
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, davMaxBody))
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	var node davNode
+	if err := xml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// davProps maps property names to their value as inner XML
+type davProps map[xml.Name]string
+
+// davResponse is one resource of a multistatus. Missing resources have a
+// status instead of properties
+type davResponse struct {
+	href   string
+	props  davProps
+	status int
+}
+
+// writeMultistatus answers a PROPFIND or REPORT with the requested
+// properties of each resource, or all of them when requested is nil.
+// Properties a resource does not have are reported as not found
+func writeMultistatus(c *gin.Context, responses []davResponse, requested []xml.Name) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	b.WriteString("<d:multistatus")
+	spaces := make([]string, 0, len(davPrefixes))
+	for space := range davPrefixes {
+		spaces = append(spaces, space)
+	}
+	sort.Strings(spaces)
+	for _, space := range spaces {
+		fmt.Fprintf(&b, ` xmlns:%s="%s"`, davPrefixes[space], space)
+	}
+	b.WriteString(">")
+
+	for _, response := range responses {
+		b.WriteString("<d:response><d:href>" + escapeXML(response.href) + "</d:href>")
+		if response.status != 0 {
+			b.WriteString("<d:status>" + davStatus(response.status) + "</d:status></d:response>")
+			continue
+		}
+
+		names := requested
+		if names == nil {
+			names = make([]xml.Name, 0, len(response.props))
+			for name := range response.props {
+				names = append(names, name)
+			}
+			sort.Slice(names, func(i, j int) bool {
+				if names[i].Space != names[j].Space {
+					return names[i].Space < names[j].Space
+				}
+				return names[i].Local < names[j].Local
+			})
+		}
+
+		var found, missing strings.Builder
+		for _, name := range names {
+			if value, ok := response.props[name]; ok {
+				found.WriteString(davElement(name, value))
+			} else {
+				missing.WriteString(davElement(name, ""))
+			}
+		}
+		if found.Len() > 0 {
+			b.WriteString("<d:propstat><d:prop>" + found.String() + "</d:prop><d:status>" + davStatus(http.StatusOK) + "</d:status></d:propstat>")
+		}
+		if missing.Len() > 0 {
+			b.WriteString("<d:propstat><d:prop>" + missing.String() + "</d:prop><d:status>" + davStatus(http.StatusNotFound) + "</d:status></d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	b.WriteString("</d:multistatus>\n")
+
+	c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", []byte(b.String()))
+}
+
+// davElement renders an element holding inner, which is already XML
+func davElement(name xml.Name, inner string) string {
+	tag, open := name.Local, name.Local
+	if prefix, ok := davPrefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+		open = tag
+	} else if name.Space != "" {
+		open = name.Local + ` xmlns="` + escapeXML(name.Space) + `"`
+	}
+	if inner == "" {
+		return "<" + open + "/>"
+	}
+	return "<" + open + ">" + inner + "</" + tag + ">"
+}
+
+func davHref(href string) string {
+	return "<d:href>" + escapeXML(href) + "</d:href>"
+}
+
+func davStatus(status int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", status, http.StatusText(status))
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,144 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxContentLine bounds an unfolded content line
+const maxContentLine = 1 << 20
+
+var ErrNoComponent = errors.New("ical: no component found")
+
+// Decode reads the first component of r, such as a VCALENDAR, with all its
+// children. Anything after its END line is ignored. Lines may end in CRLF
+// or LF
+func Decode(r io.Reader) (*Component, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxContentLine)
+
+	var stack []*Component
+	var current string
+	currentLine, lineNo := 0, 0
+
+	// handle processes one unfolded content line and reports the root once
+	// it has ended
+	handle := func(content string, line int) (*Component, error) {
+		if content == "" {
+			return nil, nil
+		}
+		p, err := parseContentLine(content)
+		if err != nil {
+			return nil, fmt.Errorf("ical: line %d: %w", line, err)
+		}
+
+		switch p.Name {
+		case "BEGIN":
+			c := &Component{Name: strings.ToUpper(p.Value), Line: line}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, c)
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(p.Value) {
+				return nil, fmt.Errorf("ical: line %d: unexpected END:%s", line, p.Value)
+			}
+			c := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return c, nil
+			}
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("ical: line %d: property %s outside of a component", line, p.Name)
+			}
+			c := stack[len(stack)-1]
+			c.Properties = append(c.Properties, p)
+		}
+		return nil, nil
+	}
+
+	for scanner.Scan() {
+		lineNo++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if lineNo == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if len(text) > 0 && (text[0] == ' ' || text[0] == '\t') {
+			current += text[1:]
+			if len(current) > maxContentLine {
+				return nil, fmt.Errorf("ical: line %d: content line too long", currentLine)
+			}
+			continue
+		}
+
+		root, err := handle(current, currentLine)
+		if err != nil || root != nil {
+			return root, err
+		}
+		current, currentLine = text, lineNo
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	root, err := handle(current, currentLine)
+	if err != nil || root != nil {
+		return root, err
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("ical: missing END:%s", stack[len(stack)-1].Name)
+	}
+	return nil, ErrNoComponent
+}
+
+// parseContentLine splits "NAME;PARAM=value:value" into a property
+func parseContentLine(line string) (Property, error) {
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return Property{}, fmt.Errorf("malformed content line %q", line)
+	}
+	p := Property{Name: strings.ToUpper(line[:end])}
+	rest := line[end:]
+
+	for rest[0] == ';' {
+		rest = rest[1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return Property{}, fmt.Errorf("malformed parameter in %s", p.Name)
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		// A value runs to the next unquoted ; or :
+		var value strings.Builder
+		quoted := false
+		i := 0
+		for ; i < len(rest); i++ {
+			ch := rest[i]
+			if ch == '"' {
+				quoted = !quoted
+				continue
+			}
+			if !quoted && (ch == ';' || ch == ':') {
+				break
+			}
+			value.WriteByte(ch)
+		}
+		if i == len(rest) {
+			return Property{}, fmt.Errorf("%s has no value", p.Name)
+		}
+		if p.Params == nil {
+			p.Params = map[string]string{}
+		}
+		p.Params[name] = value.String()
+		rest = rest[i:]
+	}
+
+	p.Value = rest[1:]
+	return p, nil
+}
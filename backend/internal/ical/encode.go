@@ -0,0 +1,101 @@
+package ical
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineLength is the longest content line allowed, in octets; longer
+// lines are folded
+const maxLineLength = 75
+
+// Encoder writes components, or a component piece by piece so long
+// calendars can be streamed. The first write error is kept and returned by
+// every later call
+type Encoder struct {
+	w   io.Writer
+	err error
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes a whole component with its children
+func (e *Encoder) Encode(c *Component) error {
+	e.Begin(c.Name)
+	for _, p := range c.Properties {
+		e.Property(p)
+	}
+	for _, child := range c.Children {
+		e.Encode(child)
+	}
+	return e.End(c.Name)
+}
+
+// Err returns the first write error
+func (e *Encoder) Err() error {
+	return e.err
+}
+
+func (e *Encoder) Begin(name string) error {
+	return e.line("BEGIN:" + name)
+}
+
+func (e *Encoder) End(name string) error {
+	return e.line("END:" + name)
+}
+
+func (e *Encoder) Property(p Property) error {
+	var b strings.Builder
+	b.WriteString(p.Name)
+
+	names := make([]string, 0, len(p.Params))
+	for name := range p.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString(";" + name + "=" + paramValue(p.Params[name]))
+	}
+
+	b.WriteString(":" + p.Value)
+	return e.line(b.String())
+}
+
+// line writes a content line, folding it into lines of at most
+// maxLineLength octets without splitting characters
+func (e *Encoder) line(s string) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	var b strings.Builder
+	limit := maxLineLength
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space
+		limit = maxLineLength - 1
+	}
+	b.WriteString(s + "\r\n")
+
+	_, e.err = io.WriteString(e.w, b.String())
+	return e.err
+}
+
+// paramValue quotes parameter values that contain separators. Values cannot
+// contain double quotes, so those are dropped
+func paramValue(value string) string {
+	value = strings.ReplaceAll(value, `"`, "")
+	if strings.ContainsAny(value, ":;,") {
+		return `"` + value + `"`
+	}
+	return value
+}
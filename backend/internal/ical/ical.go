@@ -0,0 +1,140 @@
+// Package ical reads and writes the iCalendar format (RFC 5545). It only
+// knows the syntax: components, properties and their parameters, text
+// escaping and date values. What the properties mean is up to the caller
+package ical
+
+import (
+	"strings"
+	"time"
+)
+
+// Property is one content line. Value is kept as written; use Text to
+// unescape TEXT values
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Text returns the value of a TEXT property
+func (p Property) Text() string {
+	return UnescapeText(p.Value)
+}
+
+// Component is a BEGIN/END block such as VCALENDAR or VTODO. Line is where
+// it begins when it was decoded
+type Component struct {
+	Name       string
+	Line       int
+	Properties []Property
+	Children   []*Component
+}
+
+func NewComponent(name string) *Component {
+	return &Component{Name: name}
+}
+
+// Add appends a property with a value written as is
+func (c *Component) Add(name, value string) {
+	c.Properties = append(c.Properties, Property{Name: name, Value: value})
+}
+
+// AddText appends a TEXT property, escaping its value
+func (c *Component) AddText(name, text string) {
+	c.Add(name, EscapeText(text))
+}
+
+// AddTime appends a DATE-TIME property in UTC, or when dateOnly a DATE
+// property holding the UTC date of t
+func (c *Component) AddTime(name string, t time.Time, dateOnly bool) {
+	if dateOnly {
+		c.Properties = append(c.Properties, Property{
+			Name:   name,
+			Params: map[string]string{"VALUE": "DATE"},
+			Value:  t.UTC().Format(dateFormat),
+		})
+		return
+	}
+	c.Add(name, FormatDateTime(t))
+}
+
+// Get returns the first property with the given name
+func (c *Component) Get(name string) (Property, bool) {
+	for _, p := range c.Properties {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Property{}, false
+}
+
+// Text returns the unescaped value of the first property with the given
+// name, or "" when there is none
+func (c *Component) Text(name string) string {
+	p, _ := c.Get(name)
+	return p.Text()
+}
+
+// All returns every property with the given name
+func (c *Component) All(name string) []Property {
+	var props []Property
+	for _, p := range c.Properties {
+		if p.Name == name {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// Components returns the children with the given name
+func (c *Component) Components(name string) []*Component {
+	var children []*Component
+	for _, child := range c.Children {
+		if child.Name == name {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+var (
+	textEscaper   = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	textUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+)
+
+func EscapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+func UnescapeText(value string) string {
+	return textUnescaper.Replace(value)
+}
+
+// SplitText splits a list of TEXT values, such as CATEGORIES, at the
+// commas that are not escaped, and unescapes each
+func SplitText(value string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, UnescapeText(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, UnescapeText(value[start:]))
+}
+
+// Set replaces the value of the first property with the given name, or
+// appends the property when there is none
+func (c *Component) Set(name, value string) {
+	for i := range c.Properties {
+		if c.Properties[i].Name == name {
+			c.Properties[i] = Property{Name: name, Value: value}
+			return
+		}
+	}
+	c.Add(name, value)
+}
@@ -0,0 +1,47 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405"
+)
+
+// FormatDateTime writes t as a UTC DATE-TIME
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat) + "Z"
+}
+
+// ParseTime reads a DATE or DATE-TIME property. Dates are midnight UTC and
+// reported as dateOnly. Times are UTC when they end in Z, in the zone named
+// by TZID when there is one, and otherwise in loc
+func ParseTime(p Property, loc *time.Location) (t time.Time, dateOnly bool, err error) {
+	value := p.Value
+	if strings.EqualFold(p.Params["VALUE"], "DATE") || len(value) == len(dateFormat) {
+		t, err = time.Parse(dateFormat, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%s: invalid date %q", p.Name, value)
+		}
+		return t, true, nil
+	}
+
+	if len(value) == len(dateTimeFormat)+1 && value[len(value)-1] == 'Z' {
+		t, err = time.Parse(dateTimeFormat, value[:len(value)-1])
+	} else {
+		if tzid := p.Params["TZID"]; tzid != "" {
+			loc, err = time.LoadLocation(tzid)
+			if err != nil {
+				return time.Time{}, false, fmt.Errorf("%s: unknown time zone %q", p.Name, tzid)
+			}
+		}
+		t, err = time.ParseInLocation(dateTimeFormat, value, loc)
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%s: invalid date-time %q", p.Name, value)
+	}
+	return t, false, nil
+}
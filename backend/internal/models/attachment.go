@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// Attachment describes a file uploaded to a todo. The bytes live in blob
+// storage under StorageKey
+type Attachment struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TodoID      uint      `gorm:"not null;index" json:"todo_id"`
+	FileName    string    `gorm:"size:255;not null" json:"file_name"`
+	ContentType string    `gorm:"size:255;not null" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	StorageKey  string    `gorm:"size:255;not null;uniqueIndex" json:"-"`
+	UploadedBy  string    `gorm:"size:100;not null" json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
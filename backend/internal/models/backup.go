@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+)
+
+// BackupFormat identifies backup files. BackupVersion is bumped whenever
+// their layout changes; older versions stay restorable
+const (
+	BackupFormat  = "industrix-todo-backup"
+	BackupVersion = 2
+)
+
+type RestoreMode string
+
+const (
+	// RestoreMerge adds the backup to the existing data, skipping duplicates
+	RestoreMerge RestoreMode = "merge"
+	// RestoreReplace deletes all existing data before restoring
+	RestoreReplace RestoreMode = "replace"
+)
+
+// Backup is a full copy of the data. IDs only link records within the
+// backup; restoring assigns new ones. Attachment files and the built-in
+// smart lists are not included. SmartLists was added in version 2
+type Backup struct {
+	Format         string           `json:"format"`
+	Version        int              `json:"version"`
+	ExportedAt     time.Time        `json:"exported_at"`
+	Categories     []Category       `json:"categories"`
+	Statuses       []Status         `json:"statuses"`
+	Todos          []BackupTodo     `json:"todos"`
+	Dependencies   []TodoDependency `json:"dependencies"`
+	Comments       []Comment        `json:"comments"`
+	ChecklistItems []ChecklistItem  `json:"checklist_items"`
+	TimeEntries    []TimeEntry      `json:"time_entries"`
+	Templates      []Template       `json:"templates"`
+	SmartLists     []SmartList      `json:"smart_lists"`
+}
+
+// BackupTodo is a todo as stored in a backup, with its tags by name and
+// without computed fields
+type BackupTodo struct {
+	ID              uint              `json:"id"`
+	Title           string            `json:"title"`
+	Description     string            `json:"description"`
+	Completed       bool              `json:"completed"`
+	Priority        Priority          `json:"priority"`
+	DueDate         *time.Time        `json:"due_date,omitempty"`
+	CategoryID      *uint             `json:"category_id,omitempty"`
+	StatusID        *uint             `json:"status_id,omitempty"`
+	Tags            []string          `json:"tags,omitempty"`
+	Position        string            `json:"position"`
+	EstimateMinutes *int              `json:"estimate_minutes,omitempty"`
+	CustomFields    CustomFieldValues `json:"custom_fields,omitempty"`
+	Recurrence      string            `json:"recurrence,omitempty"`
+	Version         uint              `json:"version"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// RestoreCount tells how many records of a kind were restored and how many
+// were skipped as duplicates
+type RestoreCount struct {
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+}
+
+type RestoreReport struct {
+	Mode           RestoreMode  `json:"mode"`
+	Categories     RestoreCount `json:"categories"`
+	Statuses       RestoreCount `json:"statuses"`
+	Todos          RestoreCount `json:"todos"`
+	Dependencies   RestoreCount `json:"dependencies"`
+	Comments       RestoreCount `json:"comments"`
+	ChecklistItems RestoreCount `json:"checklist_items"`
+	TimeEntries    RestoreCount `json:"time_entries"`
+	Templates      RestoreCount `json:"templates"`
+	SmartLists     RestoreCount `json:"smart_lists"`
+}
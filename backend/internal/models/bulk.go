@@ -0,0 +1,46 @@
+package models
+
+type BulkAction string
+
+const (
+	BulkActionComplete       BulkAction = "complete"
+	BulkActionUncomplete     BulkAction = "uncomplete"
+	BulkActionDelete         BulkAction = "delete"
+	BulkActionSetPriority    BulkAction = "set_priority"
+	BulkActionMoveToCategory BulkAction = "move_to_category"
+	BulkActionAddTag         BulkAction = "add_tag"
+	BulkActionRemoveTag      BulkAction = "remove_tag"
+)
+
+// BulkTodoRequest applies one action to the todos selected either by IDs or
+// by Filter. With AllOrNothing set, any failing item rolls back the batch
+type BulkTodoRequest struct {
+	IDs          []uint      `json:"ids"`
+	Filter       *TodoFilter `json:"filter"`
+	Action       BulkAction  `json:"action" binding:"required"`
+	Priority     Priority    `json:"priority"`
+	CategoryID   *uint       `json:"category_id"`
+	Tag          string      `json:"tag"`
+	AllOrNothing bool        `json:"all_or_nothing"`
+}
+
+const (
+	BulkStatusOK         = "ok"
+	BulkStatusFailed     = "failed"
+	BulkStatusRolledBack = "rolled_back"
+	BulkStatusSkipped    = "skipped"
+)
+
+type BulkItemResult struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkTodoResponse struct {
+	Action     BulkAction       `json:"action"`
+	Results    []BulkItemResult `json:"results"`
+	Succeeded  int              `json:"succeeded"`
+	Failed     int              `json:"failed"`
+	RolledBack bool             `json:"rolled_back"`
+}
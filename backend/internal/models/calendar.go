@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// CalendarToken lets a user subscribe to the calendar feed from apps that
+// cannot send headers. Only a hash of the token is stored
+type CalendarToken struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	UserName  string    `gorm:"size:100;not null;uniqueIndex" json:"user"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CalendarTokenResponse is returned once, when a token is created
+type CalendarTokenResponse struct {
+	User    string `json:"user"`
+	Token   string `json:"token"`
+	FeedURL string `json:"feed_url"`
+}
+
+// CalendarImportRequest carries an uploaded iCalendar file
+type CalendarImportRequest struct {
+	Data   []byte
+	DryRun bool
+}
+
+// CalendarResource remembers the name and UID a CalDAV client gave a todo
+// it created, so the client finds the todo under them again. Todos without
+// one are served as todo-<id>.ics
+type CalendarResource struct {
+	TodoID uint   `gorm:"primaryKey" json:"todo_id"`
+	Name   string `gorm:"size:255;not null;uniqueIndex" json:"name"`
+	UID    string `gorm:"size:255;not null;uniqueIndex" json:"uid"`
+}
+
+// CalDAVCalendar is a category served as a CalDAV calendar collection.
+// State changes whenever one of its todos does
+type CalDAVCalendar struct {
+	Category Category
+	State    ListState
+}
+
+// CalDAVObject is a todo served as a CalDAV calendar object resource
+type CalDAVObject struct {
+	Name string
+	UID  string
+	Todo *Todo
+}
+
+// CalDAVQuery narrows the todos returned by a calendar-query REPORT. Todos
+// without a due date match any due range
+type CalDAVQuery struct {
+	Completed *bool
+	DueFrom   *time.Time
+	DueTo     *time.Time
+}
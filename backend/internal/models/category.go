@@ -5,20 +5,33 @@ import (
 )
 
 type Category struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Name      string    `gorm:"size:100;not null" json:"name"`
-	Color     string    `gorm:"size:20;default:'#3B82F6'" json:"color"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Todos     []Todo    `gorm:"foreignKey:CategoryID" json:"todos,omitempty"`
+	ID           uint                   `gorm:"primaryKey" json:"id"`
+	Name         string                 `gorm:"size:100;not null" json:"name"`
+	Color        string                 `gorm:"size:20;default:'#3B82F6'" json:"color"`
+	CustomFields CustomFieldDefinitions `gorm:"type:jsonb;not null;default:'[]'" json:"custom_fields"`
+	Version      uint                   `gorm:"not null;default:1" json:"version"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+	Todos        []Todo                 `gorm:"foreignKey:CategoryID" json:"todos,omitempty"`
 }
 
 type CreateCategoryRequest struct {
-	Name  string `json:"name" binding:"required,min=1,max=100"`
-	Color string `json:"color"`
+	Name         string                 `json:"name" binding:"required,min=1,max=100"`
+	Color        string                 `json:"color" binding:"omitempty,hexcolor"`
+	CustomFields CustomFieldDefinitions `json:"custom_fields"`
 }
 
+// UpdateCategoryRequest replaces a whole category: an omitted color goes
+// back to the default and omitted custom fields are removed
 type UpdateCategoryRequest struct {
-	Name  string `json:"name" binding:"omitempty,min=1,max=100"`
-	Color string `json:"color"`
+	Name         string                 `json:"name" binding:"required,min=1,max=100"`
+	Color        string                 `json:"color" binding:"omitempty,hexcolor"`
+	CustomFields CustomFieldDefinitions `json:"custom_fields"`
+}
+
+// PatchCategoryRequest is a JSON merge patch for a category
+type PatchCategoryRequest struct {
+	Name         Nullable[string]                 `json:"name"`
+	Color        Nullable[string]                 `json:"color"`
+	CustomFields Nullable[CustomFieldDefinitions] `json:"custom_fields"`
 }
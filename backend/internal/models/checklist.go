@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// ChecklistItem is a lightweight step of a todo, ordered by its rank
+type ChecklistItem struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TodoID    uint      `gorm:"not null;index" json:"todo_id"`
+	Title     string    `gorm:"size:255;not null" json:"title"`
+	Checked   bool      `gorm:"not null;default:false" json:"checked"`
+	Position  string    `gorm:"type:varchar(255) collate \"C\";size:255;not null" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ChecklistProgress summarises a todo's checklist for list views
+type ChecklistProgress struct {
+	Total int64 `json:"total"`
+	Done  int64 `json:"done"`
+}
+
+type CreateChecklistItemRequest struct {
+	Title   string `json:"title" binding:"required,max=255"`
+	Checked bool   `json:"checked"`
+}
+
+type UpdateChecklistItemRequest struct {
+	Title   *string `json:"title" binding:"omitempty,min=1,max=255"`
+	Checked *bool   `json:"checked"`
+}
+
+// MoveChecklistItemRequest places an item right before and/or after other
+// items of the same checklist
+type MoveChecklistItemRequest struct {
+	Before *uint `json:"before"`
+	After  *uint `json:"after"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// CollectionChange records the last time rows were removed from a
+// collection, since deletions leave no updated_at behind to compare against
+type CollectionChange struct {
+	Collection string    `gorm:"primaryKey;size:50"`
+	ChangedAt  time.Time `gorm:"not null"`
+}
+
+// ListState summarizes a list cheaply enough to derive caching validators
+// without loading the rows themselves
+type ListState struct {
+	LastModified time.Time
+	Count        int64
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// Comment is a markdown note left on a todo by one of its collaborators
+type Comment struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TodoID    uint      `gorm:"not null;index" json:"todo_id"`
+	Author    string    `gorm:"size:100;not null" json:"author"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
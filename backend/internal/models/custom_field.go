@@ -0,0 +1,82 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type CustomFieldType string
+
+const (
+	CustomFieldText     CustomFieldType = "text"
+	CustomFieldNumber   CustomFieldType = "number"
+	CustomFieldDate     CustomFieldType = "date"
+	CustomFieldSelect   CustomFieldType = "select"
+	CustomFieldCheckbox CustomFieldType = "checkbox"
+)
+
+// CustomFieldDefinition declares a typed field that todos of a category can
+// fill in. Key is how values are addressed in todos, filters and sorting
+type CustomFieldDefinition struct {
+	Key      string          `json:"key"`
+	Name     string          `json:"name"`
+	Type     CustomFieldType `json:"type"`
+	Required bool            `json:"required,omitempty"`
+	Options  []string        `json:"options,omitempty"`
+}
+
+// CustomFieldDefinitions is stored as a JSONB array on the category
+type CustomFieldDefinitions []CustomFieldDefinition
+
+// Find returns the definition with the given key
+func (d CustomFieldDefinitions) Find(key string) (CustomFieldDefinition, bool) {
+	for _, def := range d {
+		if def.Key == key {
+			return def, true
+		}
+	}
+	return CustomFieldDefinition{}, false
+}
+
+func (d CustomFieldDefinitions) Value() (driver.Value, error) {
+	if d == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(d)
+	return string(b), err
+}
+
+func (d *CustomFieldDefinitions) Scan(src interface{}) error {
+	return scanJSON(src, d)
+}
+
+// CustomFieldValues maps field keys to values: strings for text, date
+// (YYYY-MM-DD) and select fields, numbers and booleans otherwise. It is
+// stored as a JSONB object on the todo
+type CustomFieldValues map[string]interface{}
+
+func (v CustomFieldValues) Value() (driver.Value, error) {
+	if v == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func (v *CustomFieldValues) Scan(src interface{}) error {
+	return scanJSON(src, v)
+}
+
+func scanJSON(src interface{}, dest interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, dest)
+	case string:
+		return json.Unmarshal([]byte(data), dest)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dest)
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// TodoDependency records that TodoID is blocked by BlockerID until the
+// blocker is completed
+type TodoDependency struct {
+	TodoID    uint      `gorm:"primaryKey" json:"todo_id"`
+	BlockerID uint      `gorm:"primaryKey;index" json:"blocker_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AddBlockerRequest struct {
+	BlockerID uint `json:"blocker_id" binding:"required"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// IdempotencyRecord stores the response to a request sent with an
+// Idempotency-Key so retries can be answered without repeating the work. A
+// zero StatusCode marks a request that is still being processed
+type IdempotencyRecord struct {
+	Scope          string `gorm:"primaryKey;size:100"`
+	IdempotencyKey string `gorm:"primaryKey;size:255"`
+	RequestHash    string `gorm:"size:64;not null"`
+	StatusCode     int    `gorm:"not null;default:0"`
+	ResponseBody   []byte `gorm:"type:bytea"`
+	CreatedAt      time.Time
+	ExpiresAt      time.Time `gorm:"not null;index"`
+}
+
+// Completed reports whether a response has been stored for the request
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
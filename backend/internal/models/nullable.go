@@ -0,0 +1,27 @@
+package models
+
+import (
+	"encoding/json"
+)
+
+// Nullable distinguishes a JSON field that is absent, explicitly null or set
+// to a value, as needed by RFC 7396 merge patches
+type Nullable[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
+
+// Present reports whether the field carries a non-null value
+func (n Nullable[T]) Present() bool {
+	return n.Set && !n.Null
+}
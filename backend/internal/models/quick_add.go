@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// QuickAddRequest is a todo written as one line of text, such as "Pay rent
+// tomorrow 9am !high #Personal every month". Dates are read in TimeZone,
+// an IANA zone name, or UTC when it is empty
+type QuickAddRequest struct {
+	Text     string `json:"text" binding:"required"`
+	TimeZone string `json:"time_zone"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+// QuickAddParse is what was understood from a quick-add line
+type QuickAddParse struct {
+	Title      string     `json:"title"`
+	DueDate    *time.Time `json:"due_date,omitempty"`
+	Priority   Priority   `json:"priority,omitempty"`
+	Category   string     `json:"category,omitempty"`
+	Tags       []string   `json:"tags"`
+	Recurrence string     `json:"recurrence,omitempty"`
+}
+
+// QuickAddResponse holds the parsed fields with the todo created from
+// them, which is left out on a dry run
+type QuickAddResponse struct {
+	Parsed          QuickAddParse `json:"parsed"`
+	CategoryID      *uint         `json:"category_id,omitempty"`
+	CategoryCreated bool          `json:"category_created"`
+	Todo            *Todo         `json:"todo,omitempty"`
+	DryRun          bool          `json:"dry_run"`
+}
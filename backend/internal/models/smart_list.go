@@ -0,0 +1,45 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// Keys of the built-in smart lists
+const (
+	SmartListToday    = "today"
+	SmartListUpcoming = "upcoming"
+	SmartListOverdue  = "overdue"
+	SmartListNoDate   = "no_date"
+)
+
+// SmartList is a saved todo filter. Built-in lists have a Key and cannot be
+// changed or deleted
+type SmartList struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	Key       string          `gorm:"size:20;not null;default:'';uniqueIndex:idx_smart_lists_key,where:key <> ''" json:"key,omitempty"`
+	Name      string          `gorm:"size:100;not null" json:"name"`
+	Filter    SmartListFilter `gorm:"type:jsonb;not null;default:'{}'" json:"filter"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// SmartListFilter is a todo filter stored as JSONB. Pagination is not
+// part of it
+type SmartListFilter TodoFilter
+
+func (f SmartListFilter) Value() (driver.Value, error) {
+	b, err := json.Marshal(f)
+	return string(b), err
+}
+
+func (f *SmartListFilter) Scan(src interface{}) error {
+	return scanJSON(src, f)
+}
+
+// SmartListRequest creates or replaces a smart list
+type SmartListRequest struct {
+	Name   string     `json:"name" binding:"required,min=1,max=100"`
+	Filter TodoFilter `json:"filter"`
+}
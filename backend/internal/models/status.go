@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// Status is a board column a todo moves through. Todos in a status marked
+// IsDone count as completed
+type Status struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:50;not null" json:"name"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	IsDone    bool      `gorm:"not null;default:false" json:"is_done"`
+	WIPLimit  *int      `json:"wip_limit,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateStatusRequest struct {
+	Name     string `json:"name" binding:"required,min=1,max=50"`
+	Position *int   `json:"position"`
+	IsDone   bool   `json:"is_done"`
+	WIPLimit *int   `json:"wip_limit" binding:"omitempty,min=1"`
+}
+
+// UpdateStatusRequest changes the given fields; a wip_limit of 0 removes the
+// limit
+type UpdateStatusRequest struct {
+	Name     string `json:"name" binding:"omitempty,min=1,max=50"`
+	Position *int   `json:"position"`
+	IsDone   *bool  `json:"is_done"`
+	WIPLimit *int   `json:"wip_limit" binding:"omitempty,min=0"`
+}
+
+// BoardColumn is one status on the board with its todos in manual order
+type BoardColumn struct {
+	Status Status `json:"status"`
+	Todos  []Todo `json:"todos"`
+	Count  int    `json:"count"`
+}
+
+// PositionScope names the column that groups todos into one ordered list
+type PositionScope string
+
+const (
+	PositionScopeCategory PositionScope = "category_id"
+	PositionScopeStatus   PositionScope = "status_id"
+)
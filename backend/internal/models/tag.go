@@ -0,0 +1,11 @@
+package models
+
+import (
+	"time"
+)
+
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:50;not null;uniqueIndex" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
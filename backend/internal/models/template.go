@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// Template is a reusable set of todos, such as an onboarding or release
+// checklist, that can be instantiated in one go
+type Template struct {
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	Name        string        `gorm:"size:100;not null" json:"name"`
+	Description string        `gorm:"type:text" json:"description"`
+	Items       TemplateItems `gorm:"type:jsonb;not null;default:'[]'" json:"items"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// TemplateItem describes one todo of a template. Titles, descriptions and
+// subtasks may reference variables as {{name}}; the due date is given in days
+// relative to the date the template is instantiated for
+type TemplateItem struct {
+	Title         string   `json:"title"`
+	Description   string   `json:"description,omitempty"`
+	Priority      Priority `json:"priority,omitempty"`
+	CategoryID    *uint    `json:"category_id,omitempty"`
+	DueOffsetDays *int     `json:"due_offset_days,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Subtasks      []string `json:"subtasks,omitempty"`
+}
+
+// TemplateItems is stored as a JSONB array on the template
+type TemplateItems []TemplateItem
+
+func (t TemplateItems) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(t)
+	return string(b), err
+}
+
+func (t *TemplateItems) Scan(src interface{}) error {
+	return scanJSON(src, t)
+}
+
+type CreateTemplateRequest struct {
+	Name        string        `json:"name" binding:"required,min=1,max=100"`
+	Description string        `json:"description"`
+	Items       TemplateItems `json:"items"`
+}
+
+// UpdateTemplateRequest replaces a template, items included
+type UpdateTemplateRequest struct {
+	Name        string        `json:"name" binding:"required,min=1,max=100"`
+	Description string        `json:"description"`
+	Items       TemplateItems `json:"items"`
+}
+
+// InstantiateTemplateRequest fills in a template's variables. BaseDate
+// (YYYY-MM-DD) anchors the due offsets and defaults to today
+type InstantiateTemplateRequest struct {
+	Variables map[string]string `json:"variables"`
+	BaseDate  string            `json:"base_date"`
+}
+
+// SaveAsTemplateRequest turns an existing todo into a single-item template
+type SaveAsTemplateRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Description string `json:"description"`
+}
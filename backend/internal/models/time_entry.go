@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+)
+
+// TimeEntry is a span of time a user spent on a todo. A running timer has
+// no EndedAt yet; each user can have at most one
+type TimeEntry struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	TodoID          uint       `gorm:"not null;index" json:"todo_id"`
+	UserName        string     `gorm:"size:100;not null;uniqueIndex:idx_time_entries_running,where:ended_at IS NULL" json:"user"`
+	StartedAt       time.Time  `gorm:"not null;index" json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	DurationSeconds int64      `gorm:"not null;default:0" json:"duration_seconds"`
+	Note            string     `gorm:"size:255" json:"note"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type CreateTimeEntryRequest struct {
+	StartedAt time.Time `json:"started_at" binding:"required"`
+	EndedAt   time.Time `json:"ended_at" binding:"required"`
+	Note      string    `json:"note" binding:"max=255"`
+}
+
+type StartTimerRequest struct {
+	Note string `json:"note" binding:"max=255"`
+}
+
+type TimeReportGroup string
+
+const (
+	TimeReportByTodo     TimeReportGroup = "todo"
+	TimeReportByCategory TimeReportGroup = "category"
+	TimeReportByDay      TimeReportGroup = "day"
+)
+
+// TimeReportFilter selects the finished entries that count towards a report.
+// From is inclusive and To exclusive, both matched against StartedAt.
+// TimeZone is the IANA zone whose days entries are grouped by
+type TimeReportFilter struct {
+	GroupBy    TimeReportGroup `json:"group_by"`
+	From       *time.Time      `json:"from,omitempty"`
+	To         *time.Time      `json:"to,omitempty"`
+	CategoryID *uint           `json:"category_id,omitempty"`
+	User       string          `json:"user,omitempty"`
+	TimeZone   string          `json:"time_zone,omitempty"`
+}
+
+// TimeReportRow is the tracked total of one group. Key identifies the
+// group (todo or category id, or the day as YYYY-MM-DD) and is empty for
+// time on todos without a category
+type TimeReportRow struct {
+	Key             string `json:"key"`
+	Label           string `json:"label"`
+	TotalSeconds    int64  `json:"total_seconds"`
+	EstimateMinutes *int   `json:"estimate_minutes,omitempty"`
+}
+
+type TimeReport struct {
+	TimeReportFilter
+	Rows         []TimeReportRow `json:"rows"`
+	TotalSeconds int64           `json:"total_seconds"`
+}
@@ -13,44 +13,119 @@ const (
 )
 
 type Todo struct {
-	ID          uint       `gorm:"primaryKey" json:"id"`
-	Title       string     `gorm:"size:255;not null" json:"title"`
-	Description string     `gorm:"type:text" json:"description"`
-	Completed   bool       `gorm:"default:false" json:"completed"`
-	Priority    Priority   `gorm:"size:20;default:'medium'" json:"priority"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
-	CategoryID  *uint      `json:"category_id,omitempty"`
-	Category    *Category  `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID              uint              `gorm:"primaryKey" json:"id"`
+	Title           string            `gorm:"size:255;not null" json:"title"`
+	Description     string            `gorm:"type:text" json:"description"`
+	Completed       bool              `gorm:"default:false" json:"completed"`
+	Priority        Priority          `gorm:"size:20;default:'medium'" json:"priority"`
+	DueDate         *time.Time        `json:"due_date,omitempty"`
+	CategoryID      *uint             `json:"category_id,omitempty"`
+	Category        *Category         `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	StatusID        *uint             `json:"status_id,omitempty"`
+	Status          *Status           `gorm:"foreignKey:StatusID" json:"status,omitempty"`
+	Tags            []Tag             `gorm:"many2many:todo_tags" json:"tags"`
+	Position        string            `gorm:"type:varchar(255) collate \"C\";size:255;not null;default:'';index" json:"position"`
+	EstimateMinutes *int              `json:"estimate_minutes,omitempty"`
+	CustomFields    CustomFieldValues `gorm:"type:jsonb;not null;default:'{}';index:idx_todos_custom_fields,type:gin" json:"custom_fields"`
+	Recurrence      string            `gorm:"size:100;not null;default:''" json:"recurrence,omitempty"`
+	IsBlocked       bool              `gorm:"-" json:"is_blocked"`
+	CommentCount    int64             `gorm:"-" json:"comment_count"`
+	Checklist       ChecklistProgress `gorm:"-" json:"checklist"`
+	Version         uint              `gorm:"not null;default:1" json:"version"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
 }
 
 type CreateTodoRequest struct {
-	Title       string     `json:"title" binding:"required,min=1,max=255"`
-	Description string     `json:"description"`
-	Priority    Priority   `json:"priority"`
-	DueDate     *time.Time `json:"due_date"`
-	CategoryID  *uint      `json:"category_id"`
+	Title           string            `json:"title" binding:"required,min=1,max=255"`
+	Description     string            `json:"description"`
+	Completed       bool              `json:"completed"`
+	Priority        Priority          `json:"priority" binding:"omitempty,oneof=high medium low"`
+	DueDate         *time.Time        `json:"due_date"`
+	CategoryID      *uint             `json:"category_id"`
+	StatusID        *uint             `json:"status_id"`
+	EstimateMinutes *int              `json:"estimate_minutes" binding:"omitempty,min=0"`
+	CustomFields    CustomFieldValues `json:"custom_fields"`
+	Recurrence      string            `json:"recurrence"`
+	Tags            []string          `json:"tags"`
+	Checklist       []string          `json:"checklist"`
 }
 
+// UpdateTodoRequest replaces a todo (PUT): fields left out are cleared or
+// reset to their defaults rather than kept
 type UpdateTodoRequest struct {
-	Title       string     `json:"title" binding:"omitempty,min=1,max=255"`
-	Description string     `json:"description"`
-	Completed   *bool      `json:"completed"`
-	Priority    Priority   `json:"priority"`
-	DueDate     *time.Time `json:"due_date"`
-	CategoryID  *uint      `json:"category_id"`
+	Title           string            `json:"title" binding:"required,min=1,max=255"`
+	Description     string            `json:"description"`
+	Completed       *bool             `json:"completed"`
+	Priority        Priority          `json:"priority" binding:"omitempty,oneof=high medium low"`
+	DueDate         *time.Time        `json:"due_date"`
+	CategoryID      *uint             `json:"category_id"`
+	StatusID        *uint             `json:"status_id"`
+	EstimateMinutes *int              `json:"estimate_minutes" binding:"omitempty,min=0"`
+	CustomFields    CustomFieldValues `json:"custom_fields"`
+	Recurrence      string            `json:"recurrence"`
 }
 
+// PatchTodoRequest is a JSON merge patch for a todo: absent fields are left
+// untouched and null clears a field back to its default
+type PatchTodoRequest struct {
+	Title           Nullable[string]            `json:"title"`
+	Description     Nullable[string]            `json:"description"`
+	Completed       Nullable[bool]              `json:"completed"`
+	Priority        Nullable[Priority]          `json:"priority"`
+	DueDate         Nullable[time.Time]         `json:"due_date"`
+	CategoryID      Nullable[uint]              `json:"category_id"`
+	StatusID        Nullable[uint]              `json:"status_id"`
+	EstimateMinutes Nullable[int]               `json:"estimate_minutes"`
+	CustomFields    Nullable[CustomFieldValues] `json:"custom_fields"`
+	Recurrence      Nullable[string]            `json:"recurrence"`
+}
+
+// MoveTodoRequest places a todo right before and/or after other todos of
+// the same category. With StatusID set the todo moves to that board column
+// first and the anchors are todos of that column instead
+type MoveTodoRequest struct {
+	Before   *uint `json:"before"`
+	After    *uint `json:"after"`
+	StatusID *uint `json:"status_id"`
+}
+
+// DueWindow is a due date range relative to the current day
+type DueWindow string
+
+const (
+	DueOverdue  DueWindow = "overdue"
+	DueToday    DueWindow = "today"
+	DueTomorrow DueWindow = "tomorrow"
+	// DueUpcoming covers the seven days after today
+	DueUpcoming DueWindow = "upcoming"
+)
+
 type TodoFilter struct {
-	Search     string
-	CategoryID *uint
-	Completed  *bool
-	Priority   Priority
-	Page       int
-	Limit      int
-	SortBy     string
-	SortOrder  string
+	Search     string   `json:"search,omitempty"`
+	CategoryID *uint    `json:"category_id,omitempty"`
+	Completed  *bool    `json:"completed,omitempty"`
+	Priority   Priority `json:"priority,omitempty"`
+	StatusID   *uint    `json:"status_id,omitempty"`
+	Blocked    *bool    `json:"blocked,omitempty"`
+	HasDueDate *bool    `json:"has_due_date,omitempty"`
+	// Due selects a due date window relative to today in TimeZone; DueFrom
+	// and DueTo bound due dates from (inclusive) and to (exclusive)
+	Due      DueWindow  `json:"due,omitempty"`
+	DueFrom  *time.Time `json:"due_from,omitempty"`
+	DueTo    *time.Time `json:"due_to,omitempty"`
+	TimeZone string     `json:"time_zone,omitempty"`
+	// Query is a q= expression, parsed into Expr before it reaches the
+	// repository
+	Query string    `json:"q,omitempty"`
+	Expr  TodoQuery `json:"-"`
+	// CustomFields matches todos whose custom field values equal the given
+	// text, keyed by field key
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+	Page         int               `json:"page,omitempty"`
+	Limit        int               `json:"limit,omitempty"`
+	SortBy       string            `json:"sort_by,omitempty"`
+	SortOrder    string            `json:"sort_order,omitempty"`
 }
 
 type PaginatedResponse struct {
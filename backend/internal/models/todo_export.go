@@ -0,0 +1,9 @@
+package models
+
+// MarkdownGroup selects how a Markdown export splits todos into sections
+type MarkdownGroup string
+
+const (
+	MarkdownByCategory MarkdownGroup = "category"
+	MarkdownByDue      MarkdownGroup = "due"
+)
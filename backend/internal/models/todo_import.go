@@ -0,0 +1,85 @@
+package models
+
+// Todo fields that imported columns can be mapped to
+const (
+	ImportFieldTitle           = "title"
+	ImportFieldDescription     = "description"
+	ImportFieldPriority        = "priority"
+	ImportFieldDueDate         = "due_date"
+	ImportFieldCategory        = "category"
+	ImportFieldCompleted       = "completed"
+	ImportFieldTags            = "tags"
+	ImportFieldEstimateMinutes = "estimate_minutes"
+)
+
+// TodoImportRequest carries parsed CSV records. Mapping assigns header
+// names to import fields; headers left out of it are matched by name, and
+// a header mapped to "" is ignored
+type TodoImportRequest struct {
+	Header  []string
+	Records [][]string
+	Mapping map[string]string
+	DryRun  bool
+}
+
+const (
+	ImportStatusValid   = "valid"
+	ImportStatusInvalid = "invalid"
+	ImportStatusCreated = "created"
+)
+
+// ImportError is a problem with one field of an imported row. Column names
+// the header the value came from, when there is one
+type ImportError struct {
+	Column  string `json:"column,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// TodoImportRowResult reports one record. Row is its line number in the
+// file, the header being line 1, or for JSON files the position of the
+// task in it. Title and Category preview what the record was read as
+type TodoImportRowResult struct {
+	Row      int           `json:"row"`
+	Status   string        `json:"status"`
+	Title    string        `json:"title,omitempty"`
+	Category string        `json:"category,omitempty"`
+	TodoID   *uint         `json:"todo_id,omitempty"`
+	Errors   []ImportError `json:"errors,omitempty"`
+}
+
+// TodoImportReport describes an import. Nothing is committed unless every
+// row is valid and DryRun is false
+type TodoImportReport struct {
+	DryRun            bool                  `json:"dry_run"`
+	Committed         bool                  `json:"committed"`
+	Mapping           map[string]string     `json:"mapping"`
+	IgnoredColumns    []string              `json:"ignored_columns"`
+	CreatedCategories []string              `json:"created_categories"`
+	Rows              []TodoImportRowResult `json:"rows"`
+	Valid             int                   `json:"valid"`
+	Invalid           int                   `json:"invalid"`
+}
+
+// TodoTxtImportRequest carries the lines of a todo.txt file
+type TodoTxtImportRequest struct {
+	Lines  []string
+	DryRun bool
+}
+
+// Apps whose exports ImportExternal reads
+const (
+	ImportSourceTodoist       = "todoist"
+	ImportSourceTrello        = "trello"
+	ImportSourceMicrosoftTodo = "mstodo"
+)
+
+// ExternalImportRequest carries a file exported by another app. Category
+// names the list its tasks belong to when the file does not, as with
+// Todoist CSV exports
+type ExternalImportRequest struct {
+	Source   string
+	Data     []byte
+	Category string
+	DryRun   bool
+}
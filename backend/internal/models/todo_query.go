@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// TodoQuery is a node of a parsed q= expression: one of QueryAnd, QueryOr,
+// QueryNot, QueryMatch or QueryDue
+type TodoQuery interface {
+	todoQuery()
+}
+
+// QueryAnd matches todos matched by both sides
+type QueryAnd struct {
+	Left, Right TodoQuery
+}
+
+// QueryOr matches todos matched by either side
+type QueryOr struct {
+	Left, Right TodoQuery
+}
+
+// QueryNot matches todos its operand does not match
+type QueryNot struct {
+	Operand TodoQuery
+}
+
+// QueryField names what a QueryMatch compares
+type QueryField string
+
+const (
+	// QueryText matches todos whose title or description contains Value
+	QueryText      QueryField = "text"
+	QueryPriority  QueryField = "priority"
+	QueryCategory  QueryField = "category"
+	QueryTag       QueryField = "tag"
+	QueryStatus    QueryField = "status"
+	QueryCompleted QueryField = "completed"
+	QueryBlocked   QueryField = "blocked"
+	// QueryCustomField matches the custom field named by Key
+	QueryCustomField QueryField = "cf"
+)
+
+// QueryMatch matches todos whose field equals Value. Category, tag and
+// status names compare case-insensitively; completed and blocked take true
+// or false
+type QueryMatch struct {
+	Field QueryField
+	Key   string
+	Value string
+}
+
+// QueryDue matches todos due from From (inclusive) to To (exclusive),
+// either of which may be open. With neither it matches todos without a due
+// date
+type QueryDue struct {
+	From, To *time.Time
+}
+
+func (QueryAnd) todoQuery()   {}
+func (QueryOr) todoQuery()    {}
+func (QueryNot) todoQuery()   {}
+func (QueryMatch) todoQuery() {}
+func (QueryDue) todoQuery()   {}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// TodoTxtTask is one line of a todo.txt file, see
+// https://github.com/todotxt/todo.txt. Projects (+name) and contexts
+// (@name) are stored with underscores turned back into spaces
+type TodoTxtTask struct {
+	Completed   bool
+	CompletedOn *time.Time
+	CreatedOn   *time.Time
+	Priority    Priority
+	Title       string
+	Projects    []string
+	Contexts    []string
+	DueDate     *time.Time
+}
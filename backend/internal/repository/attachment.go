@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/industrix-todo-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository interface {
+	Create(attachment *models.Attachment) error
+	GetByTodo(todoID uint) ([]models.Attachment, error)
+	GetByID(id uint) (*models.Attachment, error)
+	Delete(id uint) error
+	StorageKeys(todoID uint) ([]string, error)
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Create(attachment *models.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *attachmentRepository) GetByTodo(todoID uint) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.Where("todo_id = ?", todoID).Order("created_at ASC, id ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *attachmentRepository) GetByID(id uint) (*models.Attachment, error) {
+	var attachment models.Attachment
+	if err := r.db.First(&attachment, id).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Attachment{}, id).Error
+}
+
+// StorageKeys returns the blob keys of every attachment of a todo
+func (r *attachmentRepository) StorageKeys(todoID uint) ([]string, error) {
+	var keys []string
+	err := r.db.Model(&models.Attachment{}).Where("todo_id = ?", todoID).Pluck("storage_key", &keys).Error
+	return keys, err
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// backupTables lists every table holding user data, children first so they
+// can be cleared in order. Smart lists name categories and statuses in
+// their filter, so they go before them; Clear keeps the built-in ones
+var backupTables = []string{
+	"smart_lists",
+	"todo_tags",
+	"todo_dependencies",
+	"comments",
+	"attachments",
+	"time_entries",
+	"checklist_items",
+	"calendar_resources",
+	"todos",
+	"templates",
+	"categories",
+	"statuses",
+	"tags",
+}
+
+type BackupRepository interface {
+	Export() (*models.Backup, error)
+	AttachmentCount() (int64, error)
+	Clear() error
+	Create(record interface{}) (bool, error)
+	AddTag(todoID uint, name string) error
+	BlockerIDs(todoIDs []uint) ([]uint, error)
+	LockDependencies() error
+	Touch() error
+	Transaction(fn func(repo BackupRepository) error) error
+}
+
+type backupRepository struct {
+	db *gorm.DB
+}
+
+func NewBackupRepository(db *gorm.DB) BackupRepository {
+	return &backupRepository{db: db}
+}
+
+// Export reads every table from one consistent snapshot
+func (r *backupRepository) Export() (*models.Backup, error) {
+	backup := &models.Backup{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var todos []models.Todo
+		steps := []func() error{
+			func() error { return tx.Order("id ASC").Find(&backup.Categories).Error },
+			func() error { return tx.Order("id ASC").Find(&backup.Statuses).Error },
+			func() error { return tx.Preload("Tags").Order("id ASC").Find(&todos).Error },
+			func() error { return tx.Order("todo_id ASC, blocker_id ASC").Find(&backup.Dependencies).Error },
+			func() error { return tx.Order("id ASC").Find(&backup.Comments).Error },
+			func() error { return tx.Order("id ASC").Find(&backup.ChecklistItems).Error },
+			func() error { return tx.Order("id ASC").Find(&backup.TimeEntries).Error },
+			func() error { return tx.Order("id ASC").Find(&backup.Templates).Error },
+			func() error { return tx.Where("key = ''").Order("id ASC").Find(&backup.SmartLists).Error },
+		}
+		for _, step := range steps {
+			if err := step(); err != nil {
+				return err
+			}
+		}
+
+		backup.Todos = make([]models.BackupTodo, len(todos))
+		for i, todo := range todos {
+			tags := make([]string, len(todo.Tags))
+			for j, tag := range todo.Tags {
+				tags[j] = tag.Name
+			}
+			backup.Todos[i] = models.BackupTodo{
+				ID:              todo.ID,
+				Title:           todo.Title,
+				Description:     todo.Description,
+				Completed:       todo.Completed,
+				Priority:        todo.Priority,
+				DueDate:         todo.DueDate,
+				CategoryID:      todo.CategoryID,
+				StatusID:        todo.StatusID,
+				Tags:            tags,
+				Position:        todo.Position,
+				EstimateMinutes: todo.EstimateMinutes,
+				CustomFields:    todo.CustomFields,
+				Recurrence:      todo.Recurrence,
+				Version:         todo.Version,
+				CreatedAt:       todo.CreatedAt,
+				UpdatedAt:       todo.UpdatedAt,
+			}
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+// AttachmentCount counts the attachments, whose files backups do not hold
+func (r *backupRepository) AttachmentCount() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Attachment{}).Count(&count).Error
+	return count, err
+}
+
+// Clear deletes all user data
+func (r *backupRepository) Clear() error {
+	for _, table := range backupTables {
+		query := "DELETE FROM " + table
+		if table == "smart_lists" {
+			query += " WHERE key = ''"
+		}
+		if err := r.db.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return r.Touch()
+}
+
+// Create inserts a record as is, keeping its timestamps and leaving its
+// associations alone. It reports false when the record conflicts with an
+// existing one, such as a second running timer for a user
+func (r *backupRepository) Create(record interface{}) (bool, error) {
+	result := r.db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	return result.RowsAffected > 0, result.Error
+}
+
+// AddTag attaches the tag with the given name to a todo, creating the tag
+// when needed
+func (r *backupRepository) AddTag(todoID uint, name string) error {
+	tag := models.Tag{Name: name}
+	if err := r.db.Where("name = ?", name).FirstOrCreate(&tag).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&models.Todo{ID: todoID}).Association("Tags").Append(&tag)
+}
+
+// BlockerIDs returns the IDs of the todos blocking any of todoIDs
+func (r *backupRepository) BlockerIDs(todoIDs []uint) ([]uint, error) {
+	return (&todoRepository{db: r.db}).BlockerIDs(todoIDs)
+}
+
+// LockDependencies keeps other transactions from linking todos until the
+// restore ends
+func (r *backupRepository) LockDependencies() error {
+	return advisoryLock(r.db, dependencyLock)
+}
+
+// Touch marks todos, categories and statuses as changed, since restored
+// rows keep timestamps that may predate what clients last saw
+func (r *backupRepository) Touch() error {
+	for _, collection := range []string{todosCollection, categoriesCollection, statusesCollection} {
+		if err := touchCollection(r.db, collection); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *backupRepository) Transaction(fn func(repo BackupRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&backupRepository{db: tx})
+	})
+}
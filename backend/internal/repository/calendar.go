@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"github.com/industrix-todo-app/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CalendarTokenRepository interface {
+	Save(token *models.CalendarToken) error
+	GetByHash(hash string) (*models.CalendarToken, error)
+	DeleteByUser(user string) (bool, error)
+}
+
+type calendarTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarTokenRepository(db *gorm.DB) CalendarTokenRepository {
+	return &calendarTokenRepository{db: db}
+}
+
+// Save stores token, replacing the user's previous one
+func (r *calendarTokenRepository) Save(token *models.CalendarToken) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash", "created_at"}),
+	}).Create(token).Error
+}
+
+func (r *calendarTokenRepository) GetByHash(hash string) (*models.CalendarToken, error) {
+	var token models.CalendarToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// DeleteByUser revokes the user's token, reporting whether there was one
+func (r *calendarTokenRepository) DeleteByUser(user string) (bool, error) {
+	result := r.db.Where("user_name = ?", user).Delete(&models.CalendarToken{})
+	return result.RowsAffected > 0, result.Error
+}
+
+type CalendarResourceRepository interface {
+	Save(resource *models.CalendarResource) error
+	GetByName(name string) (*models.CalendarResource, error)
+	GetByUID(uid string) (*models.CalendarResource, error)
+	GetByTodoIDs(ids []uint) ([]models.CalendarResource, error)
+}
+
+type calendarResourceRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarResourceRepository(db *gorm.DB) CalendarResourceRepository {
+	return &calendarResourceRepository{db: db}
+}
+
+// Save stores resource, taking over its name and UID from resources of
+// todos that no longer exist
+func (r *calendarResourceRepository) Save(resource *models.CalendarResource) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("(name = ? OR uid = ?) AND todo_id NOT IN (SELECT id FROM todos)", resource.Name, resource.UID).
+			Delete(&models.CalendarResource{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(resource).Error
+	})
+}
+
+func (r *calendarResourceRepository) GetByName(name string) (*models.CalendarResource, error) {
+	var resource models.CalendarResource
+	if err := r.db.Where("name = ?", name).First(&resource).Error; err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (r *calendarResourceRepository) GetByUID(uid string) (*models.CalendarResource, error) {
+	var resource models.CalendarResource
+	if err := r.db.Where("uid = ?", uid).First(&resource).Error; err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (r *calendarResourceRepository) GetByTodoIDs(ids []uint) ([]models.CalendarResource, error) {
+	var resources []models.CalendarResource
+	err := r.db.Where("todo_id IN ?", ids).Find(&resources).Error
+	return resources, err
+}
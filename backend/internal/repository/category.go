@@ -3,14 +3,16 @@ package repository
 import (
 	"github.com/industrix-todo-app/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CategoryRepository interface {
 	Create(category *models.Category) error
 	GetAll() ([]models.Category, error)
+	ListState() (models.ListState, error)
 	GetByID(id uint) (*models.Category, error)
 	Update(category *models.Category) error
-	Delete(id uint) error
+	Delete(id, version uint) error
 }
 
 type categoryRepository struct {
@@ -31,6 +33,21 @@ func (r *categoryRepository) GetAll() ([]models.Category, error) {
 	return categories, err
 }
 
+// ListState reports when any category last changed
+func (r *categoryRepository) ListState() (models.ListState, error) {
+	modified, count, err := maxUpdatedAt(r.db.Model(&models.Category{}))
+	if err != nil {
+		return models.ListState{}, err
+	}
+
+	deleted, err := lastChange(r.db, categoriesCollection)
+	if err != nil {
+		return models.ListState{}, err
+	}
+
+	return models.ListState{LastModified: latest(modified, deleted), Count: count}, nil
+}
+
 func (r *categoryRepository) GetByID(id uint) (*models.Category, error) {
 	var category models.Category
 	err := r.db.First(&category, id).Error
@@ -40,10 +57,47 @@ func (r *categoryRepository) GetByID(id uint) (*models.Category, error) {
 	return &category, nil
 }
 
+// Update saves category only if its version is unchanged since it was loaded
+// and bumps the version, returning ErrVersionConflict otherwise. Its todos
+// embed it, so they are touched as well
 func (r *categoryRepository) Update(category *models.Category) error {
-	return r.db.Save(category).Error
+	version := category.Version
+	category.Version++
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Select("*").Omit(clause.Associations).Where("version = ?", version).Save(category)
+		if result.Error == nil && result.RowsAffected == 0 {
+			result.Error = ErrVersionConflict
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		return touchTodos(tx, "category_id = ?", category.ID)
+	})
+	if err != nil {
+		category.Version = version
+	}
+	return err
 }
 
-func (r *categoryRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Category{}, id).Error
+// Delete removes a category only if its version is unchanged, returning
+// ErrVersionConflict otherwise, and drops it from the smart list filters
+// naming it. Its todos lose it, so they are touched
+func (r *categoryRepository) Delete(id, version uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := touchTodos(tx, "category_id = ?", id); err != nil {
+			return err
+		}
+		result := tx.Where("version = ?", version).Delete(&models.Category{}, id)
+		if result.Error == nil && result.RowsAffected == 0 {
+			result.Error = ErrVersionConflict
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := clearSmartListFilter(tx, "category_id", id); err != nil {
+			return err
+		}
+		return touchCollection(tx, categoriesCollection)
+	})
 }
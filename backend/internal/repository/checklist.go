@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"github.com/industrix-todo-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ChecklistRepository interface {
+	Create(item *models.ChecklistItem) error
+	GetByTodo(todoID uint) ([]models.ChecklistItem, error)
+	GetByID(id uint) (*models.ChecklistItem, error)
+	Update(item *models.ChecklistItem) error
+	Delete(id uint) error
+	MaxPosition(todoID uint) (string, error)
+	Count(todoID uint) (int64, error)
+	SetPositions(positions map[uint]string) error
+}
+
+type checklistRepository struct {
+	db *gorm.DB
+}
+
+func NewChecklistRepository(db *gorm.DB) ChecklistRepository {
+	return &checklistRepository{db: db}
+}
+
+// Create adds an item and touches its todo, which embeds its checklist
+func (r *checklistRepository) Create(item *models.ChecklistItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+		return touchTodos(tx, "id = ?", item.TodoID)
+	})
+}
+
+// GetByTodo returns a todo's checklist in order
+func (r *checklistRepository) GetByTodo(todoID uint) ([]models.ChecklistItem, error) {
+	var items []models.ChecklistItem
+	err := r.db.Where("todo_id = ?", todoID).Order("position ASC, id ASC").Find(&items).Error
+	return items, err
+}
+
+func (r *checklistRepository) GetByID(id uint) (*models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	if err := r.db.First(&item, id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Update saves an item and touches its todo
+func (r *checklistRepository) Update(item *models.ChecklistItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(item).Error; err != nil {
+			return err
+		}
+		return touchTodos(tx, "id = ?", item.TodoID)
+	})
+}
+
+// Delete removes an item and touches its todo
+func (r *checklistRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var item models.ChecklistItem
+		if err := tx.Select("todo_id").First(&item, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.ChecklistItem{}, id).Error; err != nil {
+			return err
+		}
+		return touchTodos(tx, "id = ?", item.TodoID)
+	})
+}
+
+// MaxPosition returns the last rank of a todo's checklist, or "" when empty
+func (r *checklistRepository) MaxPosition(todoID uint) (string, error) {
+	var position *string
+	err := r.db.Model(&models.ChecklistItem{}).Where("todo_id = ?", todoID).Select("MAX(position)").Scan(&position).Error
+	if err != nil || position == nil {
+		return "", err
+	}
+	return *position, nil
+}
+
+func (r *checklistRepository) Count(todoID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ChecklistItem{}).Where("todo_id = ?", todoID).Count(&count).Error
+	return count, err
+}
+
+// SetPositions changes the positions of checklist items together and
+// touches their todos
+func (r *checklistRepository) SetPositions(positions map[uint]string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ids := make([]uint, 0, len(positions))
+		for id, position := range positions {
+			if err := tx.Model(&models.ChecklistItem{}).Where("id = ?", id).UpdateColumn("position", position).Error; err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return touchTodos(tx, "id IN (SELECT todo_id FROM checklist_items WHERE id IN ?)", ids)
+	})
+}
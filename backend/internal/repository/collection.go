@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Keys of the advisory locks that serialise checks a row lock cannot cover
+const (
+	dependencyLock int64 = iota + 1
+	positionLock
+)
+
+const (
+	todosCollection      = "todos"
+	categoriesCollection = "categories"
+	statusesCollection   = "statuses"
+)
+
+// touchCollection marks a collection as changed after rows were deleted
+func touchCollection(db *gorm.DB, collection string) error {
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&models.CollectionChange{
+		Collection: collection,
+		ChangedAt:  time.Now(),
+	}).Error
+}
+
+// touchTodos moves on the version and updated_at of the todos matching
+// query after a change they show but did not write themselves, such as a
+// blocker being completed, so their ETags and the list state change too
+func touchTodos(db *gorm.DB, query interface{}, args ...interface{}) error {
+	return db.Model(&models.Todo{}).Where(query, args...).UpdateColumns(map[string]interface{}{
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// advisoryLock takes a transaction-level advisory lock, held until the
+// transaction ends. Outside a transaction it is released right away, so
+// callers run it inside one
+func advisoryLock(db *gorm.DB, key int64) error {
+	return db.Exec("SELECT pg_advisory_xact_lock(?)", key).Error
+}
+
+// lastChange returns the latest deletion time among the given collections
+func lastChange(db *gorm.DB, collections ...string) (time.Time, error) {
+	var changedAt *time.Time
+	err := db.Model(&models.CollectionChange{}).
+		Where("collection IN ?", collections).
+		Select("MAX(changed_at)").
+		Scan(&changedAt).Error
+	if err != nil || changedAt == nil {
+		return time.Time{}, err
+	}
+	return *changedAt, nil
+}
+
+// maxUpdatedAt returns the latest updated_at and the row count of query
+func maxUpdatedAt(query *gorm.DB) (time.Time, int64, error) {
+	var row struct {
+		LastModified *time.Time
+		Total        int64
+	}
+	err := query.Select("MAX(updated_at) AS last_modified, COUNT(*) AS total").Scan(&row).Error
+	if err != nil || row.LastModified == nil {
+		return time.Time{}, row.Total, err
+	}
+	return *row.LastModified, row.Total, nil
+}
+
+func latest(times ...time.Time) time.Time {
+	var result time.Time
+	for _, t := range times {
+		if t.After(result) {
+			result = t
+		}
+	}
+	return result
+}
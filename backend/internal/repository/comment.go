@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"github.com/industrix-todo-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type CommentRepository interface {
+	Create(comment *models.Comment) error
+	GetByTodo(todoID uint, page, limit int) ([]models.Comment, int64, error)
+	GetByID(id uint) (*models.Comment, error)
+	Update(comment *models.Comment) error
+	Delete(id uint) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+// Create adds a comment and touches its todo, whose comment count changes
+func (r *commentRepository) Create(comment *models.Comment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		return touchTodos(tx, "id = ?", comment.TodoID)
+	})
+}
+
+// GetByTodo returns one page of a todo's comments, oldest first so the
+// thread reads top to bottom
+func (r *commentRepository) GetByTodo(todoID uint, page, limit int) ([]models.Comment, int64, error) {
+	var comments []models.Comment
+	var total int64
+
+	query := r.db.Model(&models.Comment{}).Where("todo_id = ?", todoID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at ASC, id ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&comments).Error
+	return comments, total, err
+}
+
+func (r *commentRepository) GetByID(id uint) (*models.Comment, error) {
+	var comment models.Comment
+	if err := r.db.First(&comment, id).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *commentRepository) Update(comment *models.Comment) error {
+	return r.db.Save(comment).Error
+}
+
+// Delete removes a comment and touches its todo
+func (r *commentRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var comment models.Comment
+		if err := tx.Select("todo_id").First(&comment, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Comment{}, id).Error; err != nil {
+			return err
+		}
+		return touchTodos(tx, "id = ?", comment.TodoID)
+	})
+}
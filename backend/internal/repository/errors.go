@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"errors"
+)
+
+// ErrVersionConflict is returned by updates whose row was changed by another
+// writer after it was loaded
+var ErrVersionConflict = errors.New("record was modified concurrently")
+
+var (
+	// ErrTimerRunning is returned when starting a timer for a user who
+	// already has one running
+	ErrTimerRunning = errors.New("user already has a running timer")
+
+	// ErrTimerNotRunning is returned when stopping a timer that has already
+	// been stopped
+	ErrTimerNotRunning = errors.New("timer is not running")
+)
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository interface {
+	CreateIfAbsent(record *models.IdempotencyRecord, staleBefore time.Time) (bool, error)
+	Get(scope, key string) (*models.IdempotencyRecord, error)
+	Complete(scope, key string, statusCode int, body []byte) error
+	Delete(scope, key string) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// CreateIfAbsent inserts record unless a live record already holds its key,
+// replacing an expired one or a claim without a response made before
+// staleBefore. It reports whether record was stored
+func (r *idempotencyRepository) CreateIfAbsent(record *models.IdempotencyRecord, staleBefore time.Time) (bool, error) {
+	err := r.db.
+		Where("scope = ? AND idempotency_key = ?", record.Scope, record.IdempotencyKey).
+		Where("expires_at < ? OR (status_code = 0 AND created_at < ?)", time.Now(), staleBefore).
+		Delete(&models.IdempotencyRecord{}).Error
+	if err != nil {
+		return false, err
+	}
+
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *idempotencyRepository) Get(scope, key string) (*models.IdempotencyRecord, error) {
+	var record models.IdempotencyRecord
+	err := r.db.Where("scope = ? AND idempotency_key = ?", scope, key).First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *idempotencyRepository) Complete(scope, key string, statusCode int, body []byte) error {
+	return r.db.Model(&models.IdempotencyRecord{}).
+		Where("scope = ? AND idempotency_key = ?", scope, key).
+		Updates(map[string]interface{}{"status_code": statusCode, "response_body": body}).Error
+}
+
+func (r *idempotencyRepository) Delete(scope, key string) error {
+	return r.db.Where("scope = ? AND idempotency_key = ?", scope, key).Delete(&models.IdempotencyRecord{}).Error
+}
+
+func (r *idempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&models.IdempotencyRecord{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type SmartListRepository interface {
+	Create(list *models.SmartList) error
+	GetAll() ([]models.SmartList, error)
+	GetByID(id uint) (*models.SmartList, error)
+	Keys() ([]string, error)
+	Update(list *models.SmartList) error
+	Delete(id uint) error
+}
+
+type smartListRepository struct {
+	db *gorm.DB
+}
+
+func NewSmartListRepository(db *gorm.DB) SmartListRepository {
+	return &smartListRepository{db: db}
+}
+
+func (r *smartListRepository) Create(list *models.SmartList) error {
+	return r.db.Create(list).Error
+}
+
+// GetAll lists the built-in smart lists first, then the saved ones by name
+func (r *smartListRepository) GetAll() ([]models.SmartList, error) {
+	var lists []models.SmartList
+	err := r.db.Order("key = '' ASC").Order("CASE WHEN key = '' THEN name END ASC").Order("id ASC").Find(&lists).Error
+	return lists, err
+}
+
+func (r *smartListRepository) GetByID(id uint) (*models.SmartList, error) {
+	var list models.SmartList
+	if err := r.db.First(&list, id).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Keys lists the keys of the built-in smart lists present
+func (r *smartListRepository) Keys() ([]string, error) {
+	var keys []string
+	err := r.db.Model(&models.SmartList{}).Where("key <> ''").Pluck("key", &keys).Error
+	return keys, err
+}
+
+func (r *smartListRepository) Update(list *models.SmartList) error {
+	return r.db.Save(list).Error
+}
+
+func (r *smartListRepository) Delete(id uint) error {
+	return r.db.Delete(&models.SmartList{}, id).Error
+}
+
+// clearSmartListFilter drops a deleted category or status from the smart
+// list filters naming it, which keep matching on the rest of their filter.
+// key is the filter field, category_id or status_id
+func clearSmartListFilter(tx *gorm.DB, key string, id uint) error {
+	return tx.Model(&models.SmartList{}).
+		Where("(filter->>'"+key+"')::bigint = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"filter":     gorm.Expr("filter - '" + key + "'"),
+			"updated_at": time.Now(),
+		}).Error
+}
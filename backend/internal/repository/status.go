@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"github.com/industrix-todo-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type StatusRepository interface {
+	Create(status *models.Status) error
+	GetAll() ([]models.Status, error)
+	GetByID(id uint) (*models.Status, error)
+	Update(status *models.Status) error
+	Delete(id uint) error
+	Count() (int64, error)
+	MaxPosition() (int, error)
+	FirstByDone(done bool) (*models.Status, error)
+	CountTodos(id uint) (int64, error)
+	AssignMissing(done bool, statusID uint) error
+}
+
+type statusRepository struct {
+	db *gorm.DB
+}
+
+func NewStatusRepository(db *gorm.DB) StatusRepository {
+	return &statusRepository{db: db}
+}
+
+func (r *statusRepository) Create(status *models.Status) error {
+	return r.db.Create(status).Error
+}
+
+func (r *statusRepository) GetAll() ([]models.Status, error) {
+	var statuses []models.Status
+	err := r.db.Order("position ASC, id ASC").Find(&statuses).Error
+	return statuses, err
+}
+
+func (r *statusRepository) GetByID(id uint) (*models.Status, error) {
+	var status models.Status
+	err := r.db.First(&status, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Update saves a status and touches the todos in it, which embed it
+func (r *statusRepository) Update(status *models.Status) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(status).Error; err != nil {
+			return err
+		}
+		return touchTodos(tx, "status_id = ?", status.ID)
+	})
+}
+
+// Delete removes a status and drops it from the smart list filters naming
+// it
+func (r *statusRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Status{}, id).Error; err != nil {
+			return err
+		}
+		if err := clearSmartListFilter(tx, "status_id", id); err != nil {
+			return err
+		}
+		return touchCollection(tx, statusesCollection)
+	})
+}
+
+func (r *statusRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Status{}).Count(&count).Error
+	return count, err
+}
+
+func (r *statusRepository) MaxPosition() (int, error) {
+	var position *int
+	err := r.db.Model(&models.Status{}).Select("MAX(position)").Scan(&position).Error
+	if err != nil || position == nil {
+		return 0, err
+	}
+	return *position, nil
+}
+
+// FirstByDone returns the leftmost status with the given done flag
+func (r *statusRepository) FirstByDone(done bool) (*models.Status, error) {
+	var status models.Status
+	err := r.db.Where("is_done = ?", done).Order("position ASC, id ASC").First(&status).Error
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (r *statusRepository) CountTodos(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Todo{}).Where("status_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// AssignMissing puts todos without a status and with the given completion
+// into statusID
+func (r *statusRepository) AssignMissing(done bool, statusID uint) error {
+	return r.db.Model(&models.Todo{}).
+		Where("status_id IS NULL AND completed = ?", done).
+		UpdateColumn("status_id", statusID).Error
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"github.com/industrix-todo-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type TemplateRepository interface {
+	Create(template *models.Template) error
+	GetAll() ([]models.Template, error)
+	GetByID(id uint) (*models.Template, error)
+	Update(template *models.Template) error
+	Delete(id uint) error
+}
+
+type templateRepository struct {
+	db *gorm.DB
+}
+
+func NewTemplateRepository(db *gorm.DB) TemplateRepository {
+	return &templateRepository{db: db}
+}
+
+func (r *templateRepository) Create(template *models.Template) error {
+	return r.db.Create(template).Error
+}
+
+func (r *templateRepository) GetAll() ([]models.Template, error) {
+	var templates []models.Template
+	err := r.db.Order("name ASC").Find(&templates).Error
+	return templates, err
+}
+
+func (r *templateRepository) GetByID(id uint) (*models.Template, error) {
+	var template models.Template
+	if err := r.db.First(&template, id).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *templateRepository) Update(template *models.Template) error {
+	return r.db.Save(template).Error
+}
+
+func (r *templateRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Template{}, id).Error
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TimeEntryRepository interface {
+	Create(entry *models.TimeEntry) error
+	StartTimer(entry *models.TimeEntry) error
+	StopTimer(entry *models.TimeEntry) error
+	Running(user string) (*models.TimeEntry, error)
+	GetByID(id uint) (*models.TimeEntry, error)
+	GetByTodo(todoID uint) ([]models.TimeEntry, error)
+	Delete(id uint) error
+	Report(filter models.TimeReportFilter) ([]models.TimeReportRow, error)
+}
+
+type timeEntryRepository struct {
+	db *gorm.DB
+}
+
+func NewTimeEntryRepository(db *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepository{db: db}
+}
+
+func (r *timeEntryRepository) Create(entry *models.TimeEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// StartTimer inserts a running entry, failing with ErrTimerRunning when the
+// user already has one. The partial unique index settles concurrent starts
+func (r *timeEntryRepository) StartTimer(entry *models.TimeEntry) error {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_name"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "ended_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(entry)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTimerRunning
+	}
+	return nil
+}
+
+// StopTimer records the end of a running entry, failing with
+// ErrTimerNotRunning when it was stopped in the meantime
+func (r *timeEntryRepository) StopTimer(entry *models.TimeEntry) error {
+	result := r.db.Model(entry).
+		Where("ended_at IS NULL").
+		Updates(map[string]interface{}{
+			"ended_at":         entry.EndedAt,
+			"duration_seconds": entry.DurationSeconds,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTimerNotRunning
+	}
+	return nil
+}
+
+// Running returns the user's running timer, or nil when there is none
+func (r *timeEntryRepository) Running(user string) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := r.db.Where("user_name = ? AND ended_at IS NULL", user).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *timeEntryRepository) GetByID(id uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	if err := r.db.First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *timeEntryRepository) GetByTodo(todoID uint) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	err := r.db.Where("todo_id = ?", todoID).Order("started_at DESC, id DESC").Find(&entries).Error
+	return entries, err
+}
+
+func (r *timeEntryRepository) Delete(id uint) error {
+	return r.db.Delete(&models.TimeEntry{}, id).Error
+}
+
+// Report sums the finished entries matching filter per group, largest first
+func (r *timeEntryRepository) Report(filter models.TimeReportFilter) ([]models.TimeReportRow, error) {
+	query := r.db.Table("time_entries te").
+		Joins("JOIN todos t ON t.id = te.todo_id").
+		Where("te.ended_at IS NOT NULL")
+
+	if filter.From != nil {
+		query = query.Where("te.started_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("te.started_at < ?", *filter.To)
+	}
+	if filter.CategoryID != nil {
+		query = query.Where("t.category_id = ?", *filter.CategoryID)
+	}
+	if filter.User != "" {
+		query = query.Where("te.user_name = ?", filter.User)
+	}
+
+	switch filter.GroupBy {
+	case models.TimeReportByCategory:
+		query = query.Joins("LEFT JOIN categories c ON c.id = t.category_id").
+			Select("COALESCE(CAST(c.id AS TEXT), '') AS key, COALESCE(c.name, 'Uncategorized') AS label, " +
+				"SUM(te.duration_seconds) AS total_seconds").
+			Group("c.id, c.name")
+	case models.TimeReportByDay:
+		zone := filter.TimeZone
+		if zone == "" {
+			zone = "UTC"
+		}
+		day := "TO_CHAR(te.started_at AT TIME ZONE ?, 'YYYY-MM-DD')"
+		query = query.Select(day+" AS key, "+day+" AS label, SUM(te.duration_seconds) AS total_seconds", zone, zone).
+			Group("1")
+	default:
+		query = query.Select("CAST(t.id AS TEXT) AS key, t.title AS label, t.estimate_minutes, " +
+			"SUM(te.duration_seconds) AS total_seconds").
+			Group("t.id, t.title, t.estimate_minutes")
+	}
+
+	var rows []models.TimeReportRow
+	order := "total_seconds DESC, key ASC"
+	if filter.GroupBy == models.TimeReportByDay {
+		order = "key ASC"
+	}
+	err := query.Order(order).Scan(&rows).Error
+	return rows, err
+}
@@ -1,18 +1,61 @@
 package repository
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
 	"github.com/industrix-todo-app/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TodoRepository interface {
 	Create(todo *models.Todo) error
 	GetAll(filter models.TodoFilter) ([]models.Todo, int64, error)
+	ListState(filter models.TodoFilter) (models.ListState, error)
 	GetByID(id uint) (*models.Todo, error)
 	Update(todo *models.Todo) error
-	Delete(id uint) error
+	Delete(id, version uint) error
+	FindIDs(filter models.TodoFilter) ([]uint, error)
+	Each(filter models.TodoFilter, fn func(todo *models.Todo) error) error
+	MaxPosition() (string, error)
+	LockPositions() error
+	AdjacentPosition(scope models.PositionScope, scopeID *uint, position string, after bool, excludeID uint) (string, error)
+	IDsWithoutPosition() ([]uint, error)
+	IDsByPosition() ([]uint, error)
+	SetPosition(id uint, position string) error
+	AddTag(todoID uint, name string) error
+	RemoveTag(todoID uint, name string) error
+	AddChecklistItems(items []models.ChecklistItem) error
+	FindOrCreateCategory(name string) (*models.Category, bool, error)
+	Transaction(fn func(repo TodoRepository) error) error
+	AddBlocker(todoID, blockerID uint) error
+	RemoveBlocker(todoID, blockerID uint) error
+	Blockers(todoID uint) ([]models.Todo, error)
+	Blocking(todoID uint) ([]models.Todo, error)
+	BlockerIDs(todoIDs []uint) ([]uint, error)
+	LockDependencies() error
+	OpenBlockerCount(todoID uint) (int64, error)
+	CountInStatusForUpdate(statusID uint) (int64, error)
+	UpdateStatus(status *models.Status) error
+}
+
+var sortableColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"title":      true,
+	"priority":   true,
+	"due_date":   true,
+	"completed":  true,
+	"position":   true,
 }
 
+// customFieldSortPrefix marks a sort on a custom field value
+const customFieldSortPrefix = "cf."
+
 type todoRepository struct {
 	db *gorm.DB
 }
@@ -29,8 +72,573 @@ func (r *todoRepository) GetAll(filter models.TodoFilter) ([]models.Todo, int64,
 	var todos []models.Todo
 	var total int64
 
-	query := r.db.Model(&models.Todo{})
+	query := applyTodoFilter(r.db.Model(&models.Todo{}), filter)
+
+	// Count total records
+	query.Count(&total)
+
+	// Apply sorting
+	query = sortTodos(query, filter)
+
+	// Apply pagination
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query = query.Offset(offset).Limit(filter.Limit)
+	}
+
+	// Execute query with preload
+	if err := query.Preload("Category").Preload("Status").Preload("Tags").Find(&todos).Error; err != nil {
+		return nil, 0, err
+	}
+	return todos, total, r.annotate(todos)
+}
+
+// ListState reports when the todos matching filter, or the categories and
+// statuses they embed, last changed
+func (r *todoRepository) ListState(filter models.TodoFilter) (models.ListState, error) {
+	todosModified, count, err := maxUpdatedAt(applyTodoFilter(r.db.Model(&models.Todo{}), filter))
+	if err != nil {
+		return models.ListState{}, err
+	}
+
+	categoriesModified, _, err := maxUpdatedAt(r.db.Model(&models.Category{}))
+	if err != nil {
+		return models.ListState{}, err
+	}
+
+	statusesModified, _, err := maxUpdatedAt(r.db.Model(&models.Status{}))
+	if err != nil {
+		return models.ListState{}, err
+	}
+
+	deleted, err := lastChange(r.db, todosCollection, categoriesCollection, statusesCollection)
+	if err != nil {
+		return models.ListState{}, err
+	}
+
+	return models.ListState{
+		LastModified: latest(todosModified, categoriesModified, statusesModified, deleted),
+		Count:        count,
+	}, nil
+}
+
+func (r *todoRepository) GetByID(id uint) (*models.Todo, error) {
+	var todo models.Todo
+	err := r.db.Preload("Category").Preload("Status").Preload("Tags").First(&todo, id).Error
+	if err != nil {
+		return nil, err
+	}
+	todos := []models.Todo{todo}
+	if err := r.annotate(todos); err != nil {
+		return nil, err
+	}
+	return &todos[0], nil
+}
+
+// Update saves todo only if its version is unchanged since it was loaded and
+// bumps the version, returning ErrVersionConflict otherwise
+func (r *todoRepository) Update(todo *models.Todo) error {
+	version := todo.Version
+	todo.Version++
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// The is_blocked flag of the todos it blocks follows its completion
+		completionChanged := blockedBy + " AND EXISTS (SELECT 1 FROM todos b WHERE b.id = ? AND b.completed <> ?)"
+		if err := touchTodos(tx, completionChanged, todo.ID, todo.ID, todo.Completed); err != nil {
+			return err
+		}
+
+		// Skip associations so a preloaded Category cannot override CategoryID
+		result := tx.Select("*").Omit(clause.Associations).Where("version = ?", version).Save(todo)
+		if result.Error == nil && result.RowsAffected == 0 {
+			result.Error = ErrVersionConflict
+		}
+		return result.Error
+	})
+	if err != nil {
+		todo.Version = version
+	}
+	return err
+}
+
+// Delete removes a todo and its child records only if its version is
+// unchanged, returning ErrVersionConflict otherwise
+func (r *todoRepository) Delete(id, version uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Todos it blocked may no longer be blocked
+		if err := touchTodos(tx, blockedBy, id); err != nil {
+			return err
+		}
+		if err := tx.Where("todo_id = ? OR blocker_id = ?", id, id).Delete(&models.TodoDependency{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("todo_id = ?", id).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("todo_id = ?", id).Delete(&models.Attachment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("todo_id = ?", id).Delete(&models.TimeEntry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("todo_id = ?", id).Delete(&models.ChecklistItem{}).Error; err != nil {
+			return err
+		}
+		// A concurrent write makes this match nothing and rolls the child
+		// deletions back
+		result := tx.Where("version = ?", version).Delete(&models.Todo{}, id)
+		if result.Error == nil && result.RowsAffected == 0 {
+			result.Error = ErrVersionConflict
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		return touchCollection(tx, todosCollection)
+	})
+}
+
+// FindIDs returns the IDs of every todo matching filter, ignoring pagination
+func (r *todoRepository) FindIDs(filter models.TodoFilter) ([]uint, error) {
+	var ids []uint
+	err := applyTodoFilter(r.db.Model(&models.Todo{}), filter).Order("id ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// MaxPosition returns the highest position of any todo, or an empty string
+// when there are none
+func (r *todoRepository) MaxPosition() (string, error) {
+	var position *string
+	err := r.db.Model(&models.Todo{}).Where("position <> ''").Select("MAX(position)").Scan(&position).Error
+	if err != nil || position == nil {
+		return "", err
+	}
+	return *position, nil
+}
+
+// LockPositions keeps other transactions from ranking todos until this one
+// ends, so two todos never read the same neighbours and get the same rank
+func (r *todoRepository) LockPositions() error {
+	return advisoryLock(r.db, positionLock)
+}
+
+// AdjacentPosition returns the position closest to position among todos
+// sharing the same scope value, looking after it or before it. An empty
+// string means position is at that end of the list
+func (r *todoRepository) AdjacentPosition(scope models.PositionScope, scopeID *uint, position string, after bool, excludeID uint) (string, error) {
+	column := string(scope)
+	query := r.db.Model(&models.Todo{}).Where("id <> ? AND position <> ''", excludeID)
+	if scopeID != nil {
+		query = query.Where(column+" = ?", *scopeID)
+	} else {
+		query = query.Where(column + " IS NULL")
+	}
+
+	var neighbour *string
+	var err error
+	if after {
+		err = query.Where("position > ?", position).Select("MIN(position)").Scan(&neighbour).Error
+	} else {
+		err = query.Where("position < ?", position).Select("MAX(position)").Scan(&neighbour).Error
+	}
+	if err != nil || neighbour == nil {
+		return "", err
+	}
+	return *neighbour, nil
+}
+
+// IDsWithoutPosition returns todos created before manual ordering existed,
+// oldest first
+func (r *todoRepository) IDsWithoutPosition() ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Todo{}).Where("position = ''").Order("created_at ASC, id ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// IDsByPosition returns every ranked todo in manual order
+func (r *todoRepository) IDsByPosition() ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Todo{}).Where("position <> ''").Order("position ASC, id ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// SetPosition changes only the position of a todo, leaving its version alone
+func (r *todoRepository) SetPosition(id uint, position string) error {
+	return r.db.Model(&models.Todo{}).Where("id = ?", id).UpdateColumn("position", position).Error
+}
+
+// AddTag attaches the tag with the given name to a todo, creating the tag if
+// it does not exist yet
+func (r *todoRepository) AddTag(todoID uint, name string) error {
+	tag := models.Tag{Name: name}
+	if err := r.db.Where("name = ?", name).FirstOrCreate(&tag).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&models.Todo{ID: todoID}).Association("Tags").Append(&tag)
+}
+
+// RemoveTag detaches the tag with the given name from a todo
+func (r *todoRepository) RemoveTag(todoID uint, name string) error {
+	var tag models.Tag
+	err := r.db.Where("name = ?", name).First(&tag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return r.db.Model(&models.Todo{ID: todoID}).Association("Tags").Delete(&tag)
+}
+
+// AddChecklistItems inserts checklist items along with their todo, so they
+// share its transaction
+func (r *todoRepository) AddChecklistItems(items []models.ChecklistItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return r.db.Create(&items).Error
+}
+
+// FindOrCreateCategory returns the category with the given name, ignoring
+// case, and creates it when there is none. The boolean reports a creation
+func (r *todoRepository) FindOrCreateCategory(name string) (*models.Category, bool, error) {
+	var category models.Category
+	err := r.db.Where("LOWER(name) = LOWER(?)", name).Order("id ASC").First(&category).Error
+	if err == nil {
+		return &category, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	category = models.Category{Name: name, Version: 1}
+	if err := r.db.Create(&category).Error; err != nil {
+		return nil, false, err
+	}
+	return &category, true, nil
+}
+
+// Transaction runs fn against a repository bound to a single database
+// transaction. Nested calls use savepoints, so an inner failure can be rolled
+// back without aborting the outer transaction
+func (r *todoRepository) Transaction(fn func(repo TodoRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&todoRepository{db: tx})
+	})
+}
+
+// CountInStatusForUpdate locks a status row until the transaction ends and
+// counts its todos, so concurrent WIP limit checks on one status run one
+// after another
+func (r *todoRepository) CountInStatusForUpdate(statusID uint) (int64, error) {
+	var locked []uint
+	err := r.db.Model(&models.Status{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", statusID).Pluck("id", &locked).Error
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	err = r.db.Model(&models.Todo{}).Where("status_id = ?", statusID).Count(&count).Error
+	return count, err
+}
+
+// UpdateStatus saves a status, so a change to its done flag can be written
+// in the same transaction as the completion of its todos
+func (r *todoRepository) UpdateStatus(status *models.Status) error {
+	return r.db.Save(status).Error
+}
+
+// AddBlocker links blockerID as a blocker of todoID and touches todoID,
+// whose blockers and is_blocked flag change
+func (r *todoRepository) AddBlocker(todoID, blockerID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.TodoDependency{
+			TodoID:    todoID,
+			BlockerID: blockerID,
+		})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return touchTodos(tx, "id = ?", todoID)
+	})
+}
+
+// RemoveBlocker unlinks blockerID from todoID and touches todoID
+func (r *todoRepository) RemoveBlocker(todoID, blockerID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("todo_id = ? AND blocker_id = ?", todoID, blockerID).Delete(&models.TodoDependency{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return touchTodos(tx, "id = ?", todoID)
+	})
+}
+
+// blockedBy matches the todos a todo blocks
+const blockedBy = "id IN (SELECT todo_id FROM todo_dependencies WHERE blocker_id = ?)"
 
+// Blockers returns the todos blocking todoID
+func (r *todoRepository) Blockers(todoID uint) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := r.db.Joins("JOIN todo_dependencies d ON d.blocker_id = todos.id").
+		Where("d.todo_id = ?", todoID).
+		Order("todos.id ASC").
+		Preload("Category").Preload("Status").Preload("Tags").
+		Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, r.annotate(todos)
+}
+
+// Blocking returns the todos that todoID blocks
+func (r *todoRepository) Blocking(todoID uint) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := r.db.Joins("JOIN todo_dependencies d ON d.todo_id = todos.id").
+		Where("d.blocker_id = ?", todoID).
+		Order("todos.id ASC").
+		Preload("Category").Preload("Status").Preload("Tags").
+		Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, r.annotate(todos)
+}
+
+// BlockerIDs returns the distinct blockers of any of todoIDs
+func (r *todoRepository) BlockerIDs(todoIDs []uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.TodoDependency{}).
+		Where("todo_id IN ?", todoIDs).
+		Distinct("blocker_id").
+		Pluck("blocker_id", &ids).Error
+	return ids, err
+}
+
+// LockDependencies keeps other transactions from linking todos until this
+// one ends, so a cycle check and the link it allows are not interleaved
+// with another pair
+func (r *todoRepository) LockDependencies() error {
+	return advisoryLock(r.db, dependencyLock)
+}
+
+// OpenBlockerCount counts the blockers of todoID that are not completed
+func (r *todoRepository) OpenBlockerCount(todoID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.TodoDependency{}).
+		Joins("JOIN todos b ON b.id = todo_dependencies.blocker_id").
+		Where("todo_dependencies.todo_id = ? AND b.completed = ?", todoID, false).
+		Count(&count).Error
+	return count, err
+}
+
+// annotate fills the computed fields of todos, running one query per field
+// rather than one per todo
+func (r *todoRepository) annotate(todos []models.Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(todos))
+	for i := range todos {
+		ids[i] = todos[i].ID
+	}
+	if err := r.markBlocked(todos, ids); err != nil {
+		return err
+	}
+	if err := r.countComments(todos, ids); err != nil {
+		return err
+	}
+	return r.checklistProgress(todos, ids)
+}
+
+func (r *todoRepository) markBlocked(todos []models.Todo, ids []uint) error {
+	var blocked []uint
+	err := r.db.Model(&models.TodoDependency{}).
+		Joins("JOIN todos b ON b.id = todo_dependencies.blocker_id").
+		Where("todo_dependencies.todo_id IN ? AND b.completed = ?", ids, false).
+		Distinct("todo_dependencies.todo_id").
+		Pluck("todo_dependencies.todo_id", &blocked).Error
+	if err != nil {
+		return err
+	}
+
+	isBlocked := make(map[uint]bool, len(blocked))
+	for _, id := range blocked {
+		isBlocked[id] = true
+	}
+	for i := range todos {
+		todos[i].IsBlocked = isBlocked[todos[i].ID]
+	}
+	return nil
+}
+
+func (r *todoRepository) countComments(todos []models.Todo, ids []uint) error {
+	var rows []struct {
+		TodoID uint
+		Count  int64
+	}
+	err := r.db.Model(&models.Comment{}).
+		Select("todo_id, COUNT(*) AS count").
+		Where("todo_id IN ?", ids).
+		Group("todo_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TodoID] = row.Count
+	}
+	for i := range todos {
+		todos[i].CommentCount = counts[todos[i].ID]
+	}
+	return nil
+}
+
+func (r *todoRepository) checklistProgress(todos []models.Todo, ids []uint) error {
+	var rows []struct {
+		TodoID uint
+		Total  int64
+		Done   int64
+	}
+	err := r.db.Model(&models.ChecklistItem{}).
+		Select("todo_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE checked) AS done").
+		Where("todo_id IN ?", ids).
+		Group("todo_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	progress := make(map[uint]models.ChecklistProgress, len(rows))
+	for _, row := range rows {
+		progress[row.TodoID] = models.ChecklistProgress{Total: row.Total, Done: row.Done}
+	}
+	for i := range todos {
+		todos[i].Checklist = progress[todos[i].ID]
+	}
+	return nil
+}
+
+// eachBatchSize is how many todos Each loads at once
+const eachBatchSize = 200
+
+// Each calls fn for every todo matching filter, in the filter's order. Only
+// IDs are streamed from the database; the todos themselves are loaded a
+// batch at a time so exports never hold the whole list in memory
+func (r *todoRepository) Each(filter models.TodoFilter, fn func(todo *models.Todo) error) error {
+	query := sortTodos(applyTodoFilter(r.db.Model(&models.Todo{}), filter), filter)
+	if filter.Limit > 0 {
+		query = query.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit)
+	}
+	rows, err := query.Select("todos.id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	ids := make([]uint, 0, eachBatchSize)
+	flush := func() error {
+		if len(ids) == 0 {
+			return nil
+		}
+		var todos []models.Todo
+		if err := r.db.Preload("Category").Preload("Status").Preload("Tags").Where("id IN ?", ids).Find(&todos).Error; err != nil {
+			return err
+		}
+		if err := r.annotate(todos); err != nil {
+			return err
+		}
+		byID := make(map[uint]*models.Todo, len(todos))
+		for i := range todos {
+			byID[todos[i].ID] = &todos[i]
+		}
+		for _, id := range ids {
+			// Skip todos deleted since the IDs were read
+			if todo, ok := byID[id]; ok {
+				if err := fn(todo); err != nil {
+					return err
+				}
+			}
+		}
+		ids = ids[:0]
+		return nil
+	}
+
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		if len(ids) == eachBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// sortTodos orders todos as requested by filter, only on known columns since
+// they are interpolated. Custom fields are addressed as cf.<key> and bound as
+// a parameter
+func sortTodos(query *gorm.DB, filter models.TodoFilter) *gorm.DB {
+	sortBy := filter.SortBy
+	fieldKey, byCustomField := strings.CutPrefix(sortBy, customFieldSortPrefix)
+	if !byCustomField && !sortableColumns[sortBy] {
+		sortBy = "created_at"
+	}
+	sortOrder := strings.ToUpper(filter.SortOrder)
+	if sortOrder != "ASC" && sortOrder != "DESC" {
+		sortOrder = "DESC"
+		if sortBy == "position" || byCustomField {
+			sortOrder = "ASC"
+		}
+	}
+	if byCustomField {
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "custom_fields -> ? " + sortOrder + " NULLS LAST",
+			Vars: []interface{}{fieldKey},
+		}})
+	} else {
+		query = query.Order(sortBy + " " + sortOrder)
+	}
+	return query.Order("id " + sortOrder)
+}
+
+// openBlockers holds for todos that wait on a blocker still open
+const openBlockers = "EXISTS (SELECT 1 FROM todo_dependencies d JOIN todos b ON b.id = d.blocker_id " +
+	"WHERE d.todo_id = todos.id AND b.completed = false)"
+
+// customFieldMatch returns a condition matching todos whose custom field
+// key holds value. Values arrive as text but are stored as strings, numbers
+// or booleans, so each reading of value is tried. The containment (@>)
+// checks are served by the GIN index on custom_fields and are never NULL
+func customFieldMatch(key, value string) (string, []interface{}) {
+	candidates := []interface{}{value}
+	if n, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(n, 0) && !math.IsNaN(n) {
+		candidates = append(candidates, n)
+	}
+	if value == "true" || value == "false" {
+		candidates = append(candidates, value == "true")
+	}
+
+	conditions := make([]string, len(candidates))
+	args := make([]interface{}, len(candidates))
+	for i, candidate := range candidates {
+		document, _ := json.Marshal(map[string]interface{}{key: candidate})
+		conditions[i] = "custom_fields @> ?::jsonb"
+		args[i] = string(document)
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// applyTodoFilter adds the WHERE clauses selected by filter to query
+func applyTodoFilter(query *gorm.DB, filter models.TodoFilter) *gorm.DB {
 	// Apply search filter
 	if filter.Search != "" {
 		searchPattern := "%" + filter.Search + "%"
@@ -52,44 +660,46 @@ func (r *todoRepository) GetAll(filter models.TodoFilter) ([]models.Todo, int64,
 		query = query.Where("priority = ?", filter.Priority)
 	}
 
-	// Count total records
-	query.Count(&total)
-
-	// Apply sorting
-	sortBy := filter.SortBy
-	if sortBy == "" {
-		sortBy = "created_at"
-	}
-	sortOrder := filter.SortOrder
-	if sortOrder == "" {
-		sortOrder = "DESC"
+	// Apply status filter
+	if filter.StatusID != nil {
+		query = query.Where("status_id = ?", *filter.StatusID)
 	}
-	query = query.Order(sortBy + " " + sortOrder)
 
-	// Apply pagination
-	if filter.Limit > 0 {
-		offset := (filter.Page - 1) * filter.Limit
-		query = query.Offset(offset).Limit(filter.Limit)
+	// Apply custom field filters
+	for key, value := range filter.CustomFields {
+		sql, args := customFieldMatch(key, value)
+		query = query.Where(sql, args...)
 	}
 
-	// Execute query with preload
-	err := query.Preload("Category").Find(&todos).Error
-	return todos, total, err
-}
+	// Apply due date filter
+	if filter.HasDueDate != nil {
+		if *filter.HasDueDate {
+			query = query.Where("due_date IS NOT NULL")
+		} else {
+			query = query.Where("due_date IS NULL")
+		}
+	}
+	if filter.DueFrom != nil {
+		query = query.Where("due_date >= ?", *filter.DueFrom)
+	}
+	if filter.DueTo != nil {
+		query = query.Where("due_date < ?", *filter.DueTo)
+	}
 
-func (r *todoRepository) GetByID(id uint) (*models.Todo, error) {
-	var todo models.Todo
-	err := r.db.Preload("Category").First(&todo, id).Error
-	if err != nil {
-		return nil, err
+	// Apply blocked filter
+	if filter.Blocked != nil {
+		if *filter.Blocked {
+			query = query.Where(openBlockers)
+		} else {
+			query = query.Where("NOT " + openBlockers)
+		}
 	}
-	return &todo, nil
-}
 
-func (r *todoRepository) Update(todo *models.Todo) error {
-	return r.db.Save(todo).Error
-}
+	// Apply the q= expression
+	if filter.Expr != nil {
+		sql, args := compileTodoQuery(filter.Expr)
+		query = query.Where(sql, args...)
+	}
 
-func (r *todoRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Todo{}, id).Error
+	return query
 }
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+// likeEscaper escapes the LIKE wildcards in text searched for by a query,
+// using the default escape character of Postgres
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// compileTodoQuery turns a parsed q= expression into a SQL condition on
+// todos. Values are always bound as parameters; only fixed SQL is written
+// into the condition. Every term is true or false, never NULL, so NOT
+// matches exactly the todos its operand does not
+func compileTodoQuery(expr models.TodoQuery) (string, []interface{}) {
+	c := &todoQueryCompiler{}
+	c.compile(expr)
+	return c.sql.String(), c.args
+}
+
+type todoQueryCompiler struct {
+	sql  strings.Builder
+	args []interface{}
+}
+
+func (c *todoQueryCompiler) write(sql string, args ...interface{}) {
+	c.sql.WriteString(sql)
+	c.args = append(c.args, args...)
+}
+
+func (c *todoQueryCompiler) compile(expr models.TodoQuery) {
+	switch e := expr.(type) {
+	case models.QueryAnd:
+		c.binary(e.Left, " AND ", e.Right)
+	case models.QueryOr:
+		c.binary(e.Left, " OR ", e.Right)
+	case models.QueryNot:
+		c.write("NOT ")
+		c.compile(e.Operand)
+	case models.QueryMatch:
+		c.match(e)
+	case models.QueryDue:
+		c.due(e)
+	default:
+		panic(fmt.Sprintf("repository: unknown query node %T", expr))
+	}
+}
+
+func (c *todoQueryCompiler) binary(left models.TodoQuery, op string, right models.TodoQuery) {
+	c.write("(")
+	c.compile(left)
+	c.write(op)
+	c.compile(right)
+	c.write(")")
+}
+
+func (c *todoQueryCompiler) match(m models.QueryMatch) {
+	switch m.Field {
+	case models.QueryText:
+		pattern := "%" + likeEscaper.Replace(m.Value) + "%"
+		c.write("(title ILIKE ? OR COALESCE(description, '') ILIKE ?)", pattern, pattern)
+	case models.QueryPriority:
+		c.write("priority = ?", m.Value)
+	case models.QueryCategory:
+		c.write("EXISTS (SELECT 1 FROM categories c WHERE c.id = todos.category_id AND LOWER(c.name) = LOWER(?))", m.Value)
+	case models.QueryStatus:
+		c.write("EXISTS (SELECT 1 FROM statuses s WHERE s.id = todos.status_id AND LOWER(s.name) = LOWER(?))", m.Value)
+	case models.QueryTag:
+		c.write("EXISTS (SELECT 1 FROM todo_tags tt JOIN tags t ON t.id = tt.tag_id "+
+			"WHERE tt.todo_id = todos.id AND LOWER(t.name) = LOWER(?))", m.Value)
+	case models.QueryCompleted:
+		c.write("completed = ?", m.Value == "true")
+	case models.QueryBlocked:
+		if m.Value == "true" {
+			c.write(openBlockers)
+		} else {
+			c.write("NOT " + openBlockers)
+		}
+	case models.QueryCustomField:
+		sql, args := customFieldMatch(m.Key, m.Value)
+		c.write(sql, args...)
+	default:
+		panic(fmt.Sprintf("repository: unknown query field %q", m.Field))
+	}
+}
+
+func (c *todoQueryCompiler) due(d models.QueryDue) {
+	if d.From == nil && d.To == nil {
+		c.write("due_date IS NULL")
+		return
+	}
+	c.write("(due_date IS NOT NULL")
+	if d.From != nil {
+		c.write(" AND due_date >= ?", *d.From)
+	}
+	if d.To != nil {
+		c.write(" AND due_date < ?", *d.To)
+	}
+	c.write(")")
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+	"github.com/industrix-todo-app/backend/internal/storage"
+)
+
+var (
+	ErrAttachmentNotFound       = errors.New("attachment not found")
+	ErrAttachmentEmpty          = errors.New("file is empty")
+	ErrAttachmentTooLarge       = errors.New("file is too large")
+	ErrAttachmentTypeNotAllowed = errors.New("file type is not allowed")
+)
+
+// sniffLength is how much of an upload is inspected to detect its type
+const sniffLength = 512
+
+// AttachmentUpload is a file received from a client
+type AttachmentUpload struct {
+	FileName string
+	Size     int64
+	Body     io.Reader
+}
+
+type AttachmentService interface {
+	Upload(todoID uint, uploader string, upload AttachmentUpload) (*models.Attachment, error)
+	List(todoID uint) ([]models.Attachment, error)
+	Open(todoID, id uint) (*models.Attachment, storage.Object, error)
+	Delete(todoID, id uint) error
+	BlobKeys(todoID uint) ([]string, error)
+	RemoveBlobs(keys []string)
+}
+
+type attachmentService struct {
+	repo         repository.AttachmentRepository
+	todoRepo     repository.TodoRepository
+	store        storage.Storage
+	maxSize      int64
+	allowedTypes []string
+}
+
+// NewAttachmentService keeps uploads in store, refusing files above maxSize
+// bytes or whose type is not in allowedTypes. Entries such as "image/*" match
+// a whole family; an empty list allows every type
+func NewAttachmentService(repo repository.AttachmentRepository, todoRepo repository.TodoRepository, store storage.Storage, maxSize int64, allowedTypes []string) AttachmentService {
+	return &attachmentService{
+		repo:         repo,
+		todoRepo:     todoRepo,
+		store:        store,
+		maxSize:      maxSize,
+		allowedTypes: allowedTypes,
+	}
+}
+
+func (s *attachmentService) Upload(todoID uint, uploader string, upload AttachmentUpload) (*models.Attachment, error) {
+	if _, err := s.todoRepo.GetByID(todoID); err != nil {
+		return nil, ErrTodoNotFound
+	}
+	if upload.Size > s.maxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(upload.Body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrAttachmentEmpty
+	}
+	head = head[:n]
+
+	name := cleanFileName(upload.FileName)
+	contentType := detectContentType(name, head)
+	if !s.typeAllowed(contentType) {
+		return nil, ErrAttachmentTypeNotAllowed
+	}
+
+	key, err := newStorageKey(todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The declared size is only a hint, so count what is actually stored
+	body := &sizeLimitedReader{r: io.MultiReader(bytes.NewReader(head), upload.Body), remaining: s.maxSize}
+	if err := s.store.Put(context.Background(), key, body, upload.Size, contentType); err != nil {
+		s.RemoveBlobs([]string{key})
+		if body.exceeded {
+			return nil, ErrAttachmentTooLarge
+		}
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		TodoID:      todoID,
+		FileName:    name,
+		ContentType: contentType,
+		Size:        s.maxSize - body.remaining,
+		StorageKey:  key,
+		UploadedBy:  uploader,
+	}
+	if err := s.repo.Create(attachment); err != nil {
+		s.RemoveBlobs([]string{key})
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (s *attachmentService) List(todoID uint) ([]models.Attachment, error) {
+	if _, err := s.todoRepo.GetByID(todoID); err != nil {
+		return nil, ErrTodoNotFound
+	}
+	return s.repo.GetByTodo(todoID)
+}
+
+// Open returns an attachment with its content, which the caller must close
+func (s *attachmentService) Open(todoID, id uint) (*models.Attachment, storage.Object, error) {
+	attachment, err := s.find(todoID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	object, err := s.store.Open(context.Background(), attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, object, nil
+}
+
+func (s *attachmentService) Delete(todoID, id uint) error {
+	attachment, err := s.find(todoID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.RemoveBlobs([]string{attachment.StorageKey})
+	return nil
+}
+
+// BlobKeys lists the blobs of a todo so they can be removed once the todo
+// itself is gone
+func (s *attachmentService) BlobKeys(todoID uint) ([]string, error) {
+	return s.repo.StorageKeys(todoID)
+}
+
+// RemoveBlobs deletes blobs whose rows are already gone. Failures only leave
+// unreachable files behind, so they are logged rather than returned
+func (s *attachmentService) RemoveBlobs(keys []string) {
+	for _, key := range keys {
+		if err := s.store.Delete(context.Background(), key); err != nil {
+			log.Printf("Failed to delete attachment blob %s: %v", key, err)
+		}
+	}
+}
+
+func (s *attachmentService) find(todoID, id uint) (*models.Attachment, error) {
+	attachment, err := s.repo.GetByID(id)
+	if err != nil || attachment.TodoID != todoID {
+		return nil, ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+func (s *attachmentService) typeAllowed(contentType string) bool {
+	if len(s.allowedTypes) == 0 {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	for _, allowed := range s.allowedTypes {
+		if family, ok := strings.CutSuffix(allowed, "/*"); ok {
+			if strings.HasPrefix(mediaType, family+"/") {
+				return true
+			}
+		} else if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// detectContentType sniffs the content, falling back to the file extension
+// when the bytes alone are not conclusive
+func detectContentType(name string, head []byte) string {
+	sniffed := http.DetectContentType(head)
+	if sniffed == "application/octet-stream" || strings.HasPrefix(sniffed, "text/plain") {
+		if byExt := mime.TypeByExtension(strings.ToLower(filepath.Ext(name))); byExt != "" {
+			return byExt
+		}
+	}
+	return sniffed
+}
+
+// cleanFileName keeps the base name of an uploaded file without control
+// characters, so it is safe to echo in a Content-Disposition header
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[len(runes)-255:])
+	}
+	return name
+}
+
+func newStorageKey(todoID uint) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("todos/%d/%s", todoID, hex.EncodeToString(b)), nil
+}
+
+// sizeLimitedReader fails once more than remaining bytes have been read
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		l.exceeded = true
+		return n, ErrAttachmentTooLarge
+	}
+	return n, err
+}
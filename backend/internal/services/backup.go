@@ -0,0 +1,596 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var (
+	ErrUnsupportedBackup  = errors.New("not a backup file, or one written by a newer version")
+	ErrInvalidRestoreMode = errors.New("mode must be merge or replace")
+	ErrBackupDuplicateID  = errors.New("id is used twice in the backup")
+	ErrBackupMissingRef   = errors.New("references a record missing from the backup")
+	ErrBackupPosition     = errors.New("position must be a rank of at most 255 characters from 0-9, A-Z and a-z")
+	ErrRestoreAttachments = errors.New("replace would delete attachments, which backups do not contain; delete them first or restore with merge")
+)
+
+type BackupService interface {
+	Export() (*models.Backup, error)
+	Restore(backup *models.Backup, mode models.RestoreMode) (*models.RestoreReport, error)
+}
+
+type backupService struct {
+	repo repository.BackupRepository
+	now  func() time.Time
+}
+
+func NewBackupService(repo repository.BackupRepository) BackupService {
+	return &backupService{repo: repo, now: time.Now}
+}
+
+// Export returns a copy of all data in the current backup format
+func (s *backupService) Export() (*models.Backup, error) {
+	backup, err := s.repo.Export()
+	if err != nil {
+		return nil, err
+	}
+	backup.Format = models.BackupFormat
+	backup.Version = models.BackupVersion
+	backup.ExportedAt = s.now().UTC()
+	return backup, nil
+}
+
+// Restore loads a backup in one transaction, giving every record a new ID.
+// In merge mode records matching existing ones are skipped: categories,
+// statuses, templates and smart lists by name, todos by title and creation time, along
+// with their comments, checklists and time entries. Replace mode deletes all
+// data first; since backups do not hold attachment files, it is refused
+// while any attachment exists
+func (s *backupService) Restore(backup *models.Backup, mode models.RestoreMode) (*models.RestoreReport, error) {
+	if mode == "" {
+		mode = models.RestoreMerge
+	}
+	if mode != models.RestoreMerge && mode != models.RestoreReplace {
+		return nil, ErrInvalidRestoreMode
+	}
+	if err := validateBackup(backup); err != nil {
+		return nil, err
+	}
+
+	report := &models.RestoreReport{Mode: mode}
+	err := s.repo.Transaction(func(tx repository.BackupRepository) error {
+		if mode == models.RestoreReplace {
+			attachments, err := tx.AttachmentCount()
+			if err != nil {
+				return err
+			}
+			if attachments > 0 {
+				return ErrRestoreAttachments
+			}
+			if err := tx.Clear(); err != nil {
+				return err
+			}
+		}
+
+		existing, err := tx.Export()
+		if err != nil {
+			return err
+		}
+		r := newRestorer(tx, report, existing)
+		if err := r.restore(backup); err != nil {
+			return err
+		}
+		return tx.Touch()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// restorer copies the records of a backup, remembering the new ID of each
+// backup ID so relations can be remapped
+type restorer struct {
+	tx     repository.BackupRepository
+	report *models.RestoreReport
+
+	categoryIDs map[uint]uint
+	statusIDs   map[uint]uint
+	todoIDs     map[uint]uint
+	// newTodos holds the backup IDs of todos that were not duplicates
+	newTodos map[uint]bool
+	// categoryByID holds the categories restored todos can land in, by their
+	// ID in the database
+	categoryByID map[uint]*models.Category
+
+	categoryNames  map[string]uint
+	statusNames    map[string]uint
+	todoKeys       map[string]uint
+	templateNames  map[string]bool
+	smartListNames map[string]bool
+	statusPosition int
+}
+
+func newRestorer(tx repository.BackupRepository, report *models.RestoreReport, existing *models.Backup) *restorer {
+	r := &restorer{
+		tx:             tx,
+		report:         report,
+		categoryIDs:    map[uint]uint{},
+		statusIDs:      map[uint]uint{},
+		todoIDs:        map[uint]uint{},
+		newTodos:       map[uint]bool{},
+		categoryByID:   map[uint]*models.Category{},
+		categoryNames:  map[string]uint{},
+		statusNames:    map[string]uint{},
+		todoKeys:       map[string]uint{},
+		templateNames:  map[string]bool{},
+		smartListNames: map[string]bool{},
+		statusPosition: -1,
+	}
+	for i := range existing.Categories {
+		category := &existing.Categories[i]
+		r.categoryNames[strings.ToLower(category.Name)] = category.ID
+		r.categoryByID[category.ID] = category
+	}
+	for _, status := range existing.Statuses {
+		r.statusNames[strings.ToLower(status.Name)] = status.ID
+		if status.Position > r.statusPosition {
+			r.statusPosition = status.Position
+		}
+	}
+	for _, todo := range existing.Todos {
+		r.todoKeys[todoKey(todo.Title, todo.CreatedAt)] = todo.ID
+	}
+	for _, template := range existing.Templates {
+		r.templateNames[strings.ToLower(template.Name)] = true
+	}
+	for _, list := range existing.SmartLists {
+		r.smartListNames[strings.ToLower(list.Name)] = true
+	}
+	return r
+}
+
+// todoKey identifies a todo across instances
+func todoKey(title string, createdAt time.Time) string {
+	return title + "\x00" + createdAt.UTC().Format(time.RFC3339Nano)
+}
+
+func (r *restorer) restore(backup *models.Backup) error {
+	steps := []func(*models.Backup) error{
+		r.categories,
+		r.statuses,
+		r.todos,
+		r.dependencies,
+		r.todoChildren,
+		r.templates,
+		r.smartLists,
+	}
+	for _, step := range steps {
+		if err := step(backup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *restorer) categories(backup *models.Backup) error {
+	for _, category := range backup.Categories {
+		name := strings.ToLower(category.Name)
+		if id, ok := r.categoryNames[name]; ok {
+			r.categoryIDs[category.ID] = id
+			r.report.Categories.Skipped++
+			continue
+		}
+
+		backupID := category.ID
+		category.ID = 0
+		category.Todos = nil
+		if _, err := r.tx.Create(&category); err != nil {
+			return err
+		}
+		r.categoryIDs[backupID] = category.ID
+		r.categoryNames[name] = category.ID
+		r.categoryByID[category.ID] = &category
+		r.report.Categories.Created++
+	}
+	return nil
+}
+
+// statuses appends new statuses after the existing board columns, keeping
+// their order
+func (r *restorer) statuses(backup *models.Backup) error {
+	for _, status := range backup.Statuses {
+		name := strings.ToLower(status.Name)
+		if id, ok := r.statusNames[name]; ok {
+			r.statusIDs[status.ID] = id
+			r.report.Statuses.Skipped++
+			continue
+		}
+
+		backupID := status.ID
+		status.ID = 0
+		if r.statusPosition >= 0 {
+			r.statusPosition++
+			status.Position = r.statusPosition
+		}
+		if _, err := r.tx.Create(&status); err != nil {
+			return err
+		}
+		r.statusIDs[backupID] = status.ID
+		r.statusNames[name] = status.ID
+		r.report.Statuses.Created++
+	}
+	return nil
+}
+
+func (r *restorer) todos(backup *models.Backup) error {
+	for _, item := range backup.Todos {
+		key := todoKey(item.Title, item.CreatedAt)
+		if id, ok := r.todoKeys[key]; ok {
+			r.todoIDs[item.ID] = id
+			r.report.Todos.Skipped++
+			continue
+		}
+
+		if item.Priority == "" {
+			item.Priority = models.PriorityMedium
+		}
+		categoryID := remapID(r.categoryIDs, item.CategoryID)
+		if categoryID != nil && len(item.CustomFields) > 0 {
+			// A category merged by name may define its fields differently
+			// from the backup; values it would not accept are dropped
+			item.CustomFields = mergeCustomFields(&ValidationError{}, r.categoryByID[*categoryID], nil, item.CustomFields, false)
+		}
+		todo := models.Todo{
+			Title:           item.Title,
+			Description:     item.Description,
+			Completed:       item.Completed,
+			Priority:        item.Priority,
+			DueDate:         item.DueDate,
+			CategoryID:      categoryID,
+			StatusID:        remapID(r.statusIDs, item.StatusID),
+			Position:        item.Position,
+			EstimateMinutes: item.EstimateMinutes,
+			CustomFields:    item.CustomFields,
+			Recurrence:      item.Recurrence,
+			Version:         item.Version,
+			CreatedAt:       item.CreatedAt,
+			UpdatedAt:       item.UpdatedAt,
+		}
+		if todo.Version == 0 {
+			todo.Version = 1
+		}
+		if _, err := r.tx.Create(&todo); err != nil {
+			return err
+		}
+		for _, name := range item.Tags {
+			if err := r.tx.AddTag(todo.ID, name); err != nil {
+				return err
+			}
+		}
+		r.todoIDs[item.ID] = todo.ID
+		r.todoKeys[key] = todo.ID
+		r.newTodos[item.ID] = true
+		r.report.Todos.Created++
+	}
+	return nil
+}
+
+// dependencies restores the links between todos. Merged todos may already
+// be linked, so a link that would close a loop is skipped like AddBlocker
+// refuses it
+func (r *restorer) dependencies(backup *models.Backup) error {
+	if len(backup.Dependencies) > 0 {
+		if err := r.tx.LockDependencies(); err != nil {
+			return err
+		}
+	}
+	for _, dependency := range backup.Dependencies {
+		dependency.TodoID = r.todoIDs[dependency.TodoID]
+		dependency.BlockerID = r.todoIDs[dependency.BlockerID]
+		cyclic := dependency.TodoID == dependency.BlockerID
+		if !cyclic {
+			var err error
+			if cyclic, err = reaches(r.tx.BlockerIDs, dependency.BlockerID, dependency.TodoID); err != nil {
+				return err
+			}
+		}
+		if cyclic {
+			r.report.Dependencies.Skipped++
+			continue
+		}
+		created, err := r.tx.Create(&dependency)
+		if err != nil {
+			return err
+		}
+		count(&r.report.Dependencies, created)
+	}
+	return nil
+}
+
+// todoChildren restores comments, checklist items and time entries of the
+// todos that were created; duplicates already have theirs
+func (r *restorer) todoChildren(backup *models.Backup) error {
+	for _, comment := range backup.Comments {
+		if !r.newTodos[comment.TodoID] {
+			r.report.Comments.Skipped++
+			continue
+		}
+		comment.ID = 0
+		comment.TodoID = r.todoIDs[comment.TodoID]
+		created, err := r.tx.Create(&comment)
+		if err != nil {
+			return err
+		}
+		count(&r.report.Comments, created)
+	}
+
+	for _, item := range backup.ChecklistItems {
+		if !r.newTodos[item.TodoID] {
+			r.report.ChecklistItems.Skipped++
+			continue
+		}
+		item.ID = 0
+		item.TodoID = r.todoIDs[item.TodoID]
+		created, err := r.tx.Create(&item)
+		if err != nil {
+			return err
+		}
+		count(&r.report.ChecklistItems, created)
+	}
+
+	for _, entry := range backup.TimeEntries {
+		if !r.newTodos[entry.TodoID] {
+			r.report.TimeEntries.Skipped++
+			continue
+		}
+		entry.ID = 0
+		entry.TodoID = r.todoIDs[entry.TodoID]
+		// A running timer is skipped when its user already runs one
+		created, err := r.tx.Create(&entry)
+		if err != nil {
+			return err
+		}
+		count(&r.report.TimeEntries, created)
+	}
+	return nil
+}
+
+// templates restores templates, pointing their items at the restored
+// categories
+func (r *restorer) templates(backup *models.Backup) error {
+	for _, template := range backup.Templates {
+		name := strings.ToLower(template.Name)
+		if r.templateNames[name] {
+			r.report.Templates.Skipped++
+			continue
+		}
+
+		template.ID = 0
+		items := make(models.TemplateItems, len(template.Items))
+		for i, item := range template.Items {
+			item.CategoryID = remapID(r.categoryIDs, item.CategoryID)
+			items[i] = item
+		}
+		template.Items = items
+		if _, err := r.tx.Create(&template); err != nil {
+			return err
+		}
+		r.templateNames[name] = true
+		r.report.Templates.Created++
+	}
+	return nil
+}
+
+// smartLists restores the saved smart lists, pointing their filters at the
+// restored category and status. Built-in lists are seeded on start, so any
+// in the backup are skipped
+func (r *restorer) smartLists(backup *models.Backup) error {
+	for _, list := range backup.SmartLists {
+		name := strings.ToLower(list.Name)
+		if list.Key != "" || r.smartListNames[name] {
+			r.report.SmartLists.Skipped++
+			continue
+		}
+
+		list.ID = 0
+		list.Filter.CategoryID = remapID(r.categoryIDs, list.Filter.CategoryID)
+		list.Filter.StatusID = remapID(r.statusIDs, list.Filter.StatusID)
+		if _, err := r.tx.Create(&list); err != nil {
+			return err
+		}
+		r.smartListNames[name] = true
+		r.report.SmartLists.Created++
+	}
+	return nil
+}
+
+func remapID(ids map[uint]uint, id *uint) *uint {
+	if id == nil {
+		return nil
+	}
+	newID, ok := ids[*id]
+	if !ok {
+		return nil
+	}
+	return &newID
+}
+
+func count(c *models.RestoreCount, created bool) {
+	if created {
+		c.Created++
+	} else {
+		c.Skipped++
+	}
+}
+
+// validateBackup checks the format and version of a backup, that its
+// records only reference records it contains and that each record passes
+// the checks its create endpoint applies
+func validateBackup(backup *models.Backup) error {
+	if backup.Format != models.BackupFormat || backup.Version < 1 || backup.Version > models.BackupVersion {
+		return ErrUnsupportedBackup
+	}
+
+	verr := &ValidationError{}
+	categories := map[uint]bool{}
+	for i, category := range backup.Categories {
+		if categories[category.ID] {
+			verr.add(fmt.Sprintf("categories[%d].id", i), "unique", ErrBackupDuplicateID)
+		}
+		categories[category.ID] = true
+	}
+	statuses := map[uint]bool{}
+	for i, status := range backup.Statuses {
+		if statuses[status.ID] {
+			verr.add(fmt.Sprintf("statuses[%d].id", i), "unique", ErrBackupDuplicateID)
+		}
+		statuses[status.ID] = true
+	}
+	todos := map[uint]bool{}
+	for i, todo := range backup.Todos {
+		if todos[todo.ID] {
+			verr.add(fmt.Sprintf("todos[%d].id", i), "unique", ErrBackupDuplicateID)
+		}
+		todos[todo.ID] = true
+	}
+
+	for i, todo := range backup.Todos {
+		if todo.CategoryID != nil && !categories[*todo.CategoryID] {
+			verr.add(fmt.Sprintf("todos[%d].category_id", i), "exists", ErrBackupMissingRef)
+		}
+		if todo.StatusID != nil && !statuses[*todo.StatusID] {
+			verr.add(fmt.Sprintf("todos[%d].status_id", i), "exists", ErrBackupMissingRef)
+		}
+	}
+	for i, dependency := range backup.Dependencies {
+		if !todos[dependency.TodoID] || !todos[dependency.BlockerID] {
+			verr.add(fmt.Sprintf("dependencies[%d]", i), "exists", ErrBackupMissingRef)
+		}
+	}
+	for i, comment := range backup.Comments {
+		if !todos[comment.TodoID] {
+			verr.add(fmt.Sprintf("comments[%d].todo_id", i), "exists", ErrBackupMissingRef)
+		}
+	}
+	for i, item := range backup.ChecklistItems {
+		if !todos[item.TodoID] {
+			verr.add(fmt.Sprintf("checklist_items[%d].todo_id", i), "exists", ErrBackupMissingRef)
+		}
+	}
+	for i, entry := range backup.TimeEntries {
+		if !todos[entry.TodoID] {
+			verr.add(fmt.Sprintf("time_entries[%d].todo_id", i), "exists", ErrBackupMissingRef)
+		}
+	}
+	for i, list := range backup.SmartLists {
+		if list.Filter.CategoryID != nil && !categories[*list.Filter.CategoryID] {
+			verr.add(fmt.Sprintf("smart_lists[%d].filter.category_id", i), "exists", ErrBackupMissingRef)
+		}
+		if list.Filter.StatusID != nil && !statuses[*list.Filter.StatusID] {
+			verr.add(fmt.Sprintf("smart_lists[%d].filter.status_id", i), "exists", ErrBackupMissingRef)
+		}
+	}
+
+	validateBackupRecords(verr, backup)
+	return verr.orNil()
+}
+
+// validateBackupRecords checks the fields of every record, naming problems
+// by the record's place in the backup
+func validateBackupRecords(verr *ValidationError, backup *models.Backup) {
+	categories := make(map[uint]*models.Category, len(backup.Categories))
+	for i := range backup.Categories {
+		category := &backup.Categories[i]
+		categories[category.ID] = category
+		record := &ValidationError{}
+		if strings.TrimSpace(category.Name) == "" {
+			record.add("name", "required", ErrCategoryNameRequired)
+		}
+		validateCategoryFields(record, category.Name, category.Color)
+		validateCustomFieldDefinitions(record, category.CustomFields)
+		verr.addAll(fmt.Sprintf("categories[%d].", i), record)
+	}
+
+	for i, status := range backup.Statuses {
+		field := fmt.Sprintf("statuses[%d].", i)
+		if strings.TrimSpace(status.Name) == "" {
+			verr.add(field+"name", "required", ErrStatusNameRequired)
+		}
+		if status.WIPLimit != nil && *status.WIPLimit < 1 {
+			verr.add(field+"wip_limit", "min", ErrInvalidWIPLimit)
+		}
+	}
+
+	for i, todo := range backup.Todos {
+		record := &ValidationError{}
+		if todo.Title == "" {
+			record.add("title", "required", ErrTodoTitleRequired)
+		} else if utf8.RuneCountInString(todo.Title) > maxTodoTitleLength {
+			record.add("title", "max", ErrTodoTitleTooLong)
+		}
+		if todo.Priority != "" && !isValidPriority(todo.Priority) {
+			record.add("priority", "oneof", ErrInvalidPriority)
+		}
+		if todo.DueDate != nil && !isValidDueDate(*todo.DueDate) {
+			record.add("due_date", "range", ErrDueDateOutOfRange)
+		}
+		validateEstimate(record, todo.EstimateMinutes)
+		validateRecurrence(record, todo.Recurrence)
+		validateTagNames(record, todo.Tags)
+		validatePosition(record, todo.Position)
+		var category *models.Category
+		if todo.CategoryID != nil {
+			category = categories[*todo.CategoryID]
+		}
+		mergeCustomFields(record, category, nil, todo.CustomFields, false)
+		verr.addAll(fmt.Sprintf("todos[%d].", i), record)
+	}
+
+	for i, comment := range backup.Comments {
+		var record *ValidationError
+		if errors.As(validateCommentBody(comment.Body), &record) {
+			verr.addAll(fmt.Sprintf("comments[%d].", i), record)
+		}
+	}
+
+	for i, item := range backup.ChecklistItems {
+		record := &ValidationError{}
+		var title *ValidationError
+		if errors.As(validateChecklistTitle(strings.TrimSpace(item.Title)), &title) {
+			record.addAll("", title)
+		}
+		validatePosition(record, item.Position)
+		verr.addAll(fmt.Sprintf("checklist_items[%d].", i), record)
+	}
+
+	for i, entry := range backup.TimeEntries {
+		field := fmt.Sprintf("time_entries[%d].", i)
+		if entry.EndedAt != nil && entry.EndedAt.Before(entry.StartedAt) {
+			verr.add(field+"ended_at", "gtfield", ErrTimeEntryOrder)
+		}
+		if utf8.RuneCountInString(entry.Note) > maxTimeEntryNoteLength {
+			verr.add(field+"note", "max", ErrTimeEntryNoteLength)
+		}
+	}
+
+	for i, list := range backup.SmartLists {
+		record := &ValidationError{}
+		validateSmartList(record, list.Name, models.TodoFilter(list.Filter))
+		verr.addAll(fmt.Sprintf("smart_lists[%d].", i), record)
+	}
+}
+
+// validatePosition checks a restored rank; an empty one is ranked on the
+// next start
+func validatePosition(verr *ValidationError, position string) {
+	if position != "" && !isValidRank(position) {
+		verr.add("position", "rank", ErrBackupPosition)
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+const (
+	maxBulkItems     = 1000
+	maxTagNameLength = 50
+)
+
+var (
+	ErrBulkSelectorRequired = errors.New("provide either ids or filter")
+	ErrBulkTooManyItems     = errors.New("bulk operations are limited to 1000 todos")
+	ErrInvalidBulkAction    = errors.New("action must be one of complete, uncomplete, delete, set_priority, move_to_category, add_tag or remove_tag")
+	ErrTagNameRequired      = errors.New("tag is required")
+	ErrTagNameTooLong       = errors.New("tag must be at most 50 characters")
+
+	errBulkRolledBack = errors.New("bulk operation rolled back")
+)
+
+// Bulk applies a single action to many todos inside one transaction. Each
+// item runs in its own savepoint so failures are reported per item; with
+// AllOrNothing the first failure rolls back the whole batch
+func (s *todoService) Bulk(req models.BulkTodoRequest) (*models.BulkTodoResponse, error) {
+	req.Tag = strings.TrimSpace(req.Tag)
+	if err := s.validateBulk(req); err != nil {
+		return nil, err
+	}
+
+	response := &models.BulkTodoResponse{Action: req.Action}
+	// Blobs of deleted todos, removed only once the deletion is committed
+	blobs := map[uint][]string{}
+	err := s.repo.Transaction(func(tx repository.TodoRepository) error {
+		ids := req.IDs
+		if req.Filter != nil {
+			filter, err := resolveFilter(*req.Filter, time.Now())
+			if err != nil {
+				return err
+			}
+			if ids, err = tx.FindIDs(filter); err != nil {
+				return err
+			}
+		}
+		ids = uniqueIDs(ids)
+		if len(ids) > maxBulkItems {
+			return ErrBulkTooManyItems
+		}
+
+		for i, id := range ids {
+			if req.Action == models.BulkActionDelete {
+				keys, err := s.blobKeys(id)
+				if err != nil {
+					return err
+				}
+				blobs[id] = keys
+			}
+
+			err := tx.Transaction(func(item repository.TodoRepository) error {
+				return s.applyBulkAction(item, id, req)
+			})
+			if err == nil {
+				response.Results = append(response.Results, models.BulkItemResult{ID: id, Status: models.BulkStatusOK})
+				continue
+			}
+
+			response.Results = append(response.Results, models.BulkItemResult{
+				ID:     id,
+				Status: models.BulkStatusFailed,
+				Error:  err.Error(),
+			})
+			if req.AllOrNothing {
+				for _, skipped := range ids[i+1:] {
+					response.Results = append(response.Results, models.BulkItemResult{ID: skipped, Status: models.BulkStatusSkipped})
+				}
+				return errBulkRolledBack
+			}
+		}
+		return nil
+	})
+
+	if errors.Is(err, errBulkRolledBack) {
+		response.RolledBack = true
+		for i := range response.Results {
+			if response.Results[i].Status == models.BulkStatusOK {
+				response.Results[i].Status = models.BulkStatusRolledBack
+			}
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, result := range response.Results {
+		switch result.Status {
+		case models.BulkStatusOK:
+			response.Succeeded++
+			s.removeBlobs(blobs[result.ID])
+		case models.BulkStatusFailed:
+			response.Failed++
+		}
+	}
+	return response, nil
+}
+
+func (s *todoService) validateBulk(req models.BulkTodoRequest) error {
+	verr := &ValidationError{}
+
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		verr.add("ids", "required_without", ErrBulkSelectorRequired)
+	}
+	if len(req.IDs) > maxBulkItems {
+		verr.add("ids", "max", ErrBulkTooManyItems)
+	}
+
+	switch req.Action {
+	case models.BulkActionComplete, models.BulkActionUncomplete, models.BulkActionDelete:
+	case models.BulkActionSetPriority:
+		if !isValidPriority(req.Priority) {
+			verr.add("priority", "oneof", ErrInvalidPriority)
+		}
+	case models.BulkActionMoveToCategory:
+		// A nil category moves the todos out of any category
+		s.validateFields(verr, "", "", nil, req.CategoryID)
+	case models.BulkActionAddTag, models.BulkActionRemoveTag:
+		if req.Tag == "" {
+			verr.add("tag", "required", ErrTagNameRequired)
+		} else if utf8.RuneCountInString(req.Tag) > maxTagNameLength {
+			verr.add("tag", "max", ErrTagNameTooLong)
+		}
+	default:
+		verr.add("action", "oneof", ErrInvalidBulkAction)
+	}
+
+	return verr.orNil()
+}
+
+func (s *todoService) applyBulkAction(repo repository.TodoRepository, id uint, req models.BulkTodoRequest) error {
+	todo, err := repo.GetByID(id)
+	if err != nil {
+		return ErrTodoNotFound
+	}
+
+	wasCompleted := todo.Completed
+	switch req.Action {
+	case models.BulkActionDelete:
+		return repo.Delete(id, todo.Version)
+	case models.BulkActionComplete, models.BulkActionUncomplete:
+		todo.Completed = req.Action == models.BulkActionComplete
+		if err := s.checkBlockers(repo, todo, wasCompleted); err != nil {
+			return err
+		}
+		if err := s.syncStatus(repo, todo, todo.StatusID, false); err != nil {
+			return err
+		}
+	case models.BulkActionSetPriority:
+		todo.Priority = req.Priority
+	case models.BulkActionMoveToCategory:
+		// Values of fields the new category lacks are dropped; a todo
+		// missing one of its required fields fails on its own
+		verr := &ValidationError{}
+		category := s.validateFields(verr, "", "", nil, req.CategoryID)
+		todo.CustomFields = mergeCustomFields(verr, category, todo.CustomFields, nil, true)
+		if err := verr.orNil(); err != nil {
+			return err
+		}
+		todo.CategoryID = req.CategoryID
+		todo.Category = nil
+	case models.BulkActionAddTag:
+		if err := repo.AddTag(id, req.Tag); err != nil {
+			return err
+		}
+	case models.BulkActionRemoveTag:
+		if err := repo.RemoveTag(id, req.Tag); err != nil {
+			return err
+		}
+	}
+
+	// Saving also bumps the version and updated_at for tag changes
+	if err := versionError(repo.Update(todo)); err != nil {
+		return err
+	}
+	return s.repeat(repo, todo, wasCompleted)
+}
+
+// uniqueIDs drops repeated IDs while keeping their first-seen order
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/ical"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var (
+	ErrCalDAVCalendarNotFound = errors.New("calendar not found")
+	ErrCalDAVObjectNotFound   = errors.New("calendar object not found")
+	ErrCalDAVObjectExists     = errors.New("calendar object already exists")
+	ErrCalDAVInvalidData      = errors.New("calendar data must be a VCALENDAR holding exactly one VTODO")
+	ErrCalDAVUIDConflict      = errors.New("another calendar object already has this UID")
+	ErrCalDAVReservedName     = errors.New("names of the form todo-<id>.ics are reserved")
+)
+
+// CalDAVService serves categories as CalDAV calendars and their todos as
+// VTODO objects. Changes go through TodoService, so they are validated and
+// recorded like any other
+type CalDAVService interface {
+	Calendars() ([]models.CalDAVCalendar, error)
+	Calendar(id uint) (*models.CalDAVCalendar, error)
+	Objects(calendarID uint, query models.CalDAVQuery) ([]models.CalDAVObject, error)
+	Object(calendarID uint, name string) (*models.CalDAVObject, error)
+	Put(calendarID uint, name string, data []byte, expectedVersion *uint, createOnly bool) (*models.CalDAVObject, bool, error)
+	Delete(calendarID uint, name string, expectedVersion *uint) error
+}
+
+type caldavService struct {
+	categoryRepo repository.CategoryRepository
+	resources    repository.CalendarResourceRepository
+	todos        TodoService
+}
+
+func NewCalDAVService(categoryRepo repository.CategoryRepository, resources repository.CalendarResourceRepository, todos TodoService) CalDAVService {
+	return &caldavService{categoryRepo: categoryRepo, resources: resources, todos: todos}
+}
+
+func (s *caldavService) Calendars() ([]models.CalDAVCalendar, error) {
+	categories, err := s.categoryRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	calendars := make([]models.CalDAVCalendar, 0, len(categories))
+	for _, category := range categories {
+		state, err := s.todos.ListState(models.TodoFilter{CategoryID: &category.ID})
+		if err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, models.CalDAVCalendar{Category: category, State: state})
+	}
+	return calendars, nil
+}
+
+func (s *caldavService) Calendar(id uint) (*models.CalDAVCalendar, error) {
+	category, err := s.categoryRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrCalDAVCalendarNotFound
+	}
+	state, err := s.todos.ListState(models.TodoFilter{CategoryID: &id})
+	if err != nil {
+		return nil, err
+	}
+	return &models.CalDAVCalendar{Category: *category, State: state}, nil
+}
+
+// Objects lists the todos of a calendar matching query
+func (s *caldavService) Objects(calendarID uint, query models.CalDAVQuery) ([]models.CalDAVObject, error) {
+	if _, err := s.categoryRepo.GetByID(calendarID); err != nil {
+		return nil, ErrCalDAVCalendarNotFound
+	}
+
+	var todos []*models.Todo
+	filter := models.TodoFilter{CategoryID: &calendarID, Completed: query.Completed}
+	err := s.todos.Export(filter, func(todo *models.Todo) error {
+		if todo.DueDate != nil {
+			if query.DueFrom != nil && todo.DueDate.Before(*query.DueFrom) {
+				return nil
+			}
+			if query.DueTo != nil && !todo.DueDate.Before(*query.DueTo) {
+				return nil
+			}
+		}
+		todos = append(todos, todo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(todos) == 0 {
+		return []models.CalDAVObject{}, nil
+	}
+
+	ids := make([]uint, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+	resources, err := s.resources.GetByTodoIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byTodo := make(map[uint]models.CalendarResource, len(resources))
+	for _, resource := range resources {
+		byTodo[resource.TodoID] = resource
+	}
+
+	objects := make([]models.CalDAVObject, len(todos))
+	for i, todo := range todos {
+		objects[i] = calDAVObject(todo, byTodo[todo.ID])
+	}
+	return objects, nil
+}
+
+// Object finds a todo of a calendar by the name it is served under
+func (s *caldavService) Object(calendarID uint, name string) (*models.CalDAVObject, error) {
+	resource, err := s.resources.GetByName(name)
+	if err != nil {
+		// Todos created outside CalDAV go by their default name, unless a
+		// resource renamed them
+		id, ok := defaultObjectID(name)
+		if !ok {
+			return nil, ErrCalDAVObjectNotFound
+		}
+		resources, err := s.resources.GetByTodoIDs([]uint{id})
+		if err != nil {
+			return nil, err
+		}
+		if len(resources) > 0 {
+			return nil, ErrCalDAVObjectNotFound
+		}
+		resource = &models.CalendarResource{TodoID: id}
+	}
+
+	todo, err := s.todos.GetByID(resource.TodoID)
+	if err != nil {
+		return nil, ErrCalDAVObjectNotFound
+	}
+	if todo.CategoryID == nil || *todo.CategoryID != calendarID {
+		return nil, ErrCalDAVObjectNotFound
+	}
+	object := calDAVObject(todo, *resource)
+	return &object, nil
+}
+
+// Put creates or replaces the object stored under name from an iCalendar
+// body. Replacing overwrites every field a VTODO carries, so properties the
+// client dropped are cleared; the category stays that of the calendar.
+// Created todos keep the client's name and UID. createOnly refuses to
+// replace, as If-None-Match: * asks
+func (s *caldavService) Put(calendarID uint, name string, data []byte, expectedVersion *uint, createOnly bool) (*models.CalDAVObject, bool, error) {
+	if _, err := s.categoryRepo.GetByID(calendarID); err != nil {
+		return nil, false, ErrCalDAVCalendarNotFound
+	}
+	vtodo, err := decodeCalDAVData(data)
+	if err != nil {
+		return nil, false, err
+	}
+	req, _, parseErr := vtodoRequest(vtodo)
+	uid := strings.TrimSpace(vtodo.Text("UID"))
+	if uid == "" {
+		parseErr.add("uid", "required", errors.New("uid is required"))
+	}
+	if err := parseErr.orNil(); err != nil {
+		return nil, false, err
+	}
+
+	existing, err := s.Object(calendarID, name)
+	switch {
+	case errors.Is(err, ErrCalDAVObjectNotFound):
+		object, err := s.create(calendarID, name, uid, req, expectedVersion)
+		return object, err == nil, err
+	case err != nil:
+		return nil, false, err
+	case createOnly:
+		return nil, false, ErrCalDAVObjectExists
+	}
+
+	patch := models.PatchTodoRequest{
+		Title:       models.Nullable[string]{Set: true, Value: req.Title},
+		Description: models.Nullable[string]{Set: true, Value: req.Description},
+		Completed:   models.Nullable[bool]{Set: true, Value: req.Completed},
+		Priority:    models.Nullable[models.Priority]{Set: true, Null: req.Priority == "", Value: req.Priority},
+		DueDate:     models.Nullable[time.Time]{Set: true, Null: req.DueDate == nil},
+		Recurrence:  models.Nullable[string]{Set: true, Null: req.Recurrence == "", Value: req.Recurrence},
+	}
+	if req.DueDate != nil {
+		patch.DueDate.Value = *req.DueDate
+	}
+	todo, err := s.todos.Patch(existing.Todo.ID, patch, expectedVersion)
+	if err != nil {
+		return nil, false, err
+	}
+	object := calDAVObject(todo, models.CalendarResource{Name: existing.Name, UID: existing.UID})
+	return &object, false, nil
+}
+
+func (s *caldavService) create(calendarID uint, name, uid string, req models.CreateTodoRequest, expectedVersion *uint) (*models.CalDAVObject, error) {
+	// If-Match on a missing object can never match
+	if expectedVersion != nil {
+		return nil, ErrVersionConflict
+	}
+	if _, ok := defaultObjectID(name); ok {
+		return nil, ErrCalDAVReservedName
+	}
+	if s.uidTaken(uid) {
+		return nil, ErrCalDAVUIDConflict
+	}
+
+	req.CategoryID = &calendarID
+	// Tags are not synced, so CATEGORIES only matter on import
+	req.Tags = nil
+	todo, err := s.todos.Create(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := models.CalendarResource{TodoID: todo.ID, Name: name, UID: uid}
+	if err := s.resources.Save(&resource); err != nil {
+		// Without its resource the client could not find the todo again
+		if deleteErr := s.todos.Delete(todo.ID, nil); deleteErr != nil {
+			return nil, fmt.Errorf("%w (and the todo could not be removed: %v)", err, deleteErr)
+		}
+		return nil, err
+	}
+	object := calDAVObject(todo, resource)
+	return &object, nil
+}
+
+// uidTaken reports whether a todo already goes by uid
+func (s *caldavService) uidTaken(uid string) bool {
+	if resource, err := s.resources.GetByUID(uid); err == nil {
+		if _, err := s.todos.GetByID(resource.TodoID); err == nil {
+			return true
+		}
+	}
+	id, ok := defaultUIDID(uid)
+	if !ok {
+		return false
+	}
+	_, err := s.todos.GetByID(id)
+	return err == nil
+}
+
+func (s *caldavService) Delete(calendarID uint, name string, expectedVersion *uint) error {
+	object, err := s.Object(calendarID, name)
+	if err != nil {
+		return err
+	}
+	return s.todos.Delete(object.Todo.ID, expectedVersion)
+}
+
+// CalDAVData describes an object as the VCALENDAR served for it
+func CalDAVData(object *models.CalDAVObject) *ical.Component {
+	calendar := ical.NewComponent("VCALENDAR")
+	calendar.Add("VERSION", "2.0")
+	calendar.Add("PRODID", CalendarProductID)
+
+	vtodo := TodoVTODO(object.Todo)
+	vtodo.Set("UID", object.UID)
+	calendar.Children = append(calendar.Children, vtodo)
+	return calendar
+}
+
+// calDAVObject serves todo under the name and UID of its resource, or the
+// default ones when it has none
+func calDAVObject(todo *models.Todo, resource models.CalendarResource) models.CalDAVObject {
+	object := models.CalDAVObject{Name: resource.Name, UID: resource.UID, Todo: todo}
+	if object.Name == "" {
+		object.Name = fmt.Sprintf("todo-%d.ics", todo.ID)
+	}
+	if object.UID == "" {
+		object.UID = TodoUID(todo.ID)
+	}
+	return object
+}
+
+// defaultObjectID reads the todo ID out of a default object name
+func defaultObjectID(name string) (uint, bool) {
+	id, ok := strings.CutPrefix(name, "todo-")
+	if !ok {
+		return 0, false
+	}
+	if id, ok = strings.CutSuffix(id, ".ics"); !ok {
+		return 0, false
+	}
+	return parseObjectID(id)
+}
+
+// defaultUIDID reads the todo ID out of a UID made by TodoUID
+func defaultUIDID(uid string) (uint, bool) {
+	id, ok := strings.CutPrefix(uid, "todo-")
+	if !ok {
+		return 0, false
+	}
+	if id, ok = strings.CutSuffix(id, "@"+calendarUIDDomain); !ok {
+		return 0, false
+	}
+	return parseObjectID(id)
+}
+
+func parseObjectID(s string) (uint, bool) {
+	id, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || id == 0 || strconv.FormatUint(id, 10) != s {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// decodeCalDAVData returns the single VTODO of a calendar object body
+func decodeCalDAVData(data []byte) (*ical.Component, error) {
+	calendar, err := ical.Decode(bytes.NewReader(data))
+	if err != nil || calendar.Name != "VCALENDAR" {
+		return nil, ErrCalDAVInvalidData
+	}
+	vtodos := calendar.Components("VTODO")
+	if len(vtodos) != 1 {
+		return nil, ErrCalDAVInvalidData
+	}
+	return vtodos[0], nil
+}
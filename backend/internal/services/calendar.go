@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/ical"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+// CalendarProductID identifies us in the calendars we write
+const CalendarProductID = "-//Industrix//Todo App//EN"
+
+// calendarUIDDomain makes the UIDs of our todos globally unique
+const calendarUIDDomain = "industrix-todo"
+
+var (
+	ErrCalendarTokenInvalid    = errors.New("calendar token is invalid or has been revoked")
+	ErrCalendarTokenNotFound   = errors.New("no calendar token to revoke")
+	ErrCalendarInvalid         = errors.New("the file is not an iCalendar file")
+	ErrCalendarInvalidDue      = errors.New("due must be an iCalendar date or date-time")
+	ErrCalendarInvalidPriority = errors.New("priority must be a number from 0 to 9")
+)
+
+// calendarPriorities maps priorities to iCalendar ones, where 1-4 are high,
+// 5 medium and 6-9 low
+var calendarPriorities = map[models.Priority]int{
+	models.PriorityHigh:   1,
+	models.PriorityMedium: 5,
+	models.PriorityLow:    9,
+}
+
+type CalendarService interface {
+	CreateToken(user string) (string, error)
+	RevokeToken(user string) error
+	Authenticate(token string) (string, error)
+}
+
+type calendarService struct {
+	repo repository.CalendarTokenRepository
+}
+
+func NewCalendarService(repo repository.CalendarTokenRepository) CalendarService {
+	return &calendarService{repo: repo}
+}
+
+// CreateToken returns a new feed token for user, revoking the previous one
+func (s *calendarService) CreateToken(user string) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	err := s.repo.Save(&models.CalendarToken{
+		UserName:  user,
+		TokenHash: hashCalendarToken(token),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *calendarService) RevokeToken(user string) error {
+	deleted, err := s.repo.DeleteByUser(user)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCalendarTokenNotFound
+	}
+	return nil
+}
+
+// Authenticate returns the user a feed token belongs to
+func (s *calendarService) Authenticate(token string) (string, error) {
+	if token == "" {
+		return "", ErrCalendarTokenInvalid
+	}
+	record, err := s.repo.GetByHash(hashCalendarToken(token))
+	if err != nil {
+		return "", ErrCalendarTokenInvalid
+	}
+	return record.UserName, nil
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TodoUID returns the iCalendar UID of a todo
+func TodoUID(id uint) string {
+	return fmt.Sprintf("todo-%d@%s", id, calendarUIDDomain)
+}
+
+// TodoVTODO describes a todo as a VTODO. Due dates at midnight UTC, as
+// set from a plain date, are written as dates
+func TodoVTODO(todo *models.Todo) *ical.Component {
+	c := ical.NewComponent("VTODO")
+	c.Add("UID", TodoUID(todo.ID))
+	c.Add("DTSTAMP", ical.FormatDateTime(todo.UpdatedAt))
+	c.Add("CREATED", ical.FormatDateTime(todo.CreatedAt))
+	c.Add("LAST-MODIFIED", ical.FormatDateTime(todo.UpdatedAt))
+	if todo.Version > 0 {
+		c.Add("SEQUENCE", strconv.FormatUint(uint64(todo.Version-1), 10))
+	}
+	c.AddText("SUMMARY", todo.Title)
+	if todo.Description != "" {
+		c.AddText("DESCRIPTION", todo.Description)
+	}
+	if todo.DueDate != nil {
+		c.AddTime("DUE", *todo.DueDate, isWholeDay(*todo.DueDate))
+	}
+	if todo.Recurrence != "" {
+		c.Add("RRULE", todo.Recurrence)
+	}
+	if priority, ok := calendarPriorities[todo.Priority]; ok {
+		c.Add("PRIORITY", strconv.Itoa(priority))
+	}
+	if todo.Category != nil {
+		c.AddText("CATEGORIES", todo.Category.Name)
+	}
+	if todo.Completed {
+		c.Add("STATUS", "COMPLETED")
+		c.Add("COMPLETED", ical.FormatDateTime(todo.UpdatedAt))
+		c.Add("PERCENT-COMPLETE", "100")
+	} else {
+		c.Add("STATUS", "NEEDS-ACTION")
+	}
+	return c
+}
+
+// TodoVEvent describes the due date of a todo as a VEVENT, for calendar
+// apps that do not show VTODOs. The todo must have a due date
+func TodoVEvent(todo *models.Todo) *ical.Component {
+	c := ical.NewComponent("VEVENT")
+	c.Add("UID", fmt.Sprintf("todo-%d-due@%s", todo.ID, calendarUIDDomain))
+	c.Add("DTSTAMP", ical.FormatDateTime(todo.UpdatedAt))
+	c.Add("LAST-MODIFIED", ical.FormatDateTime(todo.UpdatedAt))
+	c.AddText("SUMMARY", todo.Title)
+	if todo.Description != "" {
+		c.AddText("DESCRIPTION", todo.Description)
+	}
+	// Without DTEND a date lasts the whole day and a date-time is an instant
+	c.AddTime("DTSTART", *todo.DueDate, isWholeDay(*todo.DueDate))
+	c.Add("TRANSP", "TRANSPARENT")
+	if todo.Category != nil {
+		c.AddText("CATEGORIES", todo.Category.Name)
+	}
+	return c
+}
+
+func isWholeDay(t time.Time) bool {
+	t = t.UTC()
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
+
+// ImportCalendar creates a todo per VTODO of an iCalendar file, like
+// Import does for CSV records. The first of its CATEGORIES names the
+// category and the others become tags. Other components, such as VEVENTs,
+// are ignored
+func (s *todoService) ImportCalendar(req models.CalendarImportRequest) (*models.TodoImportReport, error) {
+	calendar, err := ical.Decode(bytes.NewReader(req.Data))
+	if err == nil && calendar.Name != "VCALENDAR" {
+		err = fmt.Errorf("found %s instead of VCALENDAR", calendar.Name)
+	}
+	if err != nil {
+		verr := &ValidationError{}
+		verr.add("file", "format", fmt.Errorf("%w: %v", ErrCalendarInvalid, err))
+		return nil, verr
+	}
+
+	var rows []importRow
+	for _, vtodo := range calendar.Components("VTODO") {
+		createReq, category, parseErr := vtodoRequest(vtodo)
+		rows = append(rows, importRow{line: vtodo.Line, req: createReq, category: category, errs: parseErr})
+	}
+	if err := checkImportSize(len(rows)); err != nil {
+		return nil, err
+	}
+
+	report := newImportReport()
+	if err := s.importRows(rows, report, req.DryRun); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// vtodoRequest converts a VTODO into a create request, along with the
+// category name to resolve. Times without a zone are taken as UTC, and an
+// RRULE must be one Recurrence can express
+func vtodoRequest(c *ical.Component) (models.CreateTodoRequest, string, *ValidationError) {
+	verr := &ValidationError{}
+	req := models.CreateTodoRequest{
+		Title:       strings.TrimSpace(c.Text("SUMMARY")),
+		Description: c.Text("DESCRIPTION"),
+	}
+
+	if p, ok := c.Get("DUE"); ok {
+		due, _, err := ical.ParseTime(p, time.UTC)
+		if err != nil {
+			verr.add(models.ImportFieldDueDate, "format", ErrCalendarInvalidDue)
+		} else {
+			req.DueDate = &due
+		}
+	}
+
+	req.Recurrence = validateRecurrence(verr, c.Text("RRULE"))
+
+	if p, ok := c.Get("PRIORITY"); ok {
+		priority, err := strconv.Atoi(strings.TrimSpace(p.Value))
+		switch {
+		case err != nil || priority < 0 || priority > 9:
+			verr.add(models.ImportFieldPriority, "format", ErrCalendarInvalidPriority)
+		case priority == 0:
+			// 0 means undefined
+		case priority <= 4:
+			req.Priority = models.PriorityHigh
+		case priority == 5:
+			req.Priority = models.PriorityMedium
+		default:
+			req.Priority = models.PriorityLow
+		}
+	}
+
+	_, hasCompleted := c.Get("COMPLETED")
+	req.Completed = strings.EqualFold(c.Text("STATUS"), "COMPLETED") || hasCompleted
+
+	var categories []string
+	for _, p := range c.All("CATEGORIES") {
+		for _, name := range ical.SplitText(p.Value) {
+			if name = strings.TrimSpace(name); name != "" {
+				categories = append(categories, name)
+			}
+		}
+	}
+	var category string
+	if len(categories) > 0 {
+		category = categories[0]
+		req.Tags = categories[1:]
+	}
+	return req, category, verr
+}
@@ -2,22 +2,29 @@ package services
 
 import (
 	"errors"
+	"unicode/utf8"
 
 	"github.com/industrix-todo-app/backend/internal/models"
 	"github.com/industrix-todo-app/backend/internal/repository"
 )
 
 var (
-	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryNotFound     = errors.New("category not found")
 	ErrCategoryNameRequired = errors.New("category name is required")
+	ErrCategoryNameTooLong  = errors.New("category name must be at most 100 characters")
+	ErrInvalidColor         = errors.New("color must be a hex color such as #3B82F6")
 )
 
+const defaultCategoryColor = "#3B82F6"
+
 type CategoryService interface {
 	Create(req models.CreateCategoryRequest) (*models.Category, error)
 	GetAll() ([]models.Category, error)
+	ListState() (models.ListState, error)
 	GetByID(id uint) (*models.Category, error)
-	Update(id uint, req models.UpdateCategoryRequest) (*models.Category, error)
-	Delete(id uint) error
+	Update(id uint, req models.UpdateCategoryRequest, expectedVersion *uint) (*models.Category, error)
+	Patch(id uint, req models.PatchCategoryRequest, expectedVersion *uint) (*models.Category, error)
+	Delete(id uint, expectedVersion *uint) error
 }
 
 type categoryService struct {
@@ -29,17 +36,25 @@ func NewCategoryService(repo repository.CategoryRepository) CategoryService {
 }
 
 func (s *categoryService) Create(req models.CreateCategoryRequest) (*models.Category, error) {
+	verr := &ValidationError{}
 	if req.Name == "" {
-		return nil, ErrCategoryNameRequired
+		verr.add("name", "required", ErrCategoryNameRequired)
+	}
+	validateCategoryFields(verr, req.Name, req.Color)
+	validateCustomFieldDefinitions(verr, req.CustomFields)
+	if err := verr.orNil(); err != nil {
+		return nil, err
 	}
 
 	category := &models.Category{
-		Name:  req.Name,
-		Color: req.Color,
+		Name:         req.Name,
+		Color:        req.Color,
+		CustomFields: req.CustomFields,
+		Version:      1,
 	}
 
 	if category.Color == "" {
-		category.Color = "#3B82F6"
+		category.Color = defaultCategoryColor
 	}
 
 	if err := s.repo.Create(category); err != nil {
@@ -53,6 +68,10 @@ func (s *categoryService) GetAll() ([]models.Category, error) {
 	return s.repo.GetAll()
 }
 
+func (s *categoryService) ListState() (models.ListState, error) {
+	return s.repo.ListState()
+}
+
 func (s *categoryService) GetByID(id uint) (*models.Category, error) {
 	category, err := s.repo.GetByID(id)
 	if err != nil {
@@ -61,31 +80,100 @@ func (s *categoryService) GetByID(id uint) (*models.Category, error) {
 	return category, nil
 }
 
-func (s *categoryService) Update(id uint, req models.UpdateCategoryRequest) (*models.Category, error) {
+// Update replaces a category; fields left out of the request go back to
+// their defaults
+func (s *categoryService) Update(id uint, req models.UpdateCategoryRequest, expectedVersion *uint) (*models.Category, error) {
 	category, err := s.repo.GetByID(id)
 	if err != nil {
 		return nil, ErrCategoryNotFound
 	}
+	if err := checkVersion(category.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	verr := &ValidationError{}
+	if req.Name == "" {
+		verr.add("name", "required", ErrCategoryNameRequired)
+	}
+	validateCategoryFields(verr, req.Name, req.Color)
+	validateCustomFieldDefinitions(verr, req.CustomFields)
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
 
-	if req.Name != "" {
-		category.Name = req.Name
+	category.Name = req.Name
+	category.Color = req.Color
+	if category.Color == "" {
+		category.Color = defaultCategoryColor
 	}
-	if req.Color != "" {
-		category.Color = req.Color
+	category.CustomFields = req.CustomFields
+	if category.CustomFields == nil {
+		category.CustomFields = models.CustomFieldDefinitions{}
 	}
 
 	if err := s.repo.Update(category); err != nil {
+		return nil, versionError(err)
+	}
+
+	return category, nil
+}
+
+func (s *categoryService) Patch(id uint, req models.PatchCategoryRequest, expectedVersion *uint) (*models.Category, error) {
+	category, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, ErrCategoryNotFound
+	}
+	if err := checkVersion(category.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	verr := &ValidationError{}
+	if req.Name.Set && (req.Name.Null || req.Name.Value == "") {
+		verr.add("name", "required", ErrCategoryNameRequired)
+	}
+	validateCategoryFields(verr, req.Name.Value, req.Color.Value)
+	validateCustomFieldDefinitions(verr, req.CustomFields.Value)
+	if err := verr.orNil(); err != nil {
 		return nil, err
 	}
 
+	if req.Name.Set {
+		category.Name = req.Name.Value
+	}
+	if req.Color.Set {
+		category.Color = req.Color.Value
+		if req.Color.Null || category.Color == "" {
+			category.Color = defaultCategoryColor
+		}
+	}
+	if req.CustomFields.Set {
+		category.CustomFields = req.CustomFields.Value
+	}
+
+	if err := s.repo.Update(category); err != nil {
+		return nil, versionError(err)
+	}
+
 	return category, nil
 }
 
-func (s *categoryService) Delete(id uint) error {
-	_, err := s.repo.GetByID(id)
+func (s *categoryService) Delete(id uint, expectedVersion *uint) error {
+	category, err := s.repo.GetByID(id)
 	if err != nil {
 		return ErrCategoryNotFound
 	}
+	if err := checkVersion(category.Version, expectedVersion); err != nil {
+		return err
+	}
 
-	return s.repo.Delete(id)
+	return versionError(s.repo.Delete(id, category.Version))
+}
+
+func validateCategoryFields(verr *ValidationError, name, color string) {
+	if utf8.RuneCountInString(name) > maxCategoryNameLength {
+		verr.add("name", "max", ErrCategoryNameTooLong)
+	}
+	if color != "" && !isValidColor(color) {
+		verr.add("color", "hexcolor", ErrInvalidColor)
+	}
 }
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var (
+	ErrChecklistItemNotFound   = errors.New("checklist item not found")
+	ErrChecklistTitleRequired  = errors.New("checklist item title is required")
+	ErrChecklistTitleTooLong   = errors.New("checklist item title must be at most 255 characters")
+	ErrChecklistFull           = errors.New("a checklist can hold at most 200 items")
+	ErrChecklistAnchorNotFound = errors.New("anchor item not found in this checklist")
+)
+
+const maxChecklistItems = 200
+
+type ChecklistService interface {
+	List(todoID uint) ([]models.ChecklistItem, error)
+	Add(todoID uint, req models.CreateChecklistItemRequest) (*models.ChecklistItem, error)
+	Update(todoID, id uint, req models.UpdateChecklistItemRequest) (*models.ChecklistItem, error)
+	Move(todoID, id uint, req models.MoveChecklistItemRequest) (*models.ChecklistItem, error)
+	Delete(todoID, id uint) error
+}
+
+type checklistService struct {
+	repo     repository.ChecklistRepository
+	todoRepo repository.TodoRepository
+}
+
+func NewChecklistService(repo repository.ChecklistRepository, todoRepo repository.TodoRepository) ChecklistService {
+	return &checklistService{repo: repo, todoRepo: todoRepo}
+}
+
+func (s *checklistService) List(todoID uint) ([]models.ChecklistItem, error) {
+	if _, err := s.todoRepo.GetByID(todoID); err != nil {
+		return nil, ErrTodoNotFound
+	}
+	return s.repo.GetByTodo(todoID)
+}
+
+// Add appends an item to the end of a todo's checklist
+func (s *checklistService) Add(todoID uint, req models.CreateChecklistItemRequest) (*models.ChecklistItem, error) {
+	if _, err := s.todoRepo.GetByID(todoID); err != nil {
+		return nil, ErrTodoNotFound
+	}
+	title := strings.TrimSpace(req.Title)
+	if err := validateChecklistTitle(title); err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.Count(todoID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxChecklistItems {
+		return nil, ErrChecklistFull
+	}
+
+	last, err := s.repo.MaxPosition(todoID)
+	if err != nil {
+		return nil, err
+	}
+	position := RankAfter(last)
+	if !rankFits(position) {
+		if last, err = s.rebalance(todoID); err != nil {
+			return nil, err
+		}
+		position = RankAfter(last)
+	}
+
+	item := &models.ChecklistItem{
+		TodoID:   todoID,
+		Title:    title,
+		Checked:  req.Checked,
+		Position: position,
+	}
+	if err := s.repo.Create(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Update renames an item and/or checks or unchecks it
+func (s *checklistService) Update(todoID, id uint, req models.UpdateChecklistItemRequest) (*models.ChecklistItem, error) {
+	item, err := s.find(todoID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if err := validateChecklistTitle(title); err != nil {
+			return nil, err
+		}
+		item.Title = title
+	}
+	if req.Checked != nil {
+		item.Checked = *req.Checked
+	}
+
+	if err := s.repo.Update(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Move places an item between its anchors. With a single anchor the item
+// lands right next to it
+func (s *checklistService) Move(todoID, id uint, req models.MoveChecklistItemRequest) (*models.ChecklistItem, error) {
+	item, err := s.find(todoID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	verr := &ValidationError{}
+	if req.Before == nil && req.After == nil {
+		verr.add("before", "required_without", ErrMoveAnchorRequired)
+	}
+	if (req.Before != nil && *req.Before == id) || (req.After != nil && *req.After == id) {
+		verr.add("before", "self", ErrMoveAnchorSelf)
+	}
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
+
+	// Checklists are short, so neighbours are found on the loaded list
+	all, err := s.repo.GetByTodo(todoID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]models.ChecklistItem, 0, len(all))
+	for _, other := range all {
+		if other.ID != id {
+			items = append(items, other)
+		}
+	}
+
+	lower, upper := "", ""
+	switch {
+	case req.After != nil && req.Before != nil:
+		after, before := checklistIndex(items, *req.After), checklistIndex(items, *req.Before)
+		if after < 0 || before < 0 {
+			verr.add("after", "exists", ErrChecklistAnchorNotFound)
+			return nil, verr
+		}
+		if after >= before {
+			verr.add("after", "order", ErrMoveAnchorOrder)
+			return nil, verr
+		}
+		lower, upper = items[after].Position, items[before].Position
+	case req.After != nil:
+		after := checklistIndex(items, *req.After)
+		if after < 0 {
+			verr.add("after", "exists", ErrChecklistAnchorNotFound)
+			return nil, verr
+		}
+		lower = items[after].Position
+		if after+1 < len(items) {
+			upper = items[after+1].Position
+		}
+	default:
+		before := checklistIndex(items, *req.Before)
+		if before < 0 {
+			verr.add("before", "exists", ErrChecklistAnchorNotFound)
+			return nil, verr
+		}
+		upper = items[before].Position
+		if before > 0 {
+			lower = items[before-1].Position
+		}
+	}
+
+	item.Position = RankBetween(lower, upper)
+	if !rankFits(item.Position) {
+		// Respace the checklist and place the item again
+		if _, err := s.rebalance(todoID); err != nil {
+			return nil, err
+		}
+		return s.Move(todoID, id, req)
+	}
+	if err := s.repo.Update(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// rebalance gives every item of a checklist a new short rank, keeping their
+// order, and returns the last rank
+func (s *checklistService) rebalance(todoID uint) (string, error) {
+	items, err := s.repo.GetByTodo(todoID)
+	if err != nil || len(items) == 0 {
+		return "", err
+	}
+	ranks := RanksBetween("", "", len(items))
+	positions := make(map[uint]string, len(items))
+	for i, item := range items {
+		positions[item.ID] = ranks[i]
+	}
+	if err := s.repo.SetPositions(positions); err != nil {
+		return "", err
+	}
+	return ranks[len(ranks)-1], nil
+}
+
+func (s *checklistService) Delete(todoID, id uint) error {
+	if _, err := s.find(todoID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *checklistService) find(todoID, id uint) (*models.ChecklistItem, error) {
+	item, err := s.repo.GetByID(id)
+	if err != nil || item.TodoID != todoID {
+		return nil, ErrChecklistItemNotFound
+	}
+	return item, nil
+}
+
+func checklistIndex(items []models.ChecklistItem, id uint) int {
+	for i, item := range items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// validateChecklistTitles trims the titles of a checklist created along
+// with its todo, reporting errors under field
+func validateChecklistTitles(verr *ValidationError, field string, titles []string) []string {
+	if len(titles) > maxChecklistItems {
+		verr.add(field, "max", ErrChecklistFull)
+		return nil
+	}
+	trimmed := make([]string, len(titles))
+	for i, title := range titles {
+		trimmed[i] = strings.TrimSpace(title)
+		itemField := fmt.Sprintf("%s[%d]", field, i)
+		if trimmed[i] == "" {
+			verr.add(itemField, "required", ErrChecklistTitleRequired)
+		} else if utf8.RuneCountInString(trimmed[i]) > maxTodoTitleLength {
+			verr.add(itemField, "max", ErrChecklistTitleTooLong)
+		}
+	}
+	return trimmed
+}
+
+func validateChecklistTitle(title string) error {
+	verr := &ValidationError{}
+	if title == "" {
+		verr.add("title", "required", ErrChecklistTitleRequired)
+	} else if utf8.RuneCountInString(title) > maxTodoTitleLength {
+		verr.add("title", "max", ErrChecklistTitleTooLong)
+	}
+	return verr.orNil()
+}
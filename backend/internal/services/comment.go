@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var (
+	ErrCommentNotFound     = errors.New("comment not found")
+	ErrCommentBodyRequired = errors.New("comment body is required")
+	ErrCommentBodyTooLong  = errors.New("comment body must be at most 10000 characters")
+	ErrCommentForbidden    = errors.New("only the author can change a comment")
+)
+
+type CommentService interface {
+	Create(todoID uint, author string, req models.CreateCommentRequest) (*models.Comment, error)
+	List(todoID uint, page, limit int) (*models.PaginatedResponse, error)
+	Update(todoID, id uint, author string, req models.UpdateCommentRequest) (*models.Comment, error)
+	Delete(todoID, id uint, author string) error
+}
+
+type commentService struct {
+	repo     repository.CommentRepository
+	todoRepo repository.TodoRepository
+}
+
+func NewCommentService(repo repository.CommentRepository, todoRepo repository.TodoRepository) CommentService {
+	return &commentService{repo: repo, todoRepo: todoRepo}
+}
+
+func (s *commentService) Create(todoID uint, author string, req models.CreateCommentRequest) (*models.Comment, error) {
+	if _, err := s.todoRepo.GetByID(todoID); err != nil {
+		return nil, ErrTodoNotFound
+	}
+	if err := validateCommentBody(req.Body); err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		TodoID: todoID,
+		Author: author,
+		Body:   req.Body,
+	}
+	if err := s.repo.Create(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) List(todoID uint, page, limit int) (*models.PaginatedResponse, error) {
+	if _, err := s.todoRepo.GetByID(todoID); err != nil {
+		return nil, ErrTodoNotFound
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	comments, total, err := s.repo.GetByTodo(todoID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaginatedResponse{
+		Data: comments,
+		Pagination: models.Pagination{
+			CurrentPage: page,
+			PerPage:     limit,
+			Total:       total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(limit))),
+		},
+	}, nil
+}
+
+func (s *commentService) Update(todoID, id uint, author string, req models.UpdateCommentRequest) (*models.Comment, error) {
+	comment, err := s.find(todoID, id, author)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCommentBody(req.Body); err != nil {
+		return nil, err
+	}
+
+	comment.Body = req.Body
+	if err := s.repo.Update(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *commentService) Delete(todoID, id uint, author string) error {
+	if _, err := s.find(todoID, id, author); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+// find loads a comment of the given todo that author may change
+func (s *commentService) find(todoID, id uint, author string) (*models.Comment, error) {
+	comment, err := s.repo.GetByID(id)
+	if err != nil || comment.TodoID != todoID {
+		return nil, ErrCommentNotFound
+	}
+	if comment.Author != author {
+		return nil, ErrCommentForbidden
+	}
+	return comment, nil
+}
+
+func validateCommentBody(body string) error {
+	verr := &ValidationError{}
+	if strings.TrimSpace(body) == "" {
+		verr.add("body", "required", ErrCommentBodyRequired)
+	} else if utf8.RuneCountInString(body) > maxCommentBodyLength {
+		verr.add("body", "max", ErrCommentBodyTooLong)
+	}
+	return verr.orNil()
+}
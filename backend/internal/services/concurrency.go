@@ -0,0 +1,27 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var ErrVersionConflict = errors.New("resource was modified by another request")
+
+// checkVersion fails when the caller expects a different version than the
+// one currently stored. A nil expectation always passes
+func checkVersion(current uint, expected *uint) error {
+	if expected != nil && *expected != current {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// versionError maps a concurrent write detected by the repository to
+// ErrVersionConflict
+func versionError(err error) error {
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return ErrVersionConflict
+	}
+	return err
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+var (
+	ErrTooManyCustomFields        = errors.New("a category can define at most 50 custom fields")
+	ErrCustomFieldKeyInvalid      = errors.New("key must start with a letter and contain only lowercase letters, digits and underscores (at most 50)")
+	ErrCustomFieldKeyDuplicate    = errors.New("key is already used by another field")
+	ErrCustomFieldNameRequired    = errors.New("name is required and must be at most 100 characters")
+	ErrCustomFieldTypeInvalid     = errors.New("type must be one of text, number, date, select or checkbox")
+	ErrCustomFieldOptionsRequired = errors.New("select fields need between 1 and 100 distinct, non-empty options")
+	ErrCustomFieldUnknown         = errors.New("field is not defined for the todo's category")
+	ErrCustomFieldRequired        = errors.New("field is required")
+	ErrCustomFieldText            = errors.New("value must be text of at most 1000 characters")
+	ErrCustomFieldNumber          = errors.New("value must be a number")
+	ErrCustomFieldDate            = errors.New("value must be a date formatted as YYYY-MM-DD")
+	ErrCustomFieldOption          = errors.New("value must be one of the field's options")
+	ErrCustomFieldCheckbox        = errors.New("value must be true or false")
+)
+
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+const (
+	maxCustomFields          = 50
+	maxCustomFieldOptions    = 100
+	maxCustomFieldNameLength = 100
+	maxCustomFieldTextLength = 1000
+)
+
+// IsValidCustomFieldKey reports whether key can name a custom field
+func IsValidCustomFieldKey(key string) bool {
+	return customFieldKeyPattern.MatchString(key)
+}
+
+// validateCustomFieldDefinitions checks the fields a category declares
+func validateCustomFieldDefinitions(verr *ValidationError, defs models.CustomFieldDefinitions) {
+	if len(defs) > maxCustomFields {
+		verr.add("custom_fields", "max", ErrTooManyCustomFields)
+		return
+	}
+
+	seen := make(map[string]bool, len(defs))
+	for i, def := range defs {
+		field := fmt.Sprintf("custom_fields[%d]", i)
+		if !IsValidCustomFieldKey(def.Key) {
+			verr.add(field+".key", "format", ErrCustomFieldKeyInvalid)
+		} else if seen[def.Key] {
+			verr.add(field+".key", "unique", ErrCustomFieldKeyDuplicate)
+		}
+		seen[def.Key] = true
+
+		name := strings.TrimSpace(def.Name)
+		if name == "" || utf8.RuneCountInString(name) > maxCustomFieldNameLength {
+			verr.add(field+".name", "required", ErrCustomFieldNameRequired)
+		}
+
+		switch def.Type {
+		case models.CustomFieldText, models.CustomFieldNumber, models.CustomFieldDate, models.CustomFieldCheckbox:
+		case models.CustomFieldSelect:
+			if !validOptions(def.Options) {
+				verr.add(field+".options", "required", ErrCustomFieldOptionsRequired)
+			}
+		default:
+			verr.add(field+".type", "oneof", ErrCustomFieldTypeInvalid)
+		}
+	}
+}
+
+func validOptions(options []string) bool {
+	if len(options) == 0 || len(options) > maxCustomFieldOptions {
+		return false
+	}
+	seen := make(map[string]bool, len(options))
+	for _, option := range options {
+		if strings.TrimSpace(option) == "" || seen[option] {
+			return false
+		}
+		seen[option] = true
+	}
+	return true
+}
+
+// mergeCustomFields applies changes to current and validates the result
+// against the fields of category. Keys set to null are removed, and values of
+// fields the category does not define (any longer) are dropped. Required
+// fields are only enforced when checkRequired is set, so old todos stay
+// editable after a category gains a required field
+func mergeCustomFields(verr *ValidationError, category *models.Category, current, changes models.CustomFieldValues, checkRequired bool) models.CustomFieldValues {
+	var defs models.CustomFieldDefinitions
+	if category != nil {
+		defs = category.CustomFields
+	}
+
+	merged := models.CustomFieldValues{}
+	for key, value := range current {
+		if _, ok := defs.Find(key); ok {
+			merged[key] = value
+		}
+	}
+
+	for key, value := range changes {
+		field := "custom_fields." + key
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		def, ok := defs.Find(key)
+		if !ok {
+			verr.add(field, "defined", ErrCustomFieldUnknown)
+			continue
+		}
+		normalized, err := customFieldValue(def, value)
+		if err != nil {
+			verr.add(field, string(def.Type), err)
+			continue
+		}
+		merged[key] = normalized
+	}
+
+	if checkRequired {
+		for _, def := range defs {
+			if _, ok := merged[def.Key]; def.Required && !ok {
+				verr.add("custom_fields."+def.Key, "required", ErrCustomFieldRequired)
+			}
+		}
+	}
+	return merged
+}
+
+// customFieldValue checks value against the field type, returning it in
+// the form it is stored in
+func customFieldValue(def models.CustomFieldDefinition, value interface{}) (interface{}, error) {
+	switch def.Type {
+	case models.CustomFieldText:
+		s, ok := value.(string)
+		if !ok || utf8.RuneCountInString(s) > maxCustomFieldTextLength {
+			return nil, ErrCustomFieldText
+		}
+		return s, nil
+	case models.CustomFieldNumber:
+		switch n := value.(type) {
+		case float64:
+			return n, nil
+		case int:
+			return float64(n), nil
+		case int64:
+			return float64(n), nil
+		}
+		return nil, ErrCustomFieldNumber
+	case models.CustomFieldDate:
+		s, ok := value.(string)
+		if !ok {
+			return nil, ErrCustomFieldDate
+		}
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, ErrCustomFieldDate
+		}
+		return s, nil
+	case models.CustomFieldSelect:
+		s, ok := value.(string)
+		if ok {
+			for _, option := range def.Options {
+				if s == option {
+					return s, nil
+				}
+			}
+		}
+		return nil, ErrCustomFieldOption
+	case models.CustomFieldCheckbox:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, ErrCustomFieldCheckbox
+		}
+		return b, nil
+	}
+	return nil, ErrCustomFieldUnknown
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var (
+	ErrBlockerNotFound  = errors.New("blocker todo not found")
+	ErrBlockerSelf      = errors.New("a todo cannot block itself")
+	ErrDependencyCycle  = errors.New("dependency would create a cycle")
+	ErrTodoBlocked      = errors.New("todo has incomplete blockers")
+	ErrDependencyAbsent = errors.New("todo is not blocked by that todo")
+)
+
+// Blockers returns the todos that must be completed before id
+func (s *todoService) Blockers(id uint) ([]models.Todo, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, ErrTodoNotFound
+	}
+	return s.repo.Blockers(id)
+}
+
+// Blocking returns the todos waiting on id
+func (s *todoService) Blocking(id uint) ([]models.Todo, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, ErrTodoNotFound
+	}
+	return s.repo.Blocking(id)
+}
+
+// AddBlocker marks id as blocked by blockerID. Links that would close a loop
+// are refused, since none of the todos in it could ever be completed. The
+// check and the link run under a lock, so two requests linking the same
+// todos both ways cannot both pass it
+func (s *todoService) AddBlocker(id, blockerID uint) ([]models.Todo, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, ErrTodoNotFound
+	}
+
+	verr := &ValidationError{}
+	if blockerID == id {
+		verr.add("blocker_id", "self", ErrBlockerSelf)
+	} else if _, err := s.repo.GetByID(blockerID); err != nil {
+		verr.add("blocker_id", "exists", ErrBlockerNotFound)
+	}
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.Transaction(func(repo repository.TodoRepository) error {
+		if err := repo.LockDependencies(); err != nil {
+			return err
+		}
+		cyclic, err := reaches(repo.BlockerIDs, blockerID, id)
+		if err != nil {
+			return err
+		}
+		if cyclic {
+			return ErrDependencyCycle
+		}
+		return repo.AddBlocker(id, blockerID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Blockers(id)
+}
+
+// RemoveBlocker unlinks blockerID from id
+func (s *todoService) RemoveBlocker(id, blockerID uint) error {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return ErrTodoNotFound
+	}
+
+	blockers, err := s.repo.BlockerIDs([]uint{id})
+	if err != nil {
+		return err
+	}
+	for _, b := range blockers {
+		if b == blockerID {
+			return s.repo.RemoveBlocker(id, blockerID)
+		}
+	}
+	return ErrDependencyAbsent
+}
+
+// reaches reports whether target is among the transitive blockers of from,
+// walking the dependency graph one level at a time with blockerIDs
+func reaches(blockerIDs func(todoIDs []uint) ([]uint, error), from, target uint) (bool, error) {
+	visited := map[uint]bool{from: true}
+	frontier := []uint{from}
+	for len(frontier) > 0 {
+		next, err := blockerIDs(frontier)
+		if err != nil {
+			return false, err
+		}
+		frontier = frontier[:0]
+		for _, id := range next {
+			if id == target {
+				return true, nil
+			}
+			if !visited[id] {
+				visited[id] = true
+				frontier = append(frontier, id)
+			}
+		}
+	}
+	return false, nil
+}
+
+// checkBlockers refuses to complete a todo that still has open blockers when
+// the service enforces dependencies
+func (s *todoService) checkBlockers(repo repository.TodoRepository, todo *models.Todo, wasCompleted bool) error {
+	if !s.enforceBlockers || wasCompleted || !todo.Completed {
+		return nil
+	}
+	open, err := repo.OpenBlockerCount(todo.ID)
+	if err != nil {
+		return err
+	}
+	if open > 0 {
+		return ErrTodoBlocked
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+// upcomingDays is how far ahead the upcoming window looks
+const upcomingDays = 7
+
+var (
+	ErrInvalidDueWindow = errors.New("due must be one of overdue, today, tomorrow or upcoming")
+	ErrInvalidDueRange  = errors.New("due_from must be before due_to")
+)
+
+// IsValidDueWindow reports whether due names a relative due date window
+func IsValidDueWindow(due models.DueWindow) bool {
+	switch due {
+	case models.DueOverdue, models.DueToday, models.DueTomorrow, models.DueUpcoming:
+		return true
+	}
+	return false
+}
+
+// filterLocation returns the time zone a filter's days are counted in.
+// Unknown zones are taken as UTC
+func filterLocation(filter models.TodoFilter) *time.Location {
+	loc, err := time.LoadLocation(filter.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// dueWindowBounds returns the due dates a relative window spans from the
+// day starting at today. Overdue has no lower bound, so from is zero
+func dueWindowBounds(due models.DueWindow, today time.Time) (from, to time.Time, ok bool) {
+	switch due {
+	case models.DueOverdue:
+		return time.Time{}, today, true
+	case models.DueToday:
+		return today, today.AddDate(0, 0, 1), true
+	case models.DueTomorrow:
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), true
+	case models.DueUpcoming:
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 1+upcomingDays), true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// startOfDay returns midnight of the day t falls on, in t's location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// resolveDueWindow narrows the due date bounds of a filter to its relative
+// window, with days starting at midnight in the filter's time zone
+func resolveDueWindow(filter models.TodoFilter, now time.Time) models.TodoFilter {
+	if filter.Due == "" {
+		return filter
+	}
+	from, to, ok := dueWindowBounds(filter.Due, startOfDay(now.In(filterLocation(filter))))
+	if !ok {
+		return filter
+	}
+	if !from.IsZero() && (filter.DueFrom == nil || from.After(*filter.DueFrom)) {
+		filter.DueFrom = &from
+	}
+	if filter.DueTo == nil || to.Before(*filter.DueTo) {
+		filter.DueTo = &to
+	}
+	return filter
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var (
+	ErrIdempotencyKeyReused  = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is still being processed")
+	ErrIdempotencyKeyTooLong = errors.New("idempotency key must be at most 255 characters")
+	errIdempotencyRecordGone = errors.New("idempotency record disappeared while being claimed")
+)
+
+const maxIdempotencyKeyLength = 255
+
+// idempotencyClaimTimeout is how long a claimed key may go without a
+// response before it is taken as left behind by a crashed request
+const idempotencyClaimTimeout = 5 * time.Minute
+
+type IdempotencyService interface {
+	Begin(scope, key, requestHash string) (*models.IdempotencyRecord, error)
+	Complete(scope, key string, statusCode int, body []byte) error
+	Abandon(scope, key string) error
+	PurgeExpired() (int64, error)
+}
+
+type idempotencyService struct {
+	repo repository.IdempotencyRepository
+	ttl  time.Duration
+}
+
+func NewIdempotencyService(repo repository.IdempotencyRepository, ttl time.Duration) IdempotencyService {
+	return &idempotencyService{repo: repo, ttl: ttl}
+}
+
+// Begin claims key for a new request, or returns the stored record when the
+// same request was already completed. A claimed record has no status yet
+// and must be finished with Complete or Abandon; claims older than
+// idempotencyClaimTimeout are taken over
+func (s *idempotencyService) Begin(scope, key, requestHash string) (*models.IdempotencyRecord, error) {
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, ErrIdempotencyKeyTooLong
+	}
+
+	now := time.Now()
+	record := &models.IdempotencyRecord{
+		Scope:          scope,
+		IdempotencyKey: key,
+		RequestHash:    requestHash,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(s.ttl),
+	}
+	created, err := s.repo.CreateIfAbsent(record, now.Add(-idempotencyClaimTimeout))
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		return record, nil
+	}
+
+	existing, err := s.repo.Get(scope, key)
+	if err != nil {
+		return nil, errIdempotencyRecordGone
+	}
+	if existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !existing.Completed() {
+		return nil, ErrIdempotencyInProgress
+	}
+	return existing, nil
+}
+
+// Complete stores the response to replay for retries of the request
+func (s *idempotencyService) Complete(scope, key string, statusCode int, body []byte) error {
+	return s.repo.Complete(scope, key, statusCode, body)
+}
+
+// Abandon releases a claimed key without storing a response so the client
+// can retry, used when the request failed on the server side
+func (s *idempotencyService) Abandon(scope, key string) error {
+	return s.repo.Delete(scope, key)
+}
+
+// PurgeExpired removes stored responses whose TTL has passed
+func (s *idempotencyService) PurgeExpired() (int64, error) {
+	return s.repo.DeleteExpired(time.Now())
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+const markdownDate = "2006-01-02"
+
+var ErrInvalidMarkdownGroup = errors.New("group_by must be one of category or due")
+
+// markdownBadges labels priorities so they stand out in rendered lists
+var markdownBadges = map[models.Priority]string{
+	models.PriorityHigh:   "🔴 high",
+	models.PriorityMedium: "🟡 medium",
+	models.PriorityLow:    "🟢 low",
+}
+
+// markdownEscaper escapes the characters that would turn a title into
+// emphasis, code, links or HTML
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `<`, `\<`, `>`, `\>`,
+)
+
+// TodoMarkdown collects todos and writes them as a Markdown document of
+// GitHub task lists, one section per category or per due date. Todos keep
+// the order they were added in within their section
+type TodoMarkdown struct {
+	groupBy models.MarkdownGroup
+	groups  map[string]*markdownSection
+}
+
+type markdownSection struct {
+	title string
+	// order sorts sections; the empty order, for todos without a category
+	// or due date, comes last
+	order string
+	todos []*models.Todo
+}
+
+// NewTodoMarkdown returns an empty document grouped by groupBy, by
+// category when it is empty
+func NewTodoMarkdown(groupBy models.MarkdownGroup) (*TodoMarkdown, error) {
+	if groupBy == "" {
+		groupBy = models.MarkdownByCategory
+	}
+	if groupBy != models.MarkdownByCategory && groupBy != models.MarkdownByDue {
+		verr := &ValidationError{}
+		verr.add("group_by", "oneof", ErrInvalidMarkdownGroup)
+		return nil, verr
+	}
+	return &TodoMarkdown{groupBy: groupBy, groups: map[string]*markdownSection{}}, nil
+}
+
+// Add places a todo in its section
+func (m *TodoMarkdown) Add(todo *models.Todo) {
+	key, order, title := "", "", "No category"
+	if m.groupBy == models.MarkdownByDue {
+		title = "No due date"
+		if todo.DueDate != nil {
+			key = todo.DueDate.Format(markdownDate)
+			order, title = key, key+", "+todo.DueDate.Weekday().String()
+		}
+	} else if todo.Category != nil {
+		key = strconv.FormatUint(uint64(todo.Category.ID), 10)
+		order, title = strings.ToLower(todo.Category.Name)+"\x00"+key, todo.Category.Name
+	}
+
+	section, ok := m.groups[key]
+	if !ok {
+		section = &markdownSection{title: title, order: order}
+		m.groups[key] = section
+	}
+	section.todos = append(section.todos, todo)
+}
+
+// Write writes the document. Sections are sorted by category name or by
+// date
+func (m *TodoMarkdown) Write(w io.Writer) error {
+	sections := make([]*markdownSection, 0, len(m.groups))
+	for _, section := range m.groups {
+		sections = append(sections, section)
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		a, b := sections[i].order, sections[j].order
+		if a == "" || b == "" {
+			return b == ""
+		}
+		return a < b
+	})
+
+	out := bufio.NewWriter(w)
+	out.WriteString("# Todos\n")
+	if len(sections) == 0 {
+		out.WriteString("\n_No todos._\n")
+	}
+	for _, section := range sections {
+		out.WriteString("\n## " + markdownEscaper.Replace(section.title) + "\n\n")
+		for _, todo := range section.todos {
+			out.WriteString(m.formatTodo(todo))
+		}
+	}
+	return out.Flush()
+}
+
+// formatTodo writes a todo as a task list item, with its description
+// quoted beneath it. The due date is left out when it heads the section
+func (m *TodoMarkdown) formatTodo(todo *models.Todo) string {
+	var b strings.Builder
+	if todo.Completed {
+		b.WriteString("- [x] ")
+	} else {
+		b.WriteString("- [ ] ")
+	}
+	b.WriteString(markdownEscaper.Replace(strings.Join(strings.Fields(todo.Title), " ")))
+	if badge, ok := markdownBadges[todo.Priority]; ok {
+		b.WriteString(" `" + badge + "`")
+	}
+	if todo.DueDate != nil && m.groupBy != models.MarkdownByDue {
+		b.WriteString(" · due " + todo.DueDate.Format(markdownDate))
+	}
+	b.WriteString("\n")
+
+	description := strings.TrimSpace(strings.ReplaceAll(todo.Description, "\r\n", "\n"))
+	if description != "" {
+		for _, line := range strings.Split(description, "\n") {
+			b.WriteString(strings.TrimRight("  > "+line, " ") + "\n")
+		}
+	}
+	return b.String()
+}
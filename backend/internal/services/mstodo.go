@@ -0,0 +1,125 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+var (
+	htmlHiddenPattern = regexp.MustCompile(`(?is)<(head|style|script)\b.*?</(head|style|script)>`)
+	htmlBreakPattern  = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>`)
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+)
+
+// microsoftTodoImportance maps task importance to priorities; normal is
+// left to our default
+var microsoftTodoImportance = map[string]models.Priority{
+	"high": models.PriorityHigh,
+	"low":  models.PriorityLow,
+}
+
+// microsoftTodoExport holds lists with their tasks as Microsoft Graph
+// describes them, either under "lists" or, as Graph responds, "value"
+type microsoftTodoExport struct {
+	Lists []microsoftTodoList `json:"lists"`
+	Value []microsoftTodoList `json:"value"`
+}
+
+type microsoftTodoList struct {
+	DisplayName string              `json:"displayName"`
+	Tasks       []microsoftTodoTask `json:"tasks"`
+}
+
+type microsoftTodoTask struct {
+	Title string `json:"title"`
+	Body  struct {
+		Content     string `json:"content"`
+		ContentType string `json:"contentType"`
+	} `json:"body"`
+	Importance  string `json:"importance"`
+	Status      string `json:"status"`
+	DueDateTime *struct {
+		DateTime string `json:"dateTime"`
+		TimeZone string `json:"timeZone"`
+	} `json:"dueDateTime"`
+	Categories     []string `json:"categories"`
+	ChecklistItems []struct {
+		DisplayName string `json:"displayName"`
+		IsChecked   bool   `json:"isChecked"`
+	} `json:"checklistItems"`
+}
+
+// parseMicrosoftTodoExport reads Microsoft To Do lists exported as JSON.
+// Each list becomes a category and its tasks todos, with their Outlook
+// categories as tags and their steps as checklist items. Rows are numbered
+// by the position of the task across all lists
+func parseMicrosoftTodoExport(data []byte) ([]importRow, error) {
+	var export microsoftTodoExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	lists := append(export.Lists, export.Value...)
+	if lists == nil {
+		return nil, errors.New("no lists found")
+	}
+
+	var rows []importRow
+	position := 0
+	for _, list := range lists {
+		for _, task := range list.Tasks {
+			position++
+			row := importRow{
+				line: position,
+				req: models.CreateTodoRequest{
+					Title:       strings.TrimSpace(task.Title),
+					Description: microsoftTodoBody(task.Body.Content, task.Body.ContentType),
+					Completed:   task.Status == "completed",
+					Priority:    microsoftTodoImportance[strings.ToLower(task.Importance)],
+					Tags:        task.Categories,
+				},
+				category: strings.TrimSpace(list.DisplayName),
+				errs:     &ValidationError{},
+			}
+			if task.DueDateTime != nil && task.DueDateTime.DateTime != "" {
+				if due, err := microsoftTodoTime(task.DueDateTime.DateTime, task.DueDateTime.TimeZone); err != nil {
+					row.errs.add(models.ImportFieldDueDate, "format", ErrImportInvalidDate)
+				} else {
+					row.req.DueDate = &due
+				}
+			}
+			for _, item := range task.ChecklistItems {
+				row.addChecklistItem(strings.TrimSpace(item.DisplayName), item.IsChecked)
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// microsoftTodoTime reads a Graph dateTime, which has no offset, in its
+// time zone. Zones Go does not know, such as Windows names, are taken as
+// UTC
+func microsoftTodoTime(value, zone string) (time.Time, error) {
+	loc, err := time.LoadLocation(zone)
+	if err != nil || zone == "" {
+		loc = time.UTC
+	}
+	return time.ParseInLocation("2006-01-02T15:04:05", value, loc)
+}
+
+// microsoftTodoBody returns a task body as plain text
+func microsoftTodoBody(content, contentType string) string {
+	if !strings.EqualFold(contentType, "html") {
+		return content
+	}
+	content = htmlHiddenPattern.ReplaceAllString(content, "")
+	content = htmlBreakPattern.ReplaceAllString(content, "\n")
+	content = html.UnescapeString(htmlTagPattern.ReplaceAllString(content, ""))
+	return strings.TrimSpace(content)
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var (
+	ErrMoveAnchorRequired = errors.New("provide before and/or after")
+	ErrMoveAnchorNotFound = errors.New("anchor todo not found")
+	ErrMoveAnchorSelf     = errors.New("a todo cannot be moved relative to itself")
+	ErrMoveAnchorCategory = errors.New("anchor todo must be in the same category")
+	ErrMoveAnchorStatus   = errors.New("anchor todo must be in the target status")
+	ErrMoveAnchorOrder    = errors.New("after must come before before")
+)
+
+// Move places a todo between its anchors by giving it a rank between theirs.
+// With a single anchor the todo lands next to it, ahead of any neighbour.
+// When a status is given the todo first moves to that board column
+func (s *todoService) Move(id uint, req models.MoveTodoRequest) (*models.Todo, error) {
+	var moved *models.Todo
+	err := s.repo.Transaction(func(repo repository.TodoRepository) error {
+		if err := repo.LockPositions(); err != nil {
+			return err
+		}
+		var err error
+		moved, err = s.move(repo, id, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+func (s *todoService) move(repo repository.TodoRepository, id uint, req models.MoveTodoRequest) (*models.Todo, error) {
+	todo, err := repo.GetByID(id)
+	if err != nil {
+		return nil, ErrTodoNotFound
+	}
+
+	scope, scopeID := models.PositionScopeCategory, todo.CategoryID
+	wasCompleted := todo.Completed
+	if req.StatusID != nil {
+		previous := todo.StatusID
+		todo.StatusID = req.StatusID
+		if err := s.syncStatus(repo, todo, previous, true); err != nil {
+			return nil, err
+		}
+		if err := s.checkBlockers(repo, todo, wasCompleted); err != nil {
+			return nil, err
+		}
+		scope, scopeID = models.PositionScopeStatus, todo.StatusID
+	}
+
+	verr := &ValidationError{}
+	if req.Before == nil && req.After == nil && req.StatusID == nil {
+		verr.add("before", "required_without", ErrMoveAnchorRequired)
+	}
+	after := s.moveAnchor(repo, verr, "after", todo, scope, req.After)
+	before := s.moveAnchor(repo, verr, "before", todo, scope, req.Before)
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
+
+	var lower, upper string
+	switch {
+	case after != nil && before != nil:
+		lower, upper = after.Position, before.Position
+		if lower >= upper {
+			verr.add("after", "order", ErrMoveAnchorOrder)
+			return nil, verr
+		}
+	case after != nil:
+		lower = after.Position
+		upper, err = repo.AdjacentPosition(scope, scopeID, lower, true, todo.ID)
+	case before != nil:
+		upper = before.Position
+		lower, err = repo.AdjacentPosition(scope, scopeID, upper, false, todo.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Moving to another column without anchors keeps the current rank
+	if after != nil || before != nil {
+		todo.Position = RankBetween(lower, upper)
+		if !rankFits(todo.Position) {
+			// Many moves into the same gap made the ranks too long; respace
+			// them all and place the todo again
+			if err := rebalancePositions(repo); err != nil {
+				return nil, err
+			}
+			return s.move(repo, id, req)
+		}
+	}
+	if err := repo.Update(todo); err != nil {
+		return nil, versionError(err)
+	}
+	if err := s.repeat(repo, todo, wasCompleted); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// appendPosition returns the rank of a todo added at the end of the manual
+// order, rebalancing the order first if the ranks have grown too long. It
+// runs inside a transaction, which holds the position lock until the todo
+// is stored
+func appendPosition(repo repository.TodoRepository) (string, error) {
+	if err := repo.LockPositions(); err != nil {
+		return "", err
+	}
+	last, err := repo.MaxPosition()
+	if err != nil {
+		return "", err
+	}
+	if position := RankAfter(last); rankFits(position) {
+		return position, nil
+	}
+	if err := rebalancePositions(repo); err != nil {
+		return "", err
+	}
+	if last, err = repo.MaxPosition(); err != nil {
+		return "", err
+	}
+	return RankAfter(last), nil
+}
+
+// rebalancePositions gives every ranked todo a new short rank, keeping the
+// manual order
+func rebalancePositions(repo repository.TodoRepository) error {
+	ids, err := repo.IDsByPosition()
+	if err != nil {
+		return err
+	}
+	for i, position := range RanksBetween("", "", len(ids)) {
+		if err := repo.SetPosition(ids[i], position); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BackfillPositions ranks todos that have no position yet after all ranked
+// ones, keeping their creation order
+func (s *todoService) BackfillPositions() error {
+	ids, err := s.repo.IDsWithoutPosition()
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	last, err := s.repo.MaxPosition()
+	if err != nil {
+		return err
+	}
+
+	for i, position := range RanksBetween(last, "", len(ids)) {
+		if err := s.repo.SetPosition(ids[i], position); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *todoService) moveAnchor(repo repository.TodoRepository, verr *ValidationError, field string, todo *models.Todo, scope models.PositionScope, anchorID *uint) *models.Todo {
+	if anchorID == nil {
+		return nil
+	}
+	if *anchorID == todo.ID {
+		verr.add(field, "self", ErrMoveAnchorSelf)
+		return nil
+	}
+
+	anchor, err := repo.GetByID(*anchorID)
+	if err != nil {
+		verr.add(field, "exists", ErrMoveAnchorNotFound)
+		return nil
+	}
+	if scope == models.PositionScopeStatus && !sameID(anchor.StatusID, todo.StatusID) {
+		verr.add(field, "status", ErrMoveAnchorStatus)
+		return nil
+	}
+	if scope == models.PositionScopeCategory && !sameID(anchor.CategoryID, todo.CategoryID) {
+		verr.add(field, "category", ErrMoveAnchorCategory)
+		return nil
+	}
+	return anchor
+}
+
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,463 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var (
+	ErrQuickAddTitleRequired = errors.New("text must contain a title besides the date, priority, category and tags")
+	ErrInvalidTimeZone       = errors.New("time_zone must be an IANA time zone such as Asia/Jakarta")
+)
+
+// quickAddEvening is the time "tonight" stands for
+const quickAddEvening = 20 * time.Hour
+
+var quickAddPriorities = map[string]models.Priority{
+	"high": models.PriorityHigh, "h": models.PriorityHigh, "1": models.PriorityHigh,
+	"medium": models.PriorityMedium, "med": models.PriorityMedium, "m": models.PriorityMedium, "2": models.PriorityMedium,
+	"low": models.PriorityLow, "l": models.PriorityLow, "3": models.PriorityLow,
+}
+
+var quickAddWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var quickAddMonths = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+// quickAddUnits maps the units of "every 2 weeks" and "in 3 days" to
+// recurrence frequencies
+var quickAddUnits = map[string]string{
+	"day": "DAILY", "days": "DAILY",
+	"week": "WEEKLY", "weeks": "WEEKLY",
+	"month": "MONTHLY", "months": "MONTHLY",
+	"year": "YEARLY", "years": "YEARLY",
+}
+
+var quickAddFrequencies = map[string]string{
+	"daily":    "DAILY",
+	"weekly":   "WEEKLY",
+	"monthly":  "MONTHLY",
+	"yearly":   "YEARLY",
+	"annually": "YEARLY",
+}
+
+// quickAddConnectors may precede a date or time and go with it
+var quickAddConnectors = map[string]bool{"on": true, "by": true, "due": true, "at": true}
+
+var (
+	quickAddTimePattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	quickAddDayPattern  = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+	quickAddYearPattern = regexp.MustCompile(`^\d{4}$`)
+)
+
+// ParseQuickAdd reads a todo written as one line, such as "Pay rent
+// tomorrow 9am !high #Personal every month". Relative dates are read from
+// now, in its location. The words it understands are:
+//
+//   - !high, !medium, !low (or !h, !m, !l, !1, !2, !3) for the priority
+//   - #Category for the category, later #words and @words for tags, with _
+//     standing for a space
+//   - today, tonight, tomorrow, weekdays, next week/month/<weekday>,
+//     in N days/weeks/months/years, 2026-11-01 and Nov 1 (2026) for the
+//     due date, optionally after on, by or due
+//   - 9am, 9:30pm, 21:00 and noon for the time of day, optionally after at
+//   - daily, weekly, monthly, yearly, every (N|other) day/week/month/year
+//     and every <weekday> for the recurrence
+//
+// The remaining words make up the title. A time without a date is due
+// today, or tomorrow once it has passed, and a recurring todo without a
+// date starts today
+func ParseQuickAdd(text string, now time.Time) models.QuickAddParse {
+	p := &quickAddParser{
+		words: strings.Fields(text),
+		now:   now,
+		today: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+	}
+	p.lower = make([]string, len(p.words))
+	for i, word := range p.words {
+		p.lower[i] = strings.ToLower(word)
+	}
+	p.parsed.Tags = []string{}
+
+	var title []string
+	for i := 0; i < len(p.words); i++ {
+		if n := p.match(i); n > 0 {
+			i += n - 1
+			continue
+		}
+		title = append(title, p.words[i])
+	}
+	p.parsed.Title = strings.Join(title, " ")
+
+	if p.tonight && p.clock == nil {
+		evening := quickAddEvening
+		p.clock = &evening
+	}
+	if p.recurrence != nil {
+		p.parsed.Recurrence = p.recurrence.String()
+		if p.date == nil {
+			p.date = &p.today
+		}
+	}
+	switch {
+	case p.date != nil && p.clock != nil:
+		due := atClock(*p.date, *p.clock)
+		p.parsed.DueDate = &due
+	case p.date != nil:
+		due := *p.date
+		p.parsed.DueDate = &due
+	case p.clock != nil:
+		due := atClock(p.today, *p.clock)
+		if !due.After(now) {
+			due = atClock(p.today.AddDate(0, 0, 1), *p.clock)
+		}
+		p.parsed.DueDate = &due
+	}
+	return p.parsed
+}
+
+// atClock returns the time of day on date, which is wall clock time even
+// on days daylight saving time starts or ends
+func atClock(date time.Time, clock time.Duration) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, date.Location())
+}
+
+type quickAddParser struct {
+	words []string
+	lower []string
+	now   time.Time
+	today time.Time
+
+	parsed     models.QuickAddParse
+	date       *time.Time
+	clock      *time.Duration
+	recurrence *Recurrence
+	// tonight sets the time of day when none is written
+	tonight bool
+}
+
+// match reads the words starting at i, returning how many it understood
+func (p *quickAddParser) match(i int) int {
+	word, lower := p.words[i], p.lower[i]
+	if len(word) > 1 {
+		switch word[0] {
+		case '!':
+			if priority, ok := quickAddPriorities[lower[1:]]; ok {
+				p.parsed.Priority = priority
+				return 1
+			}
+		case '#':
+			if p.parsed.Category == "" {
+				p.parsed.Category = todoTxtNameOf(word[1:])
+			} else {
+				p.parsed.Tags = append(p.parsed.Tags, todoTxtNameOf(word[1:]))
+			}
+			return 1
+		case '@':
+			p.parsed.Tags = append(p.parsed.Tags, todoTxtNameOf(word[1:]))
+			return 1
+		}
+	}
+
+	if n := p.matchRecurrence(i); n > 0 {
+		return n
+	}
+	if n := p.matchWhen(i); n > 0 {
+		return n
+	}
+	if quickAddConnectors[lower] && i+1 < len(p.words) {
+		if n := p.matchWhen(i + 1); n > 0 {
+			return n + 1
+		}
+	}
+	return 0
+}
+
+// matchWhen reads a date or a time of day. Only the first of each counts;
+// later ones are left in the title
+func (p *quickAddParser) matchWhen(i int) int {
+	if p.date == nil {
+		if date, n := p.matchDate(i); n > 0 {
+			p.date = &date
+			p.tonight = p.lower[i] == "tonight"
+			return n
+		}
+	}
+	if p.clock == nil {
+		if clock, n := p.matchTime(i); n > 0 {
+			p.clock = &clock
+			return n
+		}
+	}
+	return 0
+}
+
+func (p *quickAddParser) matchDate(i int) (time.Time, int) {
+	word := p.lower[i]
+	next := p.word(i + 1)
+	switch word {
+	case "today", "tonight":
+		return p.today, 1
+	case "tomorrow", "tmr", "tmrw":
+		return p.today.AddDate(0, 0, 1), 1
+	case "next":
+		// Weeks start on Monday
+		nextWeek := p.today.AddDate(0, 0, 7-(int(p.today.Weekday())+6)%7)
+		if weekday, ok := quickAddWeekdays[next]; ok {
+			return nextWeek.AddDate(0, 0, (int(weekday)+6)%7), 2
+		}
+		switch next {
+		case "week":
+			return nextWeek, 2
+		case "month":
+			return time.Date(p.today.Year(), p.today.Month()+1, 1, 0, 0, 0, 0, p.today.Location()), 2
+		case "year":
+			return time.Date(p.today.Year()+1, time.January, 1, 0, 0, 0, 0, p.today.Location()), 2
+		}
+	case "in":
+		count, err := strconv.Atoi(next)
+		if next == "a" || next == "an" {
+			count, err = 1, nil
+		}
+		if err != nil || count < 1 || count > maxRecurrenceInterval {
+			return time.Time{}, 0
+		}
+		switch quickAddUnits[p.word(i+2)] {
+		case "DAILY":
+			return p.today.AddDate(0, 0, count), 3
+		case "WEEKLY":
+			return p.today.AddDate(0, 0, 7*count), 3
+		case "MONTHLY":
+			return addMonths(p.today, count), 3
+		case "YEARLY":
+			return addMonths(p.today, 12*count), 3
+		}
+		return time.Time{}, 0
+	}
+
+	if weekday, ok := quickAddWeekdays[word]; ok {
+		return p.weekday(weekday, false), 1
+	}
+	if date, err := time.ParseInLocation("2006-01-02", word, p.today.Location()); err == nil {
+		return date, 1
+	}
+	// Nov 1, 1 Nov, and either followed by a year
+	if month, ok := quickAddMonths[strings.TrimSuffix(word, ".")]; ok {
+		if day, ok := quickAddDay(next); ok {
+			return p.monthDay(month, day, i+2, 2)
+		}
+	}
+	if day, ok := quickAddDay(word); ok {
+		if month, ok := quickAddMonths[strings.TrimSuffix(next, ".")]; ok {
+			return p.monthDay(month, day, i+2, 2)
+		}
+	}
+	return time.Time{}, 0
+}
+
+// monthDay returns the day of month, in the year written at yearAt or
+// else its next occurrence from today. Days the month does not have are
+// not dates
+func (p *quickAddParser) monthDay(month time.Month, day, yearAt, n int) (time.Time, int) {
+	year := p.today.Year()
+	explicitYear := quickAddYearPattern.MatchString(p.word(yearAt))
+	if explicitYear {
+		year, _ = strconv.Atoi(p.word(yearAt))
+		n++
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, p.today.Location())
+	if date.Day() != day {
+		return time.Time{}, 0
+	}
+	if !explicitYear && date.Before(p.today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return date, n
+}
+
+// weekday returns the next such day after today, or today itself when
+// includeToday is set
+func (p *quickAddParser) weekday(weekday time.Weekday, includeToday bool) time.Time {
+	days := (int(weekday) - int(p.today.Weekday()) + 7) % 7
+	if days == 0 && !includeToday {
+		days = 7
+	}
+	return p.today.AddDate(0, 0, days)
+}
+
+// matchTime reads 9am, 9 am, 9:30pm or 21:00 as a time of day. Bare
+// numbers are not times
+func (p *quickAddParser) matchTime(i int) (time.Duration, int) {
+	word, n := p.lower[i], 1
+	if word == "noon" {
+		return 12 * time.Hour, 1
+	}
+	if next := p.word(i + 1); (next == "am" || next == "pm") && !strings.Contains(word, ":") {
+		word, n = word+next, 2
+	}
+	m := quickAddTimePattern.FindStringSubmatch(word)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, 0
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	if minute > 59 {
+		return 0, 0
+	}
+	switch m[3] {
+	case "":
+		if hour > 23 {
+			return 0, 0
+		}
+	default:
+		if hour < 1 || hour > 12 {
+			return 0, 0
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, n
+}
+
+// matchRecurrence reads daily, every month, every 2 weeks, every other
+// day or every friday. A weekday also starts the todo on that day
+func (p *quickAddParser) matchRecurrence(i int) int {
+	if p.recurrence != nil {
+		return 0
+	}
+	if frequency, ok := quickAddFrequencies[p.lower[i]]; ok {
+		p.recurrence = &Recurrence{Frequency: frequency, Interval: 1}
+		return 1
+	}
+	if p.lower[i] != "every" {
+		return 0
+	}
+
+	j, interval := i+1, 1
+	if p.word(j) == "other" {
+		j, interval = j+1, 2
+	} else if count, err := strconv.Atoi(p.word(j)); err == nil && count >= 1 && count <= maxRecurrenceInterval {
+		j, interval = j+1, count
+	}
+	if frequency, ok := quickAddUnits[p.word(j)]; ok {
+		p.recurrence = &Recurrence{Frequency: frequency, Interval: interval}
+		return j - i + 1
+	}
+	if weekday, ok := quickAddWeekdays[p.word(j)]; ok && interval == 1 {
+		p.recurrence = &Recurrence{Frequency: "WEEKLY", Interval: 1}
+		if p.date == nil {
+			date := p.weekday(weekday, true)
+			p.date = &date
+		}
+		return j - i + 1
+	}
+	return 0
+}
+
+// word returns the lowercased word at i, or "" past the end
+func (p *quickAddParser) word(i int) string {
+	if i < len(p.lower) {
+		return p.lower[i]
+	}
+	return ""
+}
+
+func quickAddDay(word string) (int, bool) {
+	m := quickAddDayPattern.FindStringSubmatch(strings.TrimSuffix(word, ","))
+	if m == nil {
+		return 0, false
+	}
+	day, _ := strconv.Atoi(m[1])
+	return day, day >= 1 && day <= 31
+}
+
+// QuickAdd creates a todo from one line of text, read by ParseQuickAdd in
+// the request's time zone. The category is looked up by name and created
+// when there is none yet. A dry run reports what would be created
+func (s *todoService) QuickAdd(req models.QuickAddRequest) (*models.QuickAddResponse, error) {
+	verr := &ValidationError{}
+	loc, err := time.LoadLocation(req.TimeZone)
+	if err != nil {
+		verr.add("time_zone", "timezone", ErrInvalidTimeZone)
+		return nil, verr
+	}
+	parsed := ParseQuickAdd(req.Text, time.Now().In(loc))
+	if parsed.Title == "" {
+		verr.add("text", "required", ErrQuickAddTitleRequired)
+		return nil, verr
+	}
+
+	response := &models.QuickAddResponse{Parsed: parsed, DryRun: req.DryRun}
+	categories := &importCategories{
+		CategoryRepository: s.categoryRepo,
+		byName:             map[string]*models.Category{},
+		created:            map[uint]*models.Category{},
+	}
+	creator := *s
+	creator.categoryRepo = categories
+
+	err = s.repo.Transaction(func(tx repository.TodoRepository) error {
+		createReq := models.CreateTodoRequest{
+			Title:      parsed.Title,
+			Priority:   parsed.Priority,
+			DueDate:    parsed.DueDate,
+			Recurrence: parsed.Recurrence,
+			Tags:       parsed.Tags,
+		}
+		if parsed.Category != "" {
+			report := newImportReport()
+			category, err := categories.resolve(tx, parsed.Category, report)
+			if err != nil {
+				return err
+			}
+			createReq.CategoryID = &category.ID
+			response.Parsed.Category = category.Name
+			response.CategoryCreated = len(report.CreatedCategories) > 0
+			if !response.CategoryCreated || !req.DryRun {
+				response.CategoryID = &category.ID
+			}
+		}
+
+		todo, err := creator.create(tx, createReq, nil)
+		if err != nil {
+			return err
+		}
+		if req.DryRun {
+			return errImportRolledBack
+		}
+		response.Todo = todo
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRolledBack) {
+		return nil, err
+	}
+	return response, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"strings"
+)
+
+// Ranks are base-62 strings compared lexicographically. A rank never ends in
+// the zero digit, which guarantees there is always room for another rank
+// between any two, so moving an item only rewrites that one item
+const rankDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const (
+	// maxRankLength is the size of the position columns; lists whose ranks
+	// would grow past it are rebalanced first
+	maxRankLength = 255
+	// rankAppendWidth is the width appended ranks are counted up at, leaving
+	// room for billions of appends before they grow
+	rankAppendWidth = 6
+)
+
+// RankBetween returns a rank strictly between lower and upper. An empty
+// lower means the start of the list and an empty upper means the end
+func RankBetween(lower, upper string) string {
+	if upper != "" {
+		n := 0
+		for n < len(upper) && rankDigitAt(lower, n) == upper[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(lower) {
+				rest = lower[n:]
+			}
+			return upper[:n] + RankBetween(rest, upper[n:])
+		}
+	}
+
+	digitLower := 0
+	if lower != "" {
+		digitLower = strings.IndexByte(rankDigits, lower[0])
+	}
+	digitUpper := len(rankDigits)
+	if upper != "" {
+		digitUpper = strings.IndexByte(rankDigits, upper[0])
+	}
+
+	if digitUpper-digitLower > 1 {
+		return string(rankDigits[(digitLower+digitUpper+1)/2])
+	}
+	if len(upper) > 1 {
+		return upper[:1]
+	}
+	rest := ""
+	if lower != "" {
+		rest = lower[1:]
+	}
+	return string(rankDigits[digitLower]) + RankBetween(rest, "")
+}
+
+// RankAfter returns a rank after last for appending to a list. It counts
+// last up by one at a fixed width instead of bisecting towards the end, so
+// ranks stay the same length however many items are appended
+func RankAfter(last string) string {
+	if last == "" {
+		return RankBetween("", "")
+	}
+	digits := []byte(last)
+	for len(digits) < rankAppendWidth {
+		digits = append(digits, rankDigits[0])
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := strings.IndexByte(rankDigits, digits[i])
+		if d < len(rankDigits)-1 {
+			digits[i] = rankDigits[d+1]
+			// A carry leaves trailing zeros; a rank must not end in one
+			if i < len(digits)-1 {
+				digits[len(digits)-1] = rankDigits[1]
+			}
+			return string(digits)
+		}
+		digits[i] = rankDigits[0]
+	}
+	// Every digit is already the highest one
+	return RankBetween(last, "")
+}
+
+// rankFits reports whether rank fits the position columns
+func rankFits(rank string) bool {
+	return len(rank) <= maxRankLength
+}
+
+// isValidRank reports whether rank could have been made by RankBetween:
+// rank digits only, fitting the position columns and not ending in the
+// lowest digit
+func isValidRank(rank string) bool {
+	if rank == "" || !rankFits(rank) || rank[len(rank)-1] == rankDigits[0] {
+		return false
+	}
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(rankDigits, rank[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// RanksBetween returns n increasing ranks between lower and upper, spread
+// by bisection so the ranks stay short
+func RanksBetween(lower, upper string, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	mid := RankBetween(lower, upper)
+	left := (n - 1) / 2
+	ranks := RanksBetween(lower, mid, left)
+	ranks = append(ranks, mid)
+	return append(ranks, RanksBetween(mid, upper, n-1-left)...)
+}
+
+func rankDigitAt(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return rankDigits[0]
+}
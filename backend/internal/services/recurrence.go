@@ -0,0 +1,146 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+const maxRecurrenceInterval = 365
+
+var ErrInvalidRecurrence = errors.New("recurrence must be an RRULE with FREQ of DAILY, WEEKLY, MONTHLY or YEARLY and an INTERVAL from 1 to 365")
+
+// Recurrence is the subset of iCalendar RRULEs todos can repeat by
+type Recurrence struct {
+	Frequency string
+	Interval  int
+}
+
+var recurrenceFrequencies = map[string]bool{
+	"DAILY":   true,
+	"WEEKLY":  true,
+	"MONTHLY": true,
+	"YEARLY":  true,
+}
+
+// ParseRecurrence reads an RRULE such as FREQ=WEEKLY;INTERVAL=2. The RRULE:
+// prefix of iCalendar is accepted
+func ParseRecurrence(rule string) (Recurrence, error) {
+	r := Recurrence{Interval: 1}
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+	for _, part := range strings.Split(rule, ";") {
+		name, value, _ := strings.Cut(part, "=")
+		switch name {
+		case "FREQ":
+			if !recurrenceFrequencies[value] {
+				return Recurrence{}, ErrInvalidRecurrence
+			}
+			r.Frequency = value
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > maxRecurrenceInterval {
+				return Recurrence{}, ErrInvalidRecurrence
+			}
+			r.Interval = interval
+		default:
+			return Recurrence{}, ErrInvalidRecurrence
+		}
+	}
+	if r.Frequency == "" {
+		return Recurrence{}, ErrInvalidRecurrence
+	}
+	return r, nil
+}
+
+// String writes the rule in its canonical form, leaving out an interval
+// of 1
+func (r Recurrence) String() string {
+	if r.Interval > 1 {
+		return "FREQ=" + r.Frequency + ";INTERVAL=" + strconv.Itoa(r.Interval)
+	}
+	return "FREQ=" + r.Frequency
+}
+
+// Next returns the first occurrence of a series starting at start that
+// falls after t. Monthly and yearly rules keep the day of month of start,
+// falling back to the last day of shorter months
+func (r Recurrence) Next(start, t time.Time) time.Time {
+	for n := r.Interval; ; n += r.Interval {
+		var next time.Time
+		switch r.Frequency {
+		case "DAILY":
+			next = start.AddDate(0, 0, n)
+		case "WEEKLY":
+			next = start.AddDate(0, 0, 7*n)
+		case "MONTHLY":
+			next = addMonths(start, n)
+		default:
+			next = addMonths(start, 12*n)
+		}
+		if next.After(t) {
+			return next
+		}
+	}
+}
+
+// addMonths adds months to t without overflowing into the month after
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
+
+// validateRecurrence checks a recurrence rule and returns it in canonical
+// form; an empty rule means the todo does not repeat
+func validateRecurrence(verr *ValidationError, rule string) string {
+	if strings.TrimSpace(rule) == "" {
+		return ""
+	}
+	r, err := ParseRecurrence(rule)
+	if err != nil {
+		verr.add("recurrence", "format", err)
+		return ""
+	}
+	return r.String()
+}
+
+// repeat creates the next occurrence of a recurring todo that has just
+// been completed. It is due on the first occurrence after both its due
+// date and now, and copies the todo's details and tags
+func (s *todoService) repeat(repo repository.TodoRepository, todo *models.Todo, wasCompleted bool) error {
+	if wasCompleted || !todo.Completed || todo.Recurrence == "" {
+		return nil
+	}
+	r, err := ParseRecurrence(todo.Recurrence)
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	start := now
+	if todo.DueDate != nil {
+		start = *todo.DueDate
+	}
+	due := r.Next(start, now)
+
+	tags := make([]string, len(todo.Tags))
+	for i, tag := range todo.Tags {
+		tags[i] = tag.Name
+	}
+	_, err = s.create(repo, models.CreateTodoRequest{
+		Title:           todo.Title,
+		Description:     todo.Description,
+		Priority:        todo.Priority,
+		DueDate:         &due,
+		CategoryID:      todo.CategoryID,
+		EstimateMinutes: todo.EstimateMinutes,
+		CustomFields:    todo.CustomFields,
+		Recurrence:      todo.Recurrence,
+		Tags:            tags,
+	}, nil)
+	return err
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var (
+	ErrSmartListNotFound     = errors.New("smart list not found")
+	ErrSmartListNameRequired = errors.New("smart list name is required")
+	ErrSmartListNameTooLong  = errors.New("smart list name must be at most 100 characters")
+	ErrSmartListBuiltIn      = errors.New("built-in smart lists cannot be changed or deleted")
+	ErrInvalidSortOrder      = errors.New("sort order must be asc or desc")
+)
+
+const maxSmartListNameLength = 100
+
+// builtInSmartLists are seeded on start, in the order they are listed
+var builtInSmartLists = []models.SmartList{
+	{Key: models.SmartListToday, Name: "Today", Filter: models.SmartListFilter{
+		Due: models.DueToday, Completed: boolPtr(false), SortBy: "due_date", SortOrder: "asc",
+	}},
+	{Key: models.SmartListUpcoming, Name: "Upcoming", Filter: models.SmartListFilter{
+		Due: models.DueUpcoming, Completed: boolPtr(false), SortBy: "due_date", SortOrder: "asc",
+	}},
+	{Key: models.SmartListOverdue, Name: "Overdue", Filter: models.SmartListFilter{
+		Due: models.DueOverdue, Completed: boolPtr(false), SortBy: "due_date", SortOrder: "asc",
+	}},
+	{Key: models.SmartListNoDate, Name: "No Date", Filter: models.SmartListFilter{
+		HasDueDate: boolPtr(false), Completed: boolPtr(false),
+	}},
+}
+
+type SmartListService interface {
+	Create(req models.SmartListRequest) (*models.SmartList, error)
+	GetAll() ([]models.SmartList, error)
+	GetByID(id uint) (*models.SmartList, error)
+	Update(id uint, req models.SmartListRequest) (*models.SmartList, error)
+	Delete(id uint) error
+	Todos(id uint, page, limit int, timeZone string) (*models.PaginatedResponse, error)
+	EnsureDefaults() error
+}
+
+type smartListService struct {
+	repo         repository.SmartListRepository
+	categoryRepo repository.CategoryRepository
+	statusRepo   repository.StatusRepository
+	todos        TodoService
+}
+
+func NewSmartListService(repo repository.SmartListRepository, categoryRepo repository.CategoryRepository, statusRepo repository.StatusRepository, todos TodoService) SmartListService {
+	return &smartListService{
+		repo:         repo,
+		categoryRepo: categoryRepo,
+		statusRepo:   statusRepo,
+		todos:        todos,
+	}
+}
+
+func (s *smartListService) Create(req models.SmartListRequest) (*models.SmartList, error) {
+	filter, err := s.validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.SmartList{Name: strings.TrimSpace(req.Name), Filter: filter}
+	if err := s.repo.Create(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *smartListService) GetAll() ([]models.SmartList, error) {
+	return s.repo.GetAll()
+}
+
+func (s *smartListService) GetByID(id uint) (*models.SmartList, error) {
+	list, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, ErrSmartListNotFound
+	}
+	return list, nil
+}
+
+// Update replaces the name and filter of a saved smart list
+func (s *smartListService) Update(id uint, req models.SmartListRequest) (*models.SmartList, error) {
+	list, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if list.Key != "" {
+		return nil, ErrSmartListBuiltIn
+	}
+	filter, err := s.validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	list.Name = strings.TrimSpace(req.Name)
+	list.Filter = filter
+	if err := s.repo.Update(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *smartListService) Delete(id uint) error {
+	list, err := s.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if list.Key != "" {
+		return ErrSmartListBuiltIn
+	}
+	return s.repo.Delete(id)
+}
+
+// Todos lists the todos matching a smart list, a page at a time. A time
+// zone given here replaces the one saved with the list
+func (s *smartListService) Todos(id uint, page, limit int, timeZone string) (*models.PaginatedResponse, error) {
+	if timeZone != "" {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			verr := &ValidationError{}
+			verr.add("time_zone", "timezone", ErrInvalidTimeZone)
+			return nil, verr
+		}
+	}
+	list, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	filter := models.TodoFilter(list.Filter)
+	filter.Page, filter.Limit = page, limit
+	if timeZone != "" {
+		filter.TimeZone = timeZone
+	}
+	return s.todos.GetAll(filter)
+}
+
+// EnsureDefaults seeds the built-in smart lists that are missing
+func (s *smartListService) EnsureDefaults() error {
+	keys, err := s.repo.Keys()
+	if err != nil {
+		return err
+	}
+	present := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		present[key] = true
+	}
+
+	for _, builtIn := range builtInSmartLists {
+		if present[builtIn.Key] {
+			continue
+		}
+		list := builtIn
+		if err := s.repo.Create(&list); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks a smart list and returns its filter without pagination
+func (s *smartListService) validate(req models.SmartListRequest) (models.SmartListFilter, error) {
+	verr := &ValidationError{}
+	filter := validateSmartList(verr, req.Name, req.Filter)
+	if filter.CategoryID != nil {
+		if _, err := s.categoryRepo.GetByID(*filter.CategoryID); err != nil {
+			verr.add("filter.category_id", "exists", ErrCategoryNotFound)
+		}
+	}
+	if filter.StatusID != nil {
+		if _, err := s.statusRepo.GetByID(*filter.StatusID); err != nil {
+			verr.add("filter.status_id", "exists", ErrStatusNotFound)
+		}
+	}
+	if err := verr.orNil(); err != nil {
+		return models.SmartListFilter{}, err
+	}
+	return models.SmartListFilter(filter), nil
+}
+
+// validateSmartList checks the name and filter of a smart list, leaving out
+// whether its category and status exist, and returns the filter cleaned up
+// and without pagination
+func validateSmartList(verr *ValidationError, name string, filter models.TodoFilter) models.TodoFilter {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		verr.add("name", "required", ErrSmartListNameRequired)
+	} else if utf8.RuneCountInString(name) > maxSmartListNameLength {
+		verr.add("name", "max", ErrSmartListNameTooLong)
+	}
+
+	filter.Page, filter.Limit = 0, 0
+	filter.Search = strings.TrimSpace(filter.Search)
+	if filter.Priority != "" && !isValidPriority(filter.Priority) {
+		verr.add("filter.priority", "oneof", ErrInvalidPriority)
+	}
+	if filter.Due != "" && !IsValidDueWindow(filter.Due) {
+		verr.add("filter.due", "oneof", ErrInvalidDueWindow)
+	}
+	if filter.DueFrom != nil && filter.DueTo != nil && !filter.DueFrom.Before(*filter.DueTo) {
+		verr.add("filter.due_to", "gtfield", ErrInvalidDueRange)
+	}
+	if filter.TimeZone != "" {
+		if _, err := time.LoadLocation(filter.TimeZone); err != nil {
+			verr.add("filter.time_zone", "timezone", ErrInvalidTimeZone)
+		}
+	}
+	if _, err := ParseTodoQuery(filter.Query, time.Now()); err != nil {
+		verr.add("filter.q", "query", err)
+	}
+	for key := range filter.CustomFields {
+		if !IsValidCustomFieldKey(key) {
+			verr.add("filter.custom_fields."+key, "format", ErrCustomFieldKeyInvalid)
+		}
+	}
+	filter.SortOrder = strings.ToLower(filter.SortOrder)
+	if filter.SortOrder != "" && filter.SortOrder != "asc" && filter.SortOrder != "desc" {
+		verr.add("filter.sort_order", "oneof", ErrInvalidSortOrder)
+	}
+	return filter
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var (
+	ErrStatusNotFound     = errors.New("status not found")
+	ErrStatusNameRequired = errors.New("status name is required")
+	ErrStatusInUse        = errors.New("status still has todos; move them to another status first")
+	ErrInvalidWIPLimit    = errors.New("wip limit must be at least 1")
+	ErrWIPLimitReached    = errors.New("status has reached its WIP limit")
+)
+
+// defaultStatuses seed the board the first time the server starts
+var defaultStatuses = []models.Status{
+	{Name: "To Do", Position: 0},
+	{Name: "In Progress", Position: 1},
+	{Name: "Review", Position: 2},
+	{Name: "Done", Position: 3, IsDone: true},
+}
+
+type StatusService interface {
+	Create(req models.CreateStatusRequest) (*models.Status, error)
+	GetAll() ([]models.Status, error)
+	GetByID(id uint) (*models.Status, error)
+	Update(id uint, req models.UpdateStatusRequest) (*models.Status, error)
+	Delete(id uint) error
+	Board(filter models.TodoFilter) ([]models.BoardColumn, error)
+	EnsureDefaults() error
+}
+
+type statusService struct {
+	repo     repository.StatusRepository
+	todoRepo repository.TodoRepository
+	todos    TodoService
+}
+
+func NewStatusService(repo repository.StatusRepository, todoRepo repository.TodoRepository, todos TodoService) StatusService {
+	return &statusService{repo: repo, todoRepo: todoRepo, todos: todos}
+}
+
+func (s *statusService) Create(req models.CreateStatusRequest) (*models.Status, error) {
+	verr := &ValidationError{}
+	if req.Name == "" {
+		verr.add("name", "required", ErrStatusNameRequired)
+	}
+	if req.WIPLimit != nil && *req.WIPLimit < 1 {
+		verr.add("wip_limit", "min", ErrInvalidWIPLimit)
+	}
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
+
+	status := &models.Status{
+		Name:     req.Name,
+		IsDone:   req.IsDone,
+		WIPLimit: req.WIPLimit,
+	}
+
+	// New statuses go to the right of the board unless placed explicitly
+	if req.Position != nil {
+		status.Position = *req.Position
+	} else {
+		last, err := s.repo.MaxPosition()
+		if err != nil {
+			return nil, err
+		}
+		status.Position = last + 1
+	}
+
+	if err := s.repo.Create(status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func (s *statusService) GetAll() ([]models.Status, error) {
+	return s.repo.GetAll()
+}
+
+func (s *statusService) GetByID(id uint) (*models.Status, error) {
+	status, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, ErrStatusNotFound
+	}
+	return status, nil
+}
+
+func (s *statusService) Update(id uint, req models.UpdateStatusRequest) (*models.Status, error) {
+	status, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, ErrStatusNotFound
+	}
+
+	if req.Name != "" {
+		status.Name = req.Name
+	}
+	if req.Position != nil {
+		status.Position = *req.Position
+	}
+	if req.WIPLimit != nil {
+		status.WIPLimit = req.WIPLimit
+		if *req.WIPLimit == 0 {
+			status.WIPLimit = nil
+		}
+	}
+	doneChanged := req.IsDone != nil && *req.IsDone != status.IsDone
+	if req.IsDone != nil {
+		status.IsDone = *req.IsDone
+	}
+
+	// Completion is derived from the status, so flipping the done flag
+	// completes or reopens every todo in the column
+	if doneChanged {
+		err = s.todos.SetStatusDone(status)
+	} else {
+		err = s.repo.Update(status)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func (s *statusService) Delete(id uint) error {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return ErrStatusNotFound
+	}
+
+	count, err := s.repo.CountTodos(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrStatusInUse
+	}
+
+	return s.repo.Delete(id)
+}
+
+// Board returns every status as a column holding the todos that match
+// filter, each column in manual order
+func (s *statusService) Board(filter models.TodoFilter) ([]models.BoardColumn, error) {
+	statuses, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err = resolveFilter(filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	filter.SortBy = "position"
+	filter.SortOrder = "ASC"
+	filter.Page, filter.Limit = 0, 0
+
+	columns := make([]models.BoardColumn, 0, len(statuses))
+	for _, status := range statuses {
+		id := status.ID
+		filter.StatusID = &id
+		todos, _, err := s.todoRepo.GetAll(filter)
+		if err != nil {
+			return nil, err
+		}
+		if todos == nil {
+			todos = []models.Todo{}
+		}
+		columns = append(columns, models.BoardColumn{Status: status, Todos: todos, Count: len(todos)})
+	}
+
+	return columns, nil
+}
+
+// EnsureDefaults seeds the default statuses on an empty board and places
+// todos without a status in the first open or done column
+func (s *statusService) EnsureDefaults() error {
+	count, err := s.repo.Count()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		for _, status := range defaultStatuses {
+			status := status
+			if err := s.repo.Create(&status); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, done := range []bool{false, true} {
+		status, err := s.repo.FirstByDone(done)
+		if err != nil {
+			continue
+		}
+		if err := s.repo.AssignMissing(done, status.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
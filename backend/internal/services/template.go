@@ -0,0 +1,287 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var (
+	ErrTemplateNotFound        = errors.New("template not found")
+	ErrTemplateNameRequired    = errors.New("template name is required")
+	ErrTemplateNameTooLong     = errors.New("template name must be at most 100 characters")
+	ErrTemplateItemsRequired   = errors.New("a template needs at least one item")
+	ErrTemplateTooManyItems    = errors.New("a template can hold at most 100 items")
+	ErrDueOffsetOutOfRange     = errors.New("due offset must be between -3650 and 3650 days")
+	ErrInvalidBaseDate         = errors.New("base date must be a date such as 2026-01-31")
+	ErrTemplateVariableMissing = errors.New("no value given for this template variable")
+)
+
+const (
+	maxTemplateNameLength = 100
+	maxTemplateItems      = 100
+	maxDueOffsetDays      = 3650
+	baseDateLayout        = "2006-01-02"
+)
+
+// templateVariablePattern matches {{name}} placeholders, allowing spaces
+// inside the braces
+var templateVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+type TemplateService interface {
+	Create(req models.CreateTemplateRequest) (*models.Template, error)
+	GetAll() ([]models.Template, error)
+	GetByID(id uint) (*models.Template, error)
+	Update(id uint, req models.UpdateTemplateRequest) (*models.Template, error)
+	Delete(id uint) error
+	Instantiate(id uint, req models.InstantiateTemplateRequest) ([]models.Todo, error)
+	SaveTodo(todoID uint, req models.SaveAsTemplateRequest) (*models.Template, error)
+}
+
+type templateService struct {
+	repo          repository.TemplateRepository
+	todoRepo      repository.TodoRepository
+	categoryRepo  repository.CategoryRepository
+	checklistRepo repository.ChecklistRepository
+	todos         TodoService
+	now           func() time.Time
+}
+
+func NewTemplateService(repo repository.TemplateRepository, todoRepo repository.TodoRepository, categoryRepo repository.CategoryRepository, checklistRepo repository.ChecklistRepository, todos TodoService) TemplateService {
+	return &templateService{
+		repo:          repo,
+		todoRepo:      todoRepo,
+		categoryRepo:  categoryRepo,
+		checklistRepo: checklistRepo,
+		todos:         todos,
+		now:           time.Now,
+	}
+}
+
+func (s *templateService) Create(req models.CreateTemplateRequest) (*models.Template, error) {
+	items, err := s.validate(req.Name, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.Template{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Items:       items,
+	}
+	if err := s.repo.Create(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *templateService) GetAll() ([]models.Template, error) {
+	return s.repo.GetAll()
+}
+
+func (s *templateService) GetByID(id uint) (*models.Template, error) {
+	template, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, ErrTemplateNotFound
+	}
+	return template, nil
+}
+
+// Update replaces a template's name, description and items
+func (s *templateService) Update(id uint, req models.UpdateTemplateRequest) (*models.Template, error) {
+	template, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.validate(req.Name, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = strings.TrimSpace(req.Name)
+	template.Description = req.Description
+	template.Items = items
+	if err := s.repo.Update(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *templateService) Delete(id uint) error {
+	if _, err := s.GetByID(id); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+// Instantiate creates every todo of a template in one transaction. The
+// variables fill in {{name}} placeholders, with {{date}} defaulting to the
+// base date, and due dates are offset from the base date
+func (s *templateService) Instantiate(id uint, req models.InstantiateTemplateRequest) ([]models.Todo, error) {
+	template, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	verr := &ValidationError{}
+	base := s.now().UTC().Truncate(24 * time.Hour)
+	if req.BaseDate != "" {
+		base, err = time.Parse(baseDateLayout, req.BaseDate)
+		if err != nil {
+			verr.add("base_date", "format", ErrInvalidBaseDate)
+		}
+	}
+
+	variables := map[string]string{"date": base.Format(baseDateLayout)}
+	for name, value := range req.Variables {
+		variables[name] = value
+	}
+	missing := map[string]bool{}
+	substitute := func(text string) string {
+		return templateVariablePattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+			name := templateVariablePattern.FindStringSubmatch(placeholder)[1]
+			value, ok := variables[name]
+			if !ok {
+				missing[name] = true
+				return placeholder
+			}
+			return value
+		})
+	}
+
+	reqs := make([]models.CreateTodoRequest, len(template.Items))
+	for i, item := range template.Items {
+		subtasks := make([]string, len(item.Subtasks))
+		for j, subtask := range item.Subtasks {
+			subtasks[j] = substitute(subtask)
+		}
+		reqs[i] = models.CreateTodoRequest{
+			Title:       strings.TrimSpace(substitute(item.Title)),
+			Description: substitute(item.Description),
+			Priority:    item.Priority,
+			CategoryID:  item.CategoryID,
+			Tags:        item.Tags,
+			Checklist:   subtasks,
+		}
+		if item.DueOffsetDays != nil {
+			due := base.AddDate(0, 0, *item.DueOffsetDays)
+			reqs[i].DueDate = &due
+		}
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		verr.add("variables."+name, "required", ErrTemplateVariableMissing)
+	}
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
+
+	return s.todos.CreateMany(reqs)
+}
+
+// SaveTodo captures an existing todo, its tags and checklist as a
+// single-item template. The due date becomes an offset from the day the
+// todo was created
+func (s *templateService) SaveTodo(todoID uint, req models.SaveAsTemplateRequest) (*models.Template, error) {
+	todo, err := s.todoRepo.GetByID(todoID)
+	if err != nil {
+		return nil, ErrTodoNotFound
+	}
+	checklist, err := s.checklistRepo.GetByTodo(todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := models.TemplateItem{
+		Title:       todo.Title,
+		Description: todo.Description,
+		Priority:    todo.Priority,
+		CategoryID:  todo.CategoryID,
+	}
+	if todo.DueDate != nil {
+		days := int(todo.DueDate.UTC().Truncate(24*time.Hour).Sub(todo.CreatedAt.UTC().Truncate(24*time.Hour)).Hours() / 24)
+		item.DueOffsetDays = &days
+	}
+	for _, tag := range todo.Tags {
+		item.Tags = append(item.Tags, tag.Name)
+	}
+	for _, checklistItem := range checklist {
+		item.Subtasks = append(item.Subtasks, checklistItem.Title)
+	}
+
+	return s.Create(models.CreateTemplateRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Items:       models.TemplateItems{item},
+	})
+}
+
+// validate checks a template's name and items, returning the items with
+// titles, tags and subtasks trimmed
+func (s *templateService) validate(name string, items models.TemplateItems) (models.TemplateItems, error) {
+	verr := &ValidationError{}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		verr.add("name", "required", ErrTemplateNameRequired)
+	} else if utf8.RuneCountInString(name) > maxTemplateNameLength {
+		verr.add("name", "max", ErrTemplateNameTooLong)
+	}
+
+	switch {
+	case len(items) == 0:
+		verr.add("items", "required", ErrTemplateItemsRequired)
+	case len(items) > maxTemplateItems:
+		verr.add("items", "max", ErrTemplateTooManyItems)
+		return nil, verr
+	}
+
+	cleaned := make(models.TemplateItems, len(items))
+	for i, item := range items {
+		cleaned[i] = s.validateItem(verr, fmt.Sprintf("items[%d].", i), item)
+	}
+
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
+	return cleaned, nil
+}
+
+func (s *templateService) validateItem(verr *ValidationError, prefix string, item models.TemplateItem) models.TemplateItem {
+	itemErr := &ValidationError{}
+
+	item.Title = strings.TrimSpace(item.Title)
+	if item.Title == "" {
+		itemErr.add("title", "required", ErrTodoTitleRequired)
+	} else if utf8.RuneCountInString(item.Title) > maxTodoTitleLength {
+		itemErr.add("title", "max", ErrTodoTitleTooLong)
+	}
+	if item.Priority != "" && !isValidPriority(item.Priority) {
+		itemErr.add("priority", "oneof", ErrInvalidPriority)
+	}
+	if item.CategoryID != nil {
+		if _, err := s.categoryRepo.GetByID(*item.CategoryID); err != nil {
+			itemErr.add("category_id", "exists", ErrCategoryNotFound)
+		}
+	}
+	if item.DueOffsetDays != nil && (*item.DueOffsetDays < -maxDueOffsetDays || *item.DueOffsetDays > maxDueOffsetDays) {
+		itemErr.add("due_offset_days", "range", ErrDueOffsetOutOfRange)
+	}
+	item.Tags = validateTagNames(itemErr, item.Tags)
+	item.Subtasks = validateChecklistTitles(itemErr, "subtasks", item.Subtasks)
+
+	verr.addAll(prefix, itemErr)
+	return item
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+var (
+	ErrTimerRunning        = errors.New("a timer is already running; stop it first")
+	ErrTimerNotRunning     = errors.New("no timer is running on this todo")
+	ErrTimeEntryNotFound   = errors.New("time entry not found")
+	ErrTimeEntryForbidden  = errors.New("only the user who tracked the time can delete it")
+	ErrTimeEntryOrder      = errors.New("ended_at must be after started_at")
+	ErrTimeEntryTooLong    = errors.New("a time entry can span at most 24 hours")
+	ErrTimeEntryInFuture   = errors.New("time entries cannot end in the future")
+	ErrTimeEntryNoteLength = errors.New("note must be at most 255 characters")
+	ErrInvalidReportGroup  = errors.New("group_by must be one of todo, category or day")
+	ErrInvalidReportRange  = errors.New("to must be after from")
+)
+
+const (
+	maxTimeEntryDuration   = 24 * time.Hour
+	maxTimeEntryNoteLength = 255
+)
+
+type TimeTrackingService interface {
+	Start(todoID uint, user string, req models.StartTimerRequest) (*models.TimeEntry, error)
+	Stop(todoID uint, user string) (*models.TimeEntry, error)
+	Running(user string) (*models.TimeEntry, error)
+	AddEntry(todoID uint, user string, req models.CreateTimeEntryRequest) (*models.TimeEntry, error)
+	ListEntries(todoID uint) ([]models.TimeEntry, error)
+	DeleteEntry(todoID, id uint, user string) error
+	Report(filter models.TimeReportFilter) (*models.TimeReport, error)
+}
+
+type timeTrackingService struct {
+	repo     repository.TimeEntryRepository
+	todoRepo repository.TodoRepository
+	now      func() time.Time
+}
+
+func NewTimeTrackingService(repo repository.TimeEntryRepository, todoRepo repository.TodoRepository) TimeTrackingService {
+	return &timeTrackingService{repo: repo, todoRepo: todoRepo, now: time.Now}
+}
+
+// Start begins a timer on a todo. A user can only run one timer at a time
+func (s *timeTrackingService) Start(todoID uint, user string, req models.StartTimerRequest) (*models.TimeEntry, error) {
+	if _, err := s.todoRepo.GetByID(todoID); err != nil {
+		return nil, ErrTodoNotFound
+	}
+	if utf8.RuneCountInString(req.Note) > maxTimeEntryNoteLength {
+		verr := &ValidationError{}
+		verr.add("note", "max", ErrTimeEntryNoteLength)
+		return nil, verr
+	}
+
+	entry := &models.TimeEntry{
+		TodoID:    todoID,
+		UserName:  user,
+		StartedAt: s.now().UTC(),
+		Note:      req.Note,
+	}
+	if err := s.repo.StartTimer(entry); err != nil {
+		if errors.Is(err, repository.ErrTimerRunning) {
+			return nil, ErrTimerRunning
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Stop ends the user's timer on a todo
+func (s *timeTrackingService) Stop(todoID uint, user string) (*models.TimeEntry, error) {
+	entry, err := s.repo.Running(user)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil || entry.TodoID != todoID {
+		return nil, ErrTimerNotRunning
+	}
+
+	ended := s.now().UTC()
+	if ended.Before(entry.StartedAt) {
+		ended = entry.StartedAt
+	}
+	entry.EndedAt = &ended
+	entry.DurationSeconds = int64(ended.Sub(entry.StartedAt) / time.Second)
+
+	if err := s.repo.StopTimer(entry); err != nil {
+		if errors.Is(err, repository.ErrTimerNotRunning) {
+			return nil, ErrTimerNotRunning
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Running returns the user's running timer, or nil when there is none
+func (s *timeTrackingService) Running(user string) (*models.TimeEntry, error) {
+	return s.repo.Running(user)
+}
+
+// AddEntry records time that was tracked outside the app
+func (s *timeTrackingService) AddEntry(todoID uint, user string, req models.CreateTimeEntryRequest) (*models.TimeEntry, error) {
+	if _, err := s.todoRepo.GetByID(todoID); err != nil {
+		return nil, ErrTodoNotFound
+	}
+
+	verr := &ValidationError{}
+	duration := req.EndedAt.Sub(req.StartedAt)
+	switch {
+	case duration <= 0:
+		verr.add("ended_at", "gtfield", ErrTimeEntryOrder)
+	case duration > maxTimeEntryDuration:
+		verr.add("ended_at", "max", ErrTimeEntryTooLong)
+	case req.EndedAt.After(s.now()):
+		verr.add("ended_at", "past", ErrTimeEntryInFuture)
+	}
+	if utf8.RuneCountInString(req.Note) > maxTimeEntryNoteLength {
+		verr.add("note", "max", ErrTimeEntryNoteLength)
+	}
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
+
+	ended := req.EndedAt.UTC()
+	entry := &models.TimeEntry{
+		TodoID:          todoID,
+		UserName:        user,
+		StartedAt:       req.StartedAt.UTC(),
+		EndedAt:         &ended,
+		DurationSeconds: int64(duration / time.Second),
+		Note:            req.Note,
+	}
+	if err := s.repo.Create(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *timeTrackingService) ListEntries(todoID uint) ([]models.TimeEntry, error) {
+	if _, err := s.todoRepo.GetByID(todoID); err != nil {
+		return nil, ErrTodoNotFound
+	}
+	return s.repo.GetByTodo(todoID)
+}
+
+func (s *timeTrackingService) DeleteEntry(todoID, id uint, user string) error {
+	entry, err := s.repo.GetByID(id)
+	if err != nil || entry.TodoID != todoID {
+		return ErrTimeEntryNotFound
+	}
+	if entry.UserName != user {
+		return ErrTimeEntryForbidden
+	}
+	return s.repo.Delete(id)
+}
+
+// Report totals the finished time entries per todo, category or day. Days
+// are those of the filter's time zone, UTC by default
+func (s *timeTrackingService) Report(filter models.TimeReportFilter) (*models.TimeReport, error) {
+	verr := &ValidationError{}
+	if filter.GroupBy == "" {
+		filter.GroupBy = models.TimeReportByTodo
+	}
+	switch filter.GroupBy {
+	case models.TimeReportByTodo, models.TimeReportByCategory, models.TimeReportByDay:
+	default:
+		verr.add("group_by", "oneof", ErrInvalidReportGroup)
+	}
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		verr.add("to", "gtfield", ErrInvalidReportRange)
+	}
+	if filter.TimeZone != "" {
+		if _, err := time.LoadLocation(filter.TimeZone); err != nil {
+			verr.add("time_zone", "timezone", ErrInvalidTimeZone)
+		}
+	}
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.Report(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.TimeReport{TimeReportFilter: filter, Rows: rows}
+	if report.Rows == nil {
+		report.Rows = []models.TimeReportRow{}
+	}
+	for _, row := range rows {
+		report.TotalSeconds += row.TotalSeconds
+	}
+	return report, nil
+}
@@ -2,62 +2,175 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"time"
+	"unicode/utf8"
 
 	"github.com/industrix-todo-app/backend/internal/models"
 	"github.com/industrix-todo-app/backend/internal/repository"
 )
 
 var (
-	ErrTodoNotFound     = errors.New("todo not found")
+	ErrTodoNotFound      = errors.New("todo not found")
 	ErrTodoTitleRequired = errors.New("todo title is required")
-	ErrInvalidPriority  = errors.New("invalid priority value")
+	ErrTodoTitleTooLong  = errors.New("todo title must be at most 255 characters")
+	ErrInvalidPriority   = errors.New("priority must be one of high, medium or low")
+	ErrDueDateOutOfRange = errors.New("due date must be between 2000-01-01 and 2099-12-31")
+	ErrInvalidEstimate   = errors.New("estimate must be between 0 and 525600 minutes")
+	ErrTooManyTags       = errors.New("a todo can have at most 20 tags")
 )
 
 type TodoService interface {
 	Create(req models.CreateTodoRequest) (*models.Todo, error)
+	QuickAdd(req models.QuickAddRequest) (*models.QuickAddResponse, error)
+	CreateMany(reqs []models.CreateTodoRequest) ([]models.Todo, error)
 	GetAll(filter models.TodoFilter) (*models.PaginatedResponse, error)
+	ListState(filter models.TodoFilter) (models.ListState, error)
+	Export(filter models.TodoFilter, fn func(todo *models.Todo) error) error
+	Import(req models.TodoImportRequest) (*models.TodoImportReport, error)
+	ImportTodoTxt(req models.TodoTxtImportRequest) (*models.TodoImportReport, error)
+	ImportCalendar(req models.CalendarImportRequest) (*models.TodoImportReport, error)
+	ImportExternal(req models.ExternalImportRequest) (*models.TodoImportReport, error)
 	GetByID(id uint) (*models.Todo, error)
-	Update(id uint, req models.UpdateTodoRequest) (*models.Todo, error)
-	Delete(id uint) error
+	Update(id uint, req models.UpdateTodoRequest, expectedVersion *uint) (*models.Todo, error)
+	Patch(id uint, req models.PatchTodoRequest, expectedVersion *uint) (*models.Todo, error)
+	Delete(id uint, expectedVersion *uint) error
 	ToggleComplete(id uint) (*models.Todo, error)
+	Bulk(req models.BulkTodoRequest) (*models.BulkTodoResponse, error)
+	Move(id uint, req models.MoveTodoRequest) (*models.Todo, error)
+	BackfillPositions() error
+	Blockers(id uint) ([]models.Todo, error)
+	Blocking(id uint) ([]models.Todo, error)
+	AddBlocker(id, blockerID uint) ([]models.Todo, error)
+	RemoveBlocker(id, blockerID uint) error
+	SetStatusDone(status *models.Status) error
 }
 
 type todoService struct {
-	repo repository.TodoRepository
+	repo         repository.TodoRepository
+	categoryRepo repository.CategoryRepository
+	statusRepo   repository.StatusRepository
+
+	// attachments removes the blobs of deleted todos; nil leaves them alone
+	attachments AttachmentService
+
+	// enforceBlockers refuses to complete todos with incomplete blockers
+	enforceBlockers bool
 }
 
-func NewTodoService(repo repository.TodoRepository) TodoService {
-	return &todoService{repo: repo}
+func NewTodoService(repo repository.TodoRepository, categoryRepo repository.CategoryRepository, statusRepo repository.StatusRepository, attachments AttachmentService, enforceBlockers bool) TodoService {
+	return &todoService{
+		repo:            repo,
+		categoryRepo:    categoryRepo,
+		statusRepo:      statusRepo,
+		attachments:     attachments,
+		enforceBlockers: enforceBlockers,
+	}
 }
 
+// Create adds a todo together with its tags and checklist in one transaction
 func (s *todoService) Create(req models.CreateTodoRequest) (*models.Todo, error) {
-	if req.Title == "" {
-		return nil, ErrTodoTitleRequired
+	var todo *models.Todo
+	err := s.repo.Transaction(func(tx repository.TodoRepository) error {
+		var err error
+		todo, err = s.create(tx, req, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return todo, nil
+}
 
-	if req.Priority != "" && !isValidPriority(req.Priority) {
-		return nil, ErrInvalidPriority
+// CreateMany adds several todos in one transaction, so either all of them
+// are created or none. Validation errors name the offending todo by index
+func (s *todoService) CreateMany(reqs []models.CreateTodoRequest) ([]models.Todo, error) {
+	todos := make([]models.Todo, 0, len(reqs))
+	err := s.repo.Transaction(func(tx repository.TodoRepository) error {
+		for i, req := range reqs {
+			todo, err := s.create(tx, req, nil)
+			if err != nil {
+				return withFieldPrefix(err, fmt.Sprintf("todos[%d].", i))
+			}
+			todos = append(todos, *todo)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// create adds a todo with its tags and checklist. checked marks checklist
+// items as done by index; imports use it to keep their state
+func (s *todoService) create(repo repository.TodoRepository, req models.CreateTodoRequest, checked []bool) (*models.Todo, error) {
+	verr := &ValidationError{}
+	if req.Title == "" {
+		verr.add("title", "required", ErrTodoTitleRequired)
+	}
+	category := s.validateFields(verr, req.Title, req.Priority, req.DueDate, req.CategoryID)
+	validateEstimate(verr, req.EstimateMinutes)
+	customFields := mergeCustomFields(verr, category, nil, req.CustomFields, true)
+	tags := validateTagNames(verr, req.Tags)
+	checklist := validateChecklistTitles(verr, "checklist", req.Checklist)
+	recurrence := validateRecurrence(verr, req.Recurrence)
+	if err := verr.orNil(); err != nil {
+		return nil, err
 	}
 
 	todo := &models.Todo{
-		Title:       req.Title,
-		Description: req.Description,
-		Priority:    req.Priority,
-		DueDate:     req.DueDate,
-		CategoryID:  req.CategoryID,
+		Title:           req.Title,
+		Description:     req.Description,
+		Completed:       req.Completed,
+		Priority:        req.Priority,
+		DueDate:         req.DueDate,
+		CategoryID:      req.CategoryID,
+		StatusID:        req.StatusID,
+		EstimateMinutes: req.EstimateMinutes,
+		CustomFields:    customFields,
+		Recurrence:      recurrence,
+		Version:         1,
 	}
 
 	if todo.Priority == "" {
 		todo.Priority = models.PriorityMedium
 	}
 
-	if err := s.repo.Create(todo); err != nil {
+	if err := s.syncStatus(repo, todo, nil, req.StatusID != nil); err != nil {
 		return nil, err
 	}
 
-	// Reload to get category data
-	return s.repo.GetByID(todo.ID)
+	// New todos go to the end of the manual order
+	position, err := appendPosition(repo)
+	if err != nil {
+		return nil, err
+	}
+	todo.Position = position
+
+	if err := repo.Create(todo); err != nil {
+		return nil, err
+	}
+
+	for _, name := range tags {
+		if err := repo.AddTag(todo.ID, name); err != nil {
+			return nil, err
+		}
+	}
+	if len(checklist) > 0 {
+		positions := RanksBetween("", "", len(checklist))
+		items := make([]models.ChecklistItem, len(checklist))
+		for i, title := range checklist {
+			items[i] = models.ChecklistItem{TodoID: todo.ID, Title: title, Checked: i < len(checked) && checked[i], Position: positions[i]}
+		}
+		if err := repo.AddChecklistItems(items); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reload to get category and tag data
+	return repo.GetByID(todo.ID)
 }
 
 func (s *todoService) GetAll(filter models.TodoFilter) (*models.PaginatedResponse, error) {
@@ -72,7 +185,11 @@ func (s *todoService) GetAll(filter models.TodoFilter) (*models.PaginatedRespons
 		filter.Limit = 100
 	}
 
-	todos, total, err := s.repo.GetAll(filter)
+	resolved, err := resolveFilter(filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	todos, total, err := s.repo.GetAll(resolved)
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +207,27 @@ func (s *todoService) GetAll(filter models.TodoFilter) (*models.PaginatedRespons
 	}, nil
 }
 
+func (s *todoService) ListState(filter models.TodoFilter) (models.ListState, error) {
+	resolved, err := resolveFilter(filter, time.Now())
+	if err != nil {
+		return models.ListState{}, err
+	}
+	return s.repo.ListState(resolved)
+}
+
+// Export calls fn for every todo matching filter. Unlike GetAll it is not
+// paginated unless the filter asks for a page
+func (s *todoService) Export(filter models.TodoFilter, fn func(todo *models.Todo) error) error {
+	if filter.Limit > 0 && filter.Page <= 0 {
+		filter.Page = 1
+	}
+	resolved, err := resolveFilter(filter, time.Now())
+	if err != nil {
+		return err
+	}
+	return s.repo.Each(resolved, fn)
+}
+
 func (s *todoService) GetByID(id uint) (*models.Todo, error) {
 	todo, err := s.repo.GetByID(id)
 	if err != nil {
@@ -98,49 +236,180 @@ func (s *todoService) GetByID(id uint) (*models.Todo, error) {
 	return todo, nil
 }
 
-func (s *todoService) Update(id uint, req models.UpdateTodoRequest) (*models.Todo, error) {
+// Update replaces a todo with req. Fields left out are cleared or reset to
+// their defaults: no description, due date, category, estimate, custom
+// fields or recurrence, medium priority and not completed. Without a
+// status_id the todo moves to the first status matching its completion.
+// Tags, checklist items and the manual position are managed through their
+// own endpoints and are kept
+func (s *todoService) Update(id uint, req models.UpdateTodoRequest, expectedVersion *uint) (*models.Todo, error) {
+	todo, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, ErrTodoNotFound
+	}
+	if err := checkVersion(todo.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	verr := &ValidationError{}
+	if req.Title == "" {
+		verr.add("title", "required", ErrTodoTitleRequired)
+	}
+	category := s.validateFields(verr, req.Title, req.Priority, req.DueDate, req.CategoryID)
+	validateEstimate(verr, req.EstimateMinutes)
+	customFields := mergeCustomFields(verr, category, nil, req.CustomFields, true)
+	recurrence := validateRecurrence(verr, req.Recurrence)
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
+
+	previousStatusID, wasCompleted := todo.StatusID, todo.Completed
+	todo.Title = req.Title
+	todo.Description = req.Description
+	todo.Completed = req.Completed != nil && *req.Completed
+	todo.Priority = req.Priority
+	if todo.Priority == "" {
+		todo.Priority = models.PriorityMedium
+	}
+	todo.DueDate = req.DueDate
+	todo.CategoryID, todo.Category = req.CategoryID, nil
+	todo.StatusID, todo.Status = req.StatusID, nil
+	todo.EstimateMinutes = req.EstimateMinutes
+	todo.CustomFields = customFields
+	todo.Recurrence = recurrence
+	if err := s.save(todo, previousStatusID, req.StatusID != nil, wasCompleted); err != nil {
+		return nil, err
+	}
+
+	// Reload to get updated category data
+	return s.repo.GetByID(todo.ID)
+}
+
+func (s *todoService) Patch(id uint, req models.PatchTodoRequest, expectedVersion *uint) (*models.Todo, error) {
 	todo, err := s.repo.GetByID(id)
 	if err != nil {
 		return nil, ErrTodoNotFound
 	}
+	if err := checkVersion(todo.Version, expectedVersion); err != nil {
+		return nil, err
+	}
 
-	if req.Title != "" {
-		todo.Title = req.Title
+	verr := &ValidationError{}
+	if req.Title.Set && (req.Title.Null || req.Title.Value == "") {
+		verr.add("title", "required", ErrTodoTitleRequired)
 	}
-	if req.Description != "" {
-		todo.Description = req.Description
+	var dueDate *time.Time
+	if req.DueDate.Present() {
+		dueDate = &req.DueDate.Value
 	}
-	if req.Completed != nil {
-		todo.Completed = *req.Completed
+	var categoryID *uint
+	if req.CategoryID.Present() {
+		categoryID = &req.CategoryID.Value
 	}
-	if req.Priority != "" {
-		if !isValidPriority(req.Priority) {
-			return nil, ErrInvalidPriority
+	var estimate *int
+	if req.EstimateMinutes.Present() {
+		estimate = &req.EstimateMinutes.Value
+	}
+	category := s.validateFields(verr, req.Title.Value, req.Priority.Value, dueDate, categoryID)
+	validateEstimate(verr, estimate)
+	var customFields models.CustomFieldValues
+	if req.CustomFields.Set || req.CategoryID.Set {
+		if !req.CategoryID.Set {
+			category = s.todoCategory(todo)
+		}
+		current := todo.CustomFields
+		if req.CustomFields.Null {
+			current = nil
 		}
-		todo.Priority = req.Priority
+		customFields = mergeCustomFields(verr, category, current, req.CustomFields.Value, true)
 	}
-	if req.DueDate != nil {
-		todo.DueDate = req.DueDate
+	var recurrence string
+	if req.Recurrence.Present() {
+		recurrence = validateRecurrence(verr, req.Recurrence.Value)
 	}
-	if req.CategoryID != nil {
-		todo.CategoryID = req.CategoryID
+	if err := verr.orNil(); err != nil {
+		return nil, err
 	}
 
-	if err := s.repo.Update(todo); err != nil {
+	previousStatusID, wasCompleted := todo.StatusID, todo.Completed
+	if req.Title.Set {
+		todo.Title = req.Title.Value
+	}
+	if req.Description.Set {
+		todo.Description = req.Description.Value
+	}
+	if req.Completed.Set {
+		todo.Completed = req.Completed.Value
+	}
+	if req.Priority.Set {
+		todo.Priority = req.Priority.Value
+		if req.Priority.Null {
+			todo.Priority = models.PriorityMedium
+		}
+	}
+	if req.DueDate.Set {
+		todo.DueDate = dueDate
+	}
+	if req.CategoryID.Set {
+		todo.CategoryID = categoryID
+		todo.Category = nil
+	}
+	if req.EstimateMinutes.Set {
+		todo.EstimateMinutes = estimate
+	}
+	if customFields != nil {
+		todo.CustomFields = customFields
+	}
+	if req.Recurrence.Set {
+		todo.Recurrence = recurrence
+	}
+	if req.StatusID.Set {
+		todo.StatusID = nil
+		if req.StatusID.Present() {
+			todo.StatusID = &req.StatusID.Value
+		}
+	}
+	if err := s.save(todo, previousStatusID, req.StatusID.Present(), wasCompleted); err != nil {
 		return nil, err
 	}
 
-	// Reload to get updated category data
 	return s.repo.GetByID(todo.ID)
 }
 
-func (s *todoService) Delete(id uint) error {
-	_, err := s.repo.GetByID(id)
+func (s *todoService) Delete(id uint, expectedVersion *uint) error {
+	todo, err := s.repo.GetByID(id)
 	if err != nil {
 		return ErrTodoNotFound
 	}
+	if err := checkVersion(todo.Version, expectedVersion); err != nil {
+		return err
+	}
 
-	return s.repo.Delete(id)
+	keys, err := s.blobKeys(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id, todo.Version); err != nil {
+		return versionError(err)
+	}
+	s.removeBlobs(keys)
+	return nil
+}
+
+// blobKeys lists the attachment blobs of a todo about to be deleted
+func (s *todoService) blobKeys(id uint) ([]string, error) {
+	if s.attachments == nil {
+		return nil, nil
+	}
+	return s.attachments.BlobKeys(id)
+}
+
+// removeBlobs deletes attachment blobs once their todo rows are committed
+// as deleted
+func (s *todoService) removeBlobs(keys []string) {
+	if s.attachments != nil && len(keys) > 0 {
+		s.attachments.RemoveBlobs(keys)
+	}
 }
 
 func (s *todoService) ToggleComplete(id uint) (*models.Todo, error) {
@@ -150,14 +419,176 @@ func (s *todoService) ToggleComplete(id uint) (*models.Todo, error) {
 	}
 
 	todo.Completed = !todo.Completed
-
-	if err := s.repo.Update(todo); err != nil {
+	if err := s.save(todo, todo.StatusID, false, !todo.Completed); err != nil {
 		return nil, err
 	}
 
 	return todo, nil
 }
 
-func isValidPriority(p models.Priority) bool {
-	return p == models.PriorityHigh || p == models.PriorityMedium || p == models.PriorityLow
+// save writes an edited todo in one transaction: it settles the status,
+// checks blockers, stores the todo and creates the next occurrence of a
+// completed recurring todo
+func (s *todoService) save(todo *models.Todo, previousStatusID *uint, statusRequested, wasCompleted bool) error {
+	return s.repo.Transaction(func(repo repository.TodoRepository) error {
+		if err := s.syncStatus(repo, todo, previousStatusID, statusRequested); err != nil {
+			return err
+		}
+		if err := s.checkBlockers(repo, todo, wasCompleted); err != nil {
+			return err
+		}
+		if err := repo.Update(todo); err != nil {
+			return versionError(err)
+		}
+		return s.repeat(repo, todo, wasCompleted)
+	})
+}
+
+// syncStatus keeps a todo's status and completion consistent. An explicitly
+// requested status decides completion; otherwise a completion change moves
+// the todo to the first status on the matching side of the board. Entering a
+// status that is at its WIP limit fails. The limit is counted on repo with
+// the status locked, so repo must be a transaction that goes on to save
+// the todo
+func (s *todoService) syncStatus(repo repository.TodoRepository, todo *models.Todo, previousStatusID *uint, statusRequested bool) error {
+	var status *models.Status
+	if statusRequested && todo.StatusID != nil {
+		found, err := s.statusRepo.GetByID(*todo.StatusID)
+		if err != nil {
+			verr := &ValidationError{}
+			verr.add("status_id", "exists", ErrStatusNotFound)
+			return verr
+		}
+		status = found
+		todo.Completed = status.IsDone
+	} else if !statusRequested {
+		status = todo.Status
+		if todo.StatusID != nil && (status == nil || status.ID != *todo.StatusID) {
+			status, _ = s.statusRepo.GetByID(*todo.StatusID)
+		}
+		if status == nil || status.IsDone != todo.Completed {
+			// Without a status on the matching side the todo stays put
+			if found, err := s.statusRepo.FirstByDone(todo.Completed); err == nil {
+				status = found
+				todo.StatusID = &found.ID
+			}
+		}
+	}
+	todo.Status = status
+
+	if status == nil || status.WIPLimit == nil || sameID(previousStatusID, todo.StatusID) {
+		return nil
+	}
+	count, err := repo.CountInStatusForUpdate(status.ID)
+	if err != nil {
+		return err
+	}
+	if count >= int64(*status.WIPLimit) {
+		return ErrWIPLimitReached
+	}
+	return nil
+}
+
+// SetStatusDone saves a status whose done flag flipped and completes or
+// reopens every todo in it to match, in one transaction. Completing checks
+// blockers like completing each todo on its own does, so one blocked todo
+// fails the whole change. Recurring todos repeat once the change is
+// committed, so the next occurrences land in a status that is still open
+func (s *todoService) SetStatusDone(status *models.Status) error {
+	var completed []*models.Todo
+	err := s.repo.Transaction(func(repo repository.TodoRepository) error {
+		if err := repo.UpdateStatus(status); err != nil {
+			return err
+		}
+		completion := !status.IsDone
+		ids, err := repo.FindIDs(models.TodoFilter{StatusID: &status.ID, Completed: &completion})
+		if err != nil {
+			return err
+		}
+		pending := make([]*models.Todo, 0, len(ids))
+		for _, id := range ids {
+			todo, err := repo.GetByID(id)
+			if err != nil {
+				return err
+			}
+			todo.Completed = status.IsDone
+			pending = append(pending, todo)
+		}
+
+		// Todos blocked by others in the same status wait until those are
+		// saved; a pass that saves nothing leaves only blocked todos
+		for len(pending) > 0 {
+			var blocked []*models.Todo
+			for _, todo := range pending {
+				err := s.checkBlockers(repo, todo, !todo.Completed)
+				if errors.Is(err, ErrTodoBlocked) {
+					blocked = append(blocked, todo)
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				if err := repo.Update(todo); err != nil {
+					return versionError(err)
+				}
+				completed = append(completed, todo)
+			}
+			if len(blocked) == len(pending) {
+				return ErrTodoBlocked
+			}
+			pending = blocked
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Transaction(func(repo repository.TodoRepository) error {
+		for _, todo := range completed {
+			if err := s.repeat(repo, todo, !todo.Completed); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// validateFields checks the optional todo fields shared by create and update,
+// recording every problem instead of stopping at the first one. It returns
+// the requested category when there is one
+func (s *todoService) validateFields(verr *ValidationError, title string, priority models.Priority, dueDate *time.Time, categoryID *uint) *models.Category {
+	if utf8.RuneCountInString(title) > maxTodoTitleLength {
+		verr.add("title", "max", ErrTodoTitleTooLong)
+	}
+	if priority != "" && !isValidPriority(priority) {
+		verr.add("priority", "oneof", ErrInvalidPriority)
+	}
+	if dueDate != nil && !isValidDueDate(*dueDate) {
+		verr.add("due_date", "range", ErrDueDateOutOfRange)
+	}
+	if categoryID == nil {
+		return nil
+	}
+	category, err := s.categoryRepo.GetByID(*categoryID)
+	if err != nil {
+		verr.add("category_id", "exists", ErrCategoryNotFound)
+		return nil
+	}
+	return category
+}
+
+// todoCategory returns the category a todo currently belongs to
+func (s *todoService) todoCategory(todo *models.Todo) *models.Category {
+	if todo.Category != nil || todo.CategoryID == nil {
+		return todo.Category
+	}
+	category, _ := s.categoryRepo.GetByID(*todo.CategoryID)
+	return category
+}
+
+func validateEstimate(verr *ValidationError, minutes *int) {
+	if minutes != nil && (*minutes < 0 || *minutes > maxEstimateMinutes) {
+		verr.add("estimate_minutes", "range", ErrInvalidEstimate)
+	}
 }
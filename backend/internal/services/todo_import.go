@@ -0,0 +1,402 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+)
+
+const maxImportRows = 5000
+
+var (
+	ErrImportEmpty          = errors.New("the file has no rows to import")
+	ErrImportTooManyRows    = errors.New("imports are limited to 5000 rows")
+	ErrImportUnknownField   = errors.New("column is mapped to an unknown field")
+	ErrImportDuplicateField = errors.New("another column is already mapped to this field")
+	ErrImportTitleUnmapped  = errors.New("a column must be mapped to title")
+	ErrImportInvalidDate    = errors.New("date must be formatted as YYYY-MM-DD or RFC 3339")
+	ErrImportInvalidBool    = errors.New("completed must be true or false")
+	ErrImportInvalidNumber  = errors.New("estimate must be a whole number of minutes")
+
+	errImportRolledBack = errors.New("import rolled back")
+)
+
+// importFieldAliases maps normalized header names to import fields, so
+// files exported by us and by common tools map without configuration
+var importFieldAliases = map[string]string{
+	"title":            models.ImportFieldTitle,
+	"name":             models.ImportFieldTitle,
+	"task":             models.ImportFieldTitle,
+	"description":      models.ImportFieldDescription,
+	"notes":            models.ImportFieldDescription,
+	"priority":         models.ImportFieldPriority,
+	"due_date":         models.ImportFieldDueDate,
+	"due":              models.ImportFieldDueDate,
+	"category":         models.ImportFieldCategory,
+	"list":             models.ImportFieldCategory,
+	"completed":        models.ImportFieldCompleted,
+	"done":             models.ImportFieldCompleted,
+	"tags":             models.ImportFieldTags,
+	"labels":           models.ImportFieldTags,
+	"estimate_minutes": models.ImportFieldEstimateMinutes,
+	"estimate":         models.ImportFieldEstimateMinutes,
+}
+
+// importRow is a record parsed into a create request. Errors found while
+// parsing are reported along with those of creating the todo
+type importRow struct {
+	line     int
+	req      models.CreateTodoRequest
+	category string
+	// checked marks checklist items as done, by index
+	checked []bool
+	errs    *ValidationError
+}
+
+// Import creates a todo per record in one transaction. Categories are
+// looked up by name and created when missing. Every row is validated and
+// reported; the import is committed only when all rows are valid and it is
+// not a dry run
+func (s *todoService) Import(req models.TodoImportRequest) (*models.TodoImportReport, error) {
+	columns, report, err := mapImportColumns(req.Header, req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkImportSize(len(req.Records)); err != nil {
+		return nil, err
+	}
+
+	rows := make([]importRow, len(req.Records))
+	for i, record := range req.Records {
+		createReq, categoryName, parseErr := parseImportRecord(columns, record)
+		rows[i] = importRow{line: i + 2, req: createReq, category: categoryName, errs: parseErr}
+	}
+	if err := s.importRows(rows, report, req.DryRun); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func checkImportSize(rows int) error {
+	verr := &ValidationError{}
+	switch {
+	case rows == 0:
+		verr.add("file", "required", ErrImportEmpty)
+	case rows > maxImportRows:
+		verr.add("file", "max", ErrImportTooManyRows)
+	}
+	return verr.orNil()
+}
+
+// importRows creates the todos of parsed rows in one transaction, filling
+// in the report
+func (s *todoService) importRows(rows []importRow, report *models.TodoImportReport, dryRun bool) error {
+	report.DryRun = dryRun
+	report.Rows = make([]models.TodoImportRowResult, len(rows))
+
+	// Categories created during the import are not visible outside its
+	// transaction, so validation has to learn about them here
+	categories := &importCategories{
+		CategoryRepository: s.categoryRepo,
+		byName:             map[string]*models.Category{},
+		created:            map[uint]*models.Category{},
+	}
+	importer := *s
+	importer.categoryRepo = categories
+
+	err := s.repo.Transaction(func(tx repository.TodoRepository) error {
+		for i, row := range rows {
+			result := &report.Rows[i]
+			result.Row = row.line
+			result.Title = row.req.Title
+			result.Category = row.category
+
+			createReq, parseErr := row.req, row.errs
+			if row.category != "" {
+				category, err := categories.resolve(tx, row.category, report)
+				var verr *ValidationError
+				switch {
+				case errors.As(err, &verr):
+					parseErr.addAll("", verr)
+				case err != nil:
+					return err
+				default:
+					createReq.CategoryID = &category.ID
+				}
+			}
+
+			var todo *models.Todo
+			err := tx.Transaction(func(rowTx repository.TodoRepository) error {
+				var err error
+				todo, err = importer.create(rowTx, createReq, row.checked)
+				if err == nil && len(parseErr.Fields) > 0 {
+					return parseErr
+				}
+				if err != nil && len(parseErr.Fields) > 0 {
+					var verr *ValidationError
+					if errors.As(err, &verr) {
+						parseErr.addAll("", verr)
+						return parseErr
+					}
+				}
+				return err
+			})
+			if err != nil {
+				result.Status = models.ImportStatusInvalid
+				result.Errors = importErrors(err, report.Mapping)
+				report.Invalid++
+				continue
+			}
+			result.Status = models.ImportStatusValid
+			result.TodoID = &todo.ID
+			report.Valid++
+		}
+
+		if report.Invalid > 0 || dryRun {
+			return errImportRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRolledBack) {
+		return err
+	}
+
+	report.Committed = err == nil
+	for i := range report.Rows {
+		if report.Committed {
+			report.Rows[i].Status = models.ImportStatusCreated
+		} else {
+			// IDs of rolled back todos mean nothing to the client
+			report.Rows[i].TodoID = nil
+		}
+	}
+	return nil
+}
+
+// importCategories overlays the categories created by an import on the
+// category repository
+type importCategories struct {
+	repository.CategoryRepository
+	byName  map[string]*models.Category
+	created map[uint]*models.Category
+}
+
+// resolve looks a category up by name, creating it when there is none yet
+func (c *importCategories) resolve(tx repository.TodoRepository, name string, report *models.TodoImportReport) (*models.Category, error) {
+	key := strings.ToLower(name)
+	if category, ok := c.byName[key]; ok {
+		return category, nil
+	}
+
+	verr := &ValidationError{}
+	if utf8.RuneCountInString(name) > maxCategoryNameLength {
+		verr.add(models.ImportFieldCategory, "max", ErrCategoryNameTooLong)
+		return nil, verr
+	}
+	category, created, err := tx.FindOrCreateCategory(name)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		c.created[category.ID] = category
+		report.CreatedCategories = append(report.CreatedCategories, category.Name)
+	}
+	c.byName[key] = category
+	return category, nil
+}
+
+func (c *importCategories) GetByID(id uint) (*models.Category, error) {
+	if category, ok := c.created[id]; ok {
+		return category, nil
+	}
+	return c.CategoryRepository.GetByID(id)
+}
+
+// mapImportColumns resolves the field of every column. Explicit mappings
+// are applied first; the remaining headers are matched by name
+func mapImportColumns(header []string, mapping map[string]string) ([]string, *models.TodoImportReport, error) {
+	verr := &ValidationError{}
+	report := newImportReport()
+	columns := make([]string, len(header))
+	mapped := map[string]bool{}
+
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		field, ok := mapping[name]
+		switch {
+		case !ok || field == "":
+			continue
+		case !isImportField(field):
+			verr.add("mapping."+name, "oneof", ErrImportUnknownField)
+		case mapped[field]:
+			verr.add("mapping."+name, "unique", ErrImportDuplicateField)
+		default:
+			mapped[field] = true
+			columns[i] = field
+			report.Mapping[name] = field
+		}
+	}
+
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if _, ok := mapping[name]; ok {
+			if columns[i] == "" {
+				report.IgnoredColumns = append(report.IgnoredColumns, name)
+			}
+			continue
+		}
+		field := importFieldAliases[normalizeImportHeader(name)]
+		if field == "" || mapped[field] {
+			report.IgnoredColumns = append(report.IgnoredColumns, name)
+			continue
+		}
+		mapped[field] = true
+		columns[i] = field
+		report.Mapping[name] = field
+	}
+
+	if !mapped[models.ImportFieldTitle] && len(verr.Fields) == 0 {
+		verr.add("mapping", "required", ErrImportTitleUnmapped)
+	}
+	if err := verr.orNil(); err != nil {
+		return nil, nil, err
+	}
+	return columns, report, nil
+}
+
+func newImportReport() *models.TodoImportReport {
+	return &models.TodoImportReport{
+		Mapping:           map[string]string{},
+		IgnoredColumns:    []string{},
+		CreatedCategories: []string{},
+	}
+}
+
+func normalizeImportHeader(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
+
+func isImportField(field string) bool {
+	for _, known := range importFieldAliases {
+		if field == known {
+			return true
+		}
+	}
+	return false
+}
+
+// parseImportRecord converts a record into a create request. Values that
+// cannot be parsed are reported, along with the category name to resolve.
+// Text guarded against spreadsheet formulas by the CSV export is read back
+// without its guard
+func parseImportRecord(columns []string, record []string) (models.CreateTodoRequest, string, *ValidationError) {
+	verr := &ValidationError{}
+	var req models.CreateTodoRequest
+	var categoryName string
+
+	for i, field := range columns {
+		if field == "" || i >= len(record) {
+			continue
+		}
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue
+		}
+
+		switch field {
+		case models.ImportFieldTitle:
+			req.Title = unguardCSVText(value)
+		case models.ImportFieldDescription:
+			req.Description = unguardCSVText(record[i])
+		case models.ImportFieldPriority:
+			req.Priority = models.Priority(strings.ToLower(value))
+		case models.ImportFieldDueDate:
+			due, err := parseImportDate(value)
+			if err != nil {
+				verr.add(field, "format", ErrImportInvalidDate)
+				continue
+			}
+			req.DueDate = &due
+		case models.ImportFieldCategory:
+			categoryName = unguardCSVText(value)
+		case models.ImportFieldCompleted:
+			completed, err := parseImportBool(value)
+			if err != nil {
+				verr.add(field, "format", ErrImportInvalidBool)
+				continue
+			}
+			req.Completed = completed
+		case models.ImportFieldTags:
+			req.Tags = strings.FieldsFunc(unguardCSVText(value), func(r rune) bool { return r == ',' })
+		case models.ImportFieldEstimateMinutes:
+			minutes, err := strconv.Atoi(value)
+			if err != nil {
+				verr.add(field, "format", ErrImportInvalidNumber)
+				continue
+			}
+			req.EstimateMinutes = &minutes
+		}
+	}
+	return req, categoryName, verr
+}
+
+// unguardCSVText drops the ' that the CSV export puts before text that
+// would otherwise run as a spreadsheet formula
+func unguardCSVText(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func parseImportDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes", "y", "x", "done":
+		return true, nil
+	case "no", "n":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// importErrors reports the fields of a row's error under the column they
+// were read from
+func importErrors(err error, mapping map[string]string) []models.ImportError {
+	columnOf := make(map[string]string, len(mapping))
+	for column, field := range mapping {
+		columnOf[field] = column
+	}
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		return []models.ImportError{{Message: err.Error()}}
+	}
+	errs := make([]models.ImportError, len(verr.Fields))
+	for i, f := range verr.Fields {
+		field := f.Field
+		// category_id errors concern the category column
+		if field == "category_id" {
+			field = models.ImportFieldCategory
+		}
+		base, _, _ := strings.Cut(field, "[")
+		errs[i] = models.ImportError{
+			Column:  columnOf[base],
+			Field:   field,
+			Message: f.Message,
+		}
+	}
+	return errs
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+var (
+	ErrImportUnknownSource   = errors.New("source must be todoist, trello or mstodo")
+	ErrImportInvalidExport   = errors.New("the file is not an export of this app")
+	ErrImportUnsupportedDate = errors.New("due date must be a calendar date; recurring and relative dates cannot be imported")
+)
+
+// externalDateLayouts are the due date formats other apps write
+var externalDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"Jan 2 2006",
+	"2 Jan 2006",
+}
+
+// ImportExternal creates todos from a file exported by another app, like
+// Import does for CSV records. Lists and projects become categories,
+// labels become tags and subtasks or checklists become checklist items
+func (s *todoService) ImportExternal(req models.ExternalImportRequest) (*models.TodoImportReport, error) {
+	var rows []importRow
+	var err error
+	switch req.Source {
+	case models.ImportSourceTodoist:
+		rows, err = parseTodoistExport(req.Data, req.Category)
+	case models.ImportSourceTrello:
+		rows, err = parseTrelloBoard(req.Data)
+	case models.ImportSourceMicrosoftTodo:
+		rows, err = parseMicrosoftTodoExport(req.Data)
+	default:
+		verr := &ValidationError{}
+		verr.add("source", "oneof", ErrImportUnknownSource)
+		return nil, verr
+	}
+	if err != nil {
+		verr := &ValidationError{}
+		verr.add("file", "format", fmt.Errorf("%w: %v", ErrImportInvalidExport, err))
+		return nil, verr
+	}
+	if err := checkImportSize(len(rows)); err != nil {
+		return nil, err
+	}
+
+	report := newImportReport()
+	if err := s.importRows(rows, report, req.DryRun); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// externalID is an ID that apps write either as a string or as a number
+type externalID string
+
+func (id *externalID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = externalID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = externalID(n.String())
+	return nil
+}
+
+func parseExternalDate(value string) (time.Time, error) {
+	for _, layout := range externalDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrImportUnsupportedDate
+}
+
+// addChecklistItem appends an item to the checklist of an imported row
+func (r *importRow) addChecklistItem(title string, checked bool) {
+	r.req.Checklist = append(r.req.Checklist, title)
+	r.checked = append(r.checked, checked)
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+const maxQueryLength = 500
+
+// QueryError reports what is wrong with a q= expression and where.
+// Position is the 1-based character offset of the offending token
+type QueryError struct {
+	Position int
+	Message  string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Position, e.Message)
+}
+
+type queryTokenKind int
+
+const (
+	queryEOF queryTokenKind = iota
+	queryWord
+	queryString
+	queryOp
+	queryLParen
+	queryRParen
+)
+
+type queryToken struct {
+	kind queryTokenKind
+	text string
+	// pos is the 1-based character offset of the token
+	pos int
+}
+
+// describe names a token for error messages
+func (t queryToken) describe() string {
+	switch t.kind {
+	case queryEOF:
+		return "end of query"
+	case queryString:
+		return fmt.Sprintf("%q", t.text)
+	}
+	return "\"" + t.text + "\""
+}
+
+// lexQuery splits a query into words, quoted strings, the comparison
+// operators : < <= > >= and parentheses
+func lexQuery(q string) ([]queryToken, error) {
+	runes := []rune(q)
+	var tokens []queryToken
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: queryLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: queryRParen, text: ")", pos: pos})
+			i++
+		case r == ':' || r == '<' || r == '>':
+			op := string(r)
+			if r != ':' && i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			tokens = append(tokens, queryToken{kind: queryOp, text: op, pos: pos})
+			i += len(op)
+		case r == '"':
+			var b strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, &QueryError{Position: pos, Message: "unterminated quoted string"}
+			}
+			i++
+			tokens = append(tokens, queryToken{kind: queryString, text: b.String(), pos: pos})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()":<>=`, runes[i]) {
+				i++
+			}
+			if i == start {
+				return nil, &QueryError{Position: pos, Message: fmt.Sprintf("unexpected %q", r)}
+			}
+			tokens = append(tokens, queryToken{kind: queryWord, text: string(runes[start:i]), pos: pos})
+		}
+	}
+	return append(tokens, queryToken{kind: queryEOF, pos: len(runes) + 1}), nil
+}
+
+// ParseTodoQuery parses a q= expression such as
+//
+//	priority:high AND (category:Work OR tag:urgent) AND due<2026-11-01 AND NOT completed
+//
+// Terms side by side are joined with AND, which binds tighter than OR; NOT
+// binds tightest. Bare words and quoted strings search titles and
+// descriptions. Dates and the relative due windows are read in now's
+// location. An empty query returns nil
+func ParseTodoQuery(q string, now time.Time) (models.TodoQuery, error) {
+	if len([]rune(q)) > maxQueryLength {
+		return nil, &QueryError{Position: maxQueryLength + 1, Message: fmt.Sprintf("query must be at most %d characters", maxQueryLength)}
+	}
+	tokens, err := lexQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	if tokens[0].kind == queryEOF {
+		return nil, nil
+	}
+
+	p := &queryParser{tokens: tokens, today: startOfDay(now)}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != queryEOF {
+		return nil, p.unexpected(t)
+	}
+	return expr, nil
+}
+
+type queryParser struct {
+	tokens []queryToken
+	next   int
+	today  time.Time
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.next]
+}
+
+func (p *queryParser) advance() queryToken {
+	t := p.tokens[p.next]
+	if t.kind != queryEOF {
+		p.next++
+	}
+	return t
+}
+
+// keyword reports whether t is one of the upper case operators AND, OR or
+// NOT; in lower case they are searched for like any other word
+func (t queryToken) keyword(name string) bool {
+	return t.kind == queryWord && t.text == name
+}
+
+func (p *queryParser) unexpected(t queryToken) error {
+	if t.kind == queryRParen {
+		return &QueryError{Position: t.pos, Message: "unmatched \")\""}
+	}
+	return &QueryError{Position: t.pos, Message: "unexpected " + t.describe()}
+}
+
+func (p *queryParser) parseOr() (models.TodoQuery, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("OR") {
+		p.advance()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = models.QueryOr{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (models.TodoQuery, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		switch {
+		case t.keyword("AND"):
+			p.advance()
+		case t.keyword("OR"), t.kind == queryEOF, t.kind == queryRParen, t.kind == queryOp:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = models.QueryAnd{Left: left, Right: right}
+	}
+}
+
+func (p *queryParser) parseUnary() (models.TodoQuery, error) {
+	if p.peek().keyword("NOT") {
+		p.advance()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return models.QueryNot{Operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (models.TodoQuery, error) {
+	t := p.advance()
+	switch {
+	case t.kind == queryLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing.kind != queryRParen {
+			if closing.kind == queryEOF {
+				return nil, &QueryError{Position: t.pos, Message: "unclosed \"(\""}
+			}
+			return nil, p.unexpected(closing)
+		}
+		p.advance()
+		return expr, nil
+	case t.kind == queryString:
+		return models.QueryMatch{Field: models.QueryText, Value: t.text}, nil
+	case t.kind == queryWord && !t.keyword("AND") && !t.keyword("OR"):
+		if p.peek().kind == queryOp {
+			return p.parseTerm(t)
+		}
+		switch strings.ToLower(t.text) {
+		case "completed", "blocked":
+			return models.QueryMatch{Field: models.QueryField(strings.ToLower(t.text)), Value: "true"}, nil
+		}
+		return models.QueryMatch{Field: models.QueryText, Value: t.text}, nil
+	}
+	return nil, &QueryError{Position: t.pos, Message: "expected a term, got " + t.describe()}
+}
+
+// parseTerm reads the operator and value of a field:value or due<date term
+func (p *queryParser) parseTerm(field queryToken) (models.TodoQuery, error) {
+	op := p.advance()
+	value := p.advance()
+	if value.kind != queryWord && value.kind != queryString {
+		return nil, &QueryError{Position: value.pos, Message: fmt.Sprintf("expected a value after %q, got %s", op.text, value.describe())}
+	}
+
+	name := strings.ToLower(field.text)
+	if name == "due" {
+		return p.parseDue(op, value)
+	}
+	if op.text != ":" {
+		return nil, &QueryError{Position: op.pos, Message: fmt.Sprintf("%s only supports \":\"", name)}
+	}
+
+	match := models.QueryMatch{Field: models.QueryField(name), Value: value.text}
+	switch match.Field {
+	case models.QueryPriority:
+		match.Value = strings.ToLower(value.text)
+		if !isValidPriority(models.Priority(match.Value)) {
+			return nil, &QueryError{Position: value.pos, Message: "priority must be one of high, medium or low"}
+		}
+	case models.QueryCategory, models.QueryTag, models.QueryStatus:
+		if strings.TrimSpace(value.text) == "" {
+			return nil, &QueryError{Position: value.pos, Message: name + " must not be empty"}
+		}
+	case models.QueryCompleted, models.QueryBlocked:
+		match.Value = strings.ToLower(value.text)
+		if match.Value != "true" && match.Value != "false" {
+			return nil, &QueryError{Position: value.pos, Message: name + " must be true or false"}
+		}
+	default:
+		key, ok := strings.CutPrefix(field.text, "cf.")
+		if !ok {
+			return nil, &QueryError{Position: field.pos, Message: fmt.Sprintf("unknown field %q; expected priority, category, tag, status, completed, blocked, due or cf.<key>", field.text)}
+		}
+		if !IsValidCustomFieldKey(key) {
+			return nil, &QueryError{Position: field.pos, Message: "custom field " + ErrCustomFieldKeyInvalid.Error()}
+		}
+		match.Field, match.Key = models.QueryCustomField, key
+	}
+	return match, nil
+}
+
+// parseDue reads a due date term. due: takes a date or one of the windows
+// overdue, today, tomorrow, upcoming and none; the comparisons take a date,
+// today or tomorrow
+func (p *queryParser) parseDue(op, value queryToken) (models.TodoQuery, error) {
+	word := strings.ToLower(value.text)
+	if op.text == ":" {
+		if word == "none" {
+			return models.QueryDue{}, nil
+		}
+		if from, to, ok := dueWindowBounds(models.DueWindow(word), p.today); ok {
+			due := models.QueryDue{To: &to}
+			if !from.IsZero() {
+				due.From = &from
+			}
+			return due, nil
+		}
+	}
+
+	var day time.Time
+	switch word {
+	case "today":
+		day = p.today
+	case "tomorrow":
+		day = p.today.AddDate(0, 0, 1)
+	default:
+		var err error
+		if day, err = time.ParseInLocation("2006-01-02", value.text, p.today.Location()); err != nil {
+			message := "due must be a date (YYYY-MM-DD), today or tomorrow"
+			if op.text == ":" {
+				message = "due must be a date (YYYY-MM-DD) or one of overdue, today, tomorrow, upcoming or none"
+			}
+			return nil, &QueryError{Position: value.pos, Message: message}
+		}
+	}
+	next := day.AddDate(0, 0, 1)
+
+	switch op.text {
+	case "<":
+		return models.QueryDue{To: &day}, nil
+	case "<=":
+		return models.QueryDue{To: &next}, nil
+	case ">":
+		return models.QueryDue{From: &next}, nil
+	case ">=":
+		return models.QueryDue{From: &day}, nil
+	}
+	return models.QueryDue{From: &day, To: &next}, nil
+}
+
+// resolveFilter prepares a filter for the repository: its relative due
+// window becomes date bounds and its query is parsed
+func resolveFilter(filter models.TodoFilter, now time.Time) (models.TodoFilter, error) {
+	filter = resolveDueWindow(filter, now)
+	expr, err := ParseTodoQuery(filter.Query, now.In(filterLocation(filter)))
+	if err != nil {
+		return models.TodoFilter{}, err
+	}
+	filter.Expr = expr
+	return filter, nil
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+var ErrTodoistInvalidPriority = errors.New("priority must be a number from 1 to 4")
+
+// todoistPriorities maps Todoist API priorities, where 4 is the most
+// urgent (p1), to ours. 1 is Todoist's default and is left to ours
+var todoistPriorities = map[int]models.Priority{
+	4: models.PriorityHigh,
+	3: models.PriorityMedium,
+	2: models.PriorityLow,
+}
+
+// parseTodoistExport reads a Todoist JSON backup, or a project exported as
+// CSV, whose tasks go to category since the file does not name the project
+func parseTodoistExport(data []byte, category string) ([]importRow, error) {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if len(data) > 0 && data[0] == '{' {
+		return parseTodoistBackup(data)
+	}
+	return parseTodoistCSV(data, category)
+}
+
+// parseTodoistCSV reads a project CSV export. Indented tasks are subtasks
+// and join the checklist of the task above them, notes are added to its
+// description and sections are skipped. Labels are the @words of a task
+func parseTodoistCSV(data []byte, category string) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["TYPE"]; !ok {
+		return nil, errors.New("TYPE column is missing")
+	}
+	if _, ok := columns["CONTENT"]; !ok {
+		return nil, errors.New("CONTENT column is missing")
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		switch strings.ToLower(get("TYPE")) {
+		case "task":
+			title, labels := todoistContent(get("CONTENT"))
+			indent, _ := strconv.Atoi(get("INDENT"))
+			if indent > 1 && len(rows) > 0 {
+				rows[len(rows)-1].addChecklistItem(title, false)
+				continue
+			}
+
+			line, _ := reader.FieldPos(0)
+			row := importRow{
+				line:     line,
+				req:      models.CreateTodoRequest{Title: title, Description: get("DESCRIPTION"), Tags: labels},
+				category: category,
+				errs:     &ValidationError{},
+			}
+			if value := get("PRIORITY"); value != "" {
+				// The CSV uses the p1-p4 of the app, p1 being the most urgent
+				priority, err := strconv.Atoi(value)
+				if err != nil || priority < 1 || priority > 4 {
+					row.errs.add(models.ImportFieldPriority, "format", ErrTodoistInvalidPriority)
+				} else {
+					row.req.Priority = todoistPriorities[5-priority]
+				}
+			}
+			if value := get("DATE"); value != "" {
+				if due, err := parseExternalDate(value); err != nil {
+					row.errs.add(models.ImportFieldDueDate, "format", err)
+				} else {
+					row.req.DueDate = &due
+				}
+			}
+			rows = append(rows, row)
+		case "note":
+			if len(rows) > 0 {
+				req := &rows[len(rows)-1].req
+				if req.Description != "" {
+					req.Description += "\n\n"
+				}
+				req.Description += get("CONTENT")
+			}
+		}
+	}
+	return rows, nil
+}
+
+// todoistContent splits the content of a CSV task into its title and the
+// labels written in it as @label
+func todoistContent(content string) (string, []string) {
+	var words, labels []string
+	for _, word := range strings.Fields(content) {
+		if len(word) > 1 && word[0] == '@' {
+			labels = append(labels, word[1:])
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " "), labels
+}
+
+type todoistBackup struct {
+	Projects []todoistProject `json:"projects"`
+	Items    []todoistItem    `json:"items"`
+	// Tasks holds the items of exports made through the REST API
+	Tasks []todoistItem `json:"tasks"`
+}
+
+type todoistProject struct {
+	ID   externalID `json:"id"`
+	Name string     `json:"name"`
+}
+
+type todoistItem struct {
+	ID          externalID `json:"id"`
+	ProjectID   externalID `json:"project_id"`
+	ParentID    externalID `json:"parent_id"`
+	Content     string     `json:"content"`
+	Description string     `json:"description"`
+	Priority    int        `json:"priority"`
+	Due         *struct {
+		Date string `json:"date"`
+	} `json:"due"`
+	Labels      []string `json:"labels"`
+	Checked     bool     `json:"checked"`
+	IsCompleted bool     `json:"is_completed"`
+}
+
+// parseTodoistBackup reads a JSON backup. Projects become categories and
+// subtasks, however deep, join the checklist of their top-level task. Rows
+// are numbered by the position of the task among the items
+func parseTodoistBackup(data []byte) ([]importRow, error) {
+	var backup todoistBackup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, err
+	}
+	items := append(backup.Items, backup.Tasks...)
+	if backup.Projects == nil && items == nil {
+		return nil, errors.New("no projects or items found")
+	}
+
+	projects := make(map[externalID]string, len(backup.Projects))
+	for _, project := range backup.Projects {
+		projects[project.ID] = project.Name
+	}
+	byID := make(map[externalID]*todoistItem, len(items))
+	for i := range items {
+		byID[items[i].ID] = &items[i]
+	}
+	// root follows parents up to the top-level task. Items whose parent is
+	// missing are top-level, and so are those caught in a cycle
+	root := func(item *todoistItem) *todoistItem {
+		top := item
+		for steps := 0; top.ParentID != ""; steps++ {
+			parent, ok := byID[top.ParentID]
+			if !ok {
+				return top
+			}
+			if steps == len(items) {
+				return item
+			}
+			top = parent
+		}
+		return top
+	}
+
+	var rows []importRow
+	rowOf := map[externalID]int{}
+	for i := range items {
+		item := &items[i]
+		if root(item) != item {
+			continue
+		}
+		row := importRow{
+			line: i + 1,
+			req: models.CreateTodoRequest{
+				Title:       strings.TrimSpace(item.Content),
+				Description: item.Description,
+				Completed:   item.Checked || item.IsCompleted,
+				Priority:    todoistPriorities[item.Priority],
+				Tags:        item.Labels,
+			},
+			category: projects[item.ProjectID],
+			errs:     &ValidationError{},
+		}
+		if item.Due != nil && item.Due.Date != "" {
+			if due, err := parseExternalDate(item.Due.Date); err != nil {
+				row.errs.add(models.ImportFieldDueDate, "format", err)
+			} else {
+				row.req.DueDate = &due
+			}
+		}
+		rowOf[item.ID] = len(rows)
+		rows = append(rows, row)
+	}
+
+	for i := range items {
+		item := &items[i]
+		top := root(item)
+		if top == item {
+			continue
+		}
+		rows[rowOf[top.ID]].addChecklistItem(strings.TrimSpace(item.Content), item.Checked || item.IsCompleted)
+	}
+	return rows, nil
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+const todoTxtDate = "2006-01-02"
+
+var ErrTodoTxtInvalidDue = errors.New("due must be formatted as due:YYYY-MM-DD")
+
+// todoTxtPriorities maps priorities to todo.txt letters. Letters past C
+// are read as low
+var todoTxtPriorities = map[models.Priority]byte{
+	models.PriorityHigh:   'A',
+	models.PriorityMedium: 'B',
+	models.PriorityLow:    'C',
+}
+
+// FormatTodoTxt writes a todo as a todo.txt line. The category becomes a
+// +project and tags become @contexts; a completed todo keeps its priority
+// as pri:X since todo.txt drops the (X) marker on completion. Title words
+// that would read back as markers are escaped with a backslash.
+// Descriptions have no place in the format and are left out
+func FormatTodoTxt(todo *models.Todo) string {
+	var parts []string
+	letter, hasPriority := todoTxtPriorities[todo.Priority]
+	if todo.Completed {
+		parts = append(parts, "x")
+		if !todo.UpdatedAt.IsZero() {
+			parts = append(parts, todo.UpdatedAt.Format(todoTxtDate))
+		}
+	} else if hasPriority {
+		parts = append(parts, "("+string(letter)+")")
+	}
+	if !todo.CreatedAt.IsZero() {
+		parts = append(parts, todo.CreatedAt.Format(todoTxtDate))
+	}
+
+	for i, word := range strings.Fields(todo.Title) {
+		parts = append(parts, todoTxtTitleWord(word, i == 0))
+	}
+	if todo.Category != nil {
+		parts = append(parts, "+"+todoTxtName(todo.Category.Name))
+	}
+	for _, tag := range todo.Tags {
+		parts = append(parts, "@"+todoTxtName(tag.Name))
+	}
+	if todo.DueDate != nil {
+		parts = append(parts, "due:"+todo.DueDate.Format(todoTxtDate))
+	}
+	if todo.Completed && hasPriority {
+		parts = append(parts, "pri:"+string(letter))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseTodoTxt reads a todo.txt line. Words that are not projects,
+// contexts, due: or (on completed tasks) pri: make up the title, less one
+// leading backslash that escapes a marker. An unreadable due date is
+// reported while the rest of the line is still parsed
+func ParseTodoTxt(line string) (models.TodoTxtTask, error) {
+	var task models.TodoTxtTask
+	verr := &ValidationError{}
+	words := strings.Fields(line)
+
+	if len(words) > 0 && words[0] == "x" {
+		task.Completed = true
+		words = words[1:]
+		if date, ok := parseTodoTxtDate(words); ok {
+			task.CompletedOn = &date
+			words = words[1:]
+		}
+	} else if len(words) > 0 {
+		if priority, ok := parseTodoTxtPriority(words[0]); ok {
+			task.Priority = priority
+			words = words[1:]
+		}
+	}
+	if date, ok := parseTodoTxtDate(words); ok {
+		task.CreatedOn = &date
+		words = words[1:]
+	}
+
+	var title []string
+	for _, word := range words {
+		switch {
+		case len(word) > 1 && word[0] == '+':
+			task.Projects = append(task.Projects, todoTxtNameOf(word[1:]))
+		case len(word) > 1 && word[0] == '@':
+			task.Contexts = append(task.Contexts, todoTxtNameOf(word[1:]))
+		case strings.HasPrefix(word, "due:"):
+			due, err := time.Parse(todoTxtDate, strings.TrimPrefix(word, "due:"))
+			if err != nil {
+				verr.add(models.ImportFieldDueDate, "format", ErrTodoTxtInvalidDue)
+				continue
+			}
+			task.DueDate = &due
+		case task.Completed && strings.HasPrefix(word, "pri:"):
+			if priority, ok := parseTodoTxtPriority("(" + strings.TrimPrefix(word, "pri:") + ")"); ok {
+				task.Priority = priority
+				continue
+			}
+			title = append(title, word)
+		default:
+			title = append(title, strings.TrimPrefix(word, `\`))
+		}
+	}
+	task.Title = strings.Join(title, " ")
+	return task, verr.orNil()
+}
+
+func parseTodoTxtPriority(word string) (models.Priority, bool) {
+	if len(word) != 3 || word[0] != '(' || word[2] != ')' || word[1] < 'A' || word[1] > 'Z' {
+		return "", false
+	}
+	for priority, letter := range todoTxtPriorities {
+		if word[1] == letter {
+			return priority, true
+		}
+	}
+	return models.PriorityLow, true
+}
+
+func parseTodoTxtDate(words []string) (time.Time, bool) {
+	if len(words) == 0 {
+		return time.Time{}, false
+	}
+	date, err := time.Parse(todoTxtDate, words[0])
+	return date, err == nil
+}
+
+// todoTxtTitleWord escapes a title word that ParseTodoTxt would read as a
+// marker: a project, context, due: or pri: anywhere, and the completion
+// mark, a priority or a date at the start of the title. Words starting
+// with the escape character are escaped too, so it is not lost
+func todoTxtTitleWord(word string, first bool) string {
+	escape := strings.HasPrefix(word, `\`) ||
+		len(word) > 1 && (word[0] == '+' || word[0] == '@') ||
+		strings.HasPrefix(word, "due:") || strings.HasPrefix(word, "pri:")
+	if first && !escape {
+		_, isPriority := parseTodoTxtPriority(word)
+		_, isDate := parseTodoTxtDate([]string{word})
+		escape = word == "x" || isPriority || isDate
+	}
+	if escape {
+		return `\` + word
+	}
+	return word
+}
+
+// todoTxtName turns a name into a single word, since projects and contexts
+// end at whitespace
+func todoTxtName(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
+
+func todoTxtNameOf(word string) string {
+	return strings.ReplaceAll(word, "_", " ")
+}
+
+// ImportTodoTxt creates a todo per non-blank line of a todo.txt file, like
+// Import does for CSV records. The first project names the category and
+// any further ones become tags along with the contexts
+func (s *todoService) ImportTodoTxt(req models.TodoTxtImportRequest) (*models.TodoImportReport, error) {
+	var rows []importRow
+	for i, line := range req.Lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		task, err := ParseTodoTxt(line)
+		parseErr := &ValidationError{}
+		errors.As(err, &parseErr)
+		row := importRow{
+			line: i + 1,
+			req: models.CreateTodoRequest{
+				Title:     task.Title,
+				Completed: task.Completed,
+				Priority:  task.Priority,
+				DueDate:   task.DueDate,
+				Tags:      task.Contexts,
+			},
+			errs: parseErr,
+		}
+		if len(task.Projects) > 0 {
+			row.category = task.Projects[0]
+			row.req.Tags = append(row.req.Tags, task.Projects[1:]...)
+		}
+		rows = append(rows, row)
+	}
+	if err := checkImportSize(len(rows)); err != nil {
+		return nil, err
+	}
+
+	report := newImportReport()
+	if err := s.importRows(rows, report, req.DryRun); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
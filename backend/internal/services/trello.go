@@ -0,0 +1,116 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+type trelloBoard struct {
+	Lists      []trelloList      `json:"lists"`
+	Cards      []trelloCard      `json:"cards"`
+	Checklists []trelloChecklist `json:"checklists"`
+}
+
+type trelloList struct {
+	ID     externalID `json:"id"`
+	Name   string     `json:"name"`
+	Closed bool       `json:"closed"`
+}
+
+type trelloCard struct {
+	ID          externalID `json:"id"`
+	ListID      externalID `json:"idList"`
+	Name        string     `json:"name"`
+	Desc        string     `json:"desc"`
+	Closed      bool       `json:"closed"`
+	Due         string     `json:"due"`
+	DueComplete bool       `json:"dueComplete"`
+	Labels      []struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+}
+
+type trelloChecklist struct {
+	CardID     externalID `json:"idCard"`
+	Pos        float64    `json:"pos"`
+	CheckItems []struct {
+		Name  string  `json:"name"`
+		State string  `json:"state"`
+		Pos   float64 `json:"pos"`
+	} `json:"checkItems"`
+}
+
+// parseTrelloBoard reads a board exported as JSON. Each list becomes a
+// category and each card a todo, with its labels as tags and the items of
+// its checklists, in board order, as checklist items. A card is completed
+// when its due date is marked complete. Archived cards and lists are
+// skipped. Rows are numbered by the position of the card in the file
+func parseTrelloBoard(data []byte) ([]importRow, error) {
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, err
+	}
+	if board.Lists == nil && board.Cards == nil {
+		return nil, errors.New("no lists or cards found")
+	}
+
+	lists := make(map[externalID]trelloList, len(board.Lists))
+	for _, list := range board.Lists {
+		lists[list.ID] = list
+	}
+	sort.SliceStable(board.Checklists, func(i, j int) bool {
+		return board.Checklists[i].Pos < board.Checklists[j].Pos
+	})
+	checklists := map[externalID][]trelloChecklist{}
+	for _, checklist := range board.Checklists {
+		checklists[checklist.CardID] = append(checklists[checklist.CardID], checklist)
+	}
+
+	var rows []importRow
+	for i, card := range board.Cards {
+		list := lists[card.ListID]
+		if card.Closed || list.Closed {
+			continue
+		}
+
+		row := importRow{
+			line: i + 1,
+			req: models.CreateTodoRequest{
+				Title:       strings.TrimSpace(card.Name),
+				Description: card.Desc,
+				Completed:   card.DueComplete,
+			},
+			category: strings.TrimSpace(list.Name),
+			errs:     &ValidationError{},
+		}
+		for _, label := range card.Labels {
+			// Labels may have only a color
+			name := label.Name
+			if strings.TrimSpace(name) == "" {
+				name = label.Color
+			}
+			row.req.Tags = append(row.req.Tags, name)
+		}
+		if card.Due != "" {
+			if due, err := parseExternalDate(card.Due); err != nil {
+				row.errs.add(models.ImportFieldDueDate, "format", ErrImportInvalidDate)
+			} else {
+				row.req.DueDate = &due
+			}
+		}
+		for _, checklist := range checklists[card.ID] {
+			items := checklist.CheckItems
+			sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+			for _, item := range items {
+				row.addChecklistItem(strings.TrimSpace(item.Name), item.State == "complete")
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
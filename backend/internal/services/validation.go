@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+)
+
+var (
+	// MinDueDate and MaxDueDate bound the due dates accepted for todos
+	MinDueDate = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	MaxDueDate = time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+)
+
+const (
+	maxTodoTitleLength    = 255
+	maxCategoryNameLength = 100
+	maxCommentBodyLength  = 10000
+	maxEstimateMinutes    = 525600
+	maxTodoTags           = 20
+)
+
+// FieldError describes a single invalid field of a request
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	err     error
+}
+
+// ValidationError lists every invalid field of a request so clients can
+// highlight all of them at once
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Field + ": " + f.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Unwrap exposes the sentinel errors behind each field so callers can still
+// match them with errors.Is
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		if f.err != nil {
+			errs = append(errs, f.err)
+		}
+	}
+	return errs
+}
+
+func (e *ValidationError) add(field, rule string, err error) {
+	e.Fields = append(e.Fields, FieldError{
+		Field:   field,
+		Rule:    rule,
+		Message: err.Error(),
+		err:     err,
+	})
+}
+
+// addAll copies the fields of another validation error under a prefix
+func (e *ValidationError) addAll(prefix string, other *ValidationError) {
+	for _, f := range other.Fields {
+		f.Field = prefix + f.Field
+		e.Fields = append(e.Fields, f)
+	}
+}
+
+// orNil returns the validation error only when at least one field failed
+func (e *ValidationError) orNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// withFieldPrefix qualifies the fields of a validation error, e.g. with the
+// index of a batch item. Other errors are returned unchanged
+func withFieldPrefix(err error, prefix string) error {
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+	prefixed := &ValidationError{}
+	prefixed.addAll(prefix, verr)
+	return prefixed
+}
+
+// validateTagNames trims tag names and drops duplicates
+func validateTagNames(verr *ValidationError, names []string) []string {
+	tags := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		field := fmt.Sprintf("tags[%d]", i)
+		switch {
+		case name == "":
+			verr.add(field, "required", ErrTagNameRequired)
+		case utf8.RuneCountInString(name) > maxTagNameLength:
+			verr.add(field, "max", ErrTagNameTooLong)
+		case !seen[name]:
+			seen[name] = true
+			tags = append(tags, name)
+		}
+	}
+	if len(tags) > maxTodoTags {
+		verr.add("tags", "max", ErrTooManyTags)
+	}
+	return tags
+}
+
+func isValidPriority(p models.Priority) bool {
+	return p == models.PriorityHigh || p == models.PriorityMedium || p == models.PriorityLow
+}
+
+func isValidDueDate(d time.Time) bool {
+	return !d.Before(MinDueDate) && d.Before(MaxDueDate)
+}
+
+func isValidColor(color string) bool {
+	return hexColorPattern.MatchString(color)
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/industrix-todo-app/backend/internal/config"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// Object is an open blob. It can seek so downloads can serve byte ranges
+type Object interface {
+	io.ReadSeekCloser
+}
+
+// Storage keeps attachment blobs under opaque keys chosen by the caller
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New builds the storage backend selected by the configuration
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageBackend {
+	case "local":
+		return NewLocalStorage(cfg.StorageDir)
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Bucket:          cfg.S3Bucket,
+			Region:          cfg.S3Region,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+		}, nil)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+	}
+}
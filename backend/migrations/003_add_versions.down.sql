@@ -0,0 +1,3 @@
+-- Drop version columns
+ALTER TABLE todos DROP COLUMN IF EXISTS version;
+ALTER TABLE categories DROP COLUMN IF EXISTS version;
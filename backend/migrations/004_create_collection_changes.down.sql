@@ -0,0 +1,2 @@
+-- Drop collection_changes table
+DROP TABLE IF EXISTS collection_changes;
@@ -0,0 +1,5 @@
+-- Track deletions so list endpoints can compute Last-Modified
+CREATE TABLE IF NOT EXISTS collection_changes (
+    collection VARCHAR(50) PRIMARY KEY,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
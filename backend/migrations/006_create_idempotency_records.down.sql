@@ -0,0 +1,3 @@
+-- Drop idempotency_records table and indexes
+DROP INDEX IF EXISTS idx_idempotency_records_expires_at;
+DROP TABLE IF EXISTS idempotency_records;
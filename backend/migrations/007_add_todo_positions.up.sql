@@ -0,0 +1,5 @@
+-- Add manual ordering rank; existing rows are ranked by the server on startup.
+-- Ranks compare byte by byte, so the column uses the C collation
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS position VARCHAR(255) COLLATE "C" NOT NULL DEFAULT '';
+
+CREATE INDEX idx_todos_position ON todos(category_id, position);
@@ -0,0 +1,4 @@
+-- Drop statuses table and indexes
+DROP INDEX IF EXISTS idx_todos_status_id;
+ALTER TABLE todos DROP COLUMN IF EXISTS status_id;
+DROP TABLE IF EXISTS statuses;
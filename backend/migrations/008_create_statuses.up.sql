@@ -0,0 +1,14 @@
+-- Create statuses table for the kanban board; defaults are seeded on startup
+CREATE TABLE IF NOT EXISTS statuses (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    is_done BOOLEAN NOT NULL DEFAULT FALSE,
+    wip_limit INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS status_id INTEGER REFERENCES statuses(id);
+
+CREATE INDEX idx_todos_status_id ON todos(status_id, position);
@@ -0,0 +1,10 @@
+-- Create todo_dependencies table: todo_id is blocked by blocker_id
+CREATE TABLE IF NOT EXISTS todo_dependencies (
+    todo_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    blocker_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (todo_id, blocker_id),
+    CHECK (todo_id <> blocker_id)
+);
+
+CREATE INDEX idx_todo_dependencies_blocker_id ON todo_dependencies(blocker_id);
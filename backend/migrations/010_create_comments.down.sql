@@ -0,0 +1,3 @@
+-- Drop comments table and indexes
+DROP INDEX IF EXISTS idx_comments_todo_id;
+DROP TABLE IF EXISTS comments;
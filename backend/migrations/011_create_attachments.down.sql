@@ -0,0 +1,3 @@
+-- Drop attachments table and indexes
+DROP INDEX IF EXISTS idx_attachments_todo_id;
+DROP TABLE IF EXISTS attachments;
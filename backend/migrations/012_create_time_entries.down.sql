@@ -0,0 +1,6 @@
+-- Drop time_entries table, indexes and todo estimates
+DROP INDEX IF EXISTS idx_time_entries_running;
+DROP INDEX IF EXISTS idx_time_entries_started_at;
+DROP INDEX IF EXISTS idx_time_entries_todo_id;
+DROP TABLE IF EXISTS time_entries;
+ALTER TABLE todos DROP COLUMN IF EXISTS estimate_minutes;
@@ -0,0 +1,21 @@
+-- Add estimates to todos
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS estimate_minutes INTEGER CHECK (estimate_minutes >= 0);
+
+-- Create time_entries table; a running timer has no ended_at
+CREATE TABLE IF NOT EXISTS time_entries (
+    id SERIAL PRIMARY KEY,
+    todo_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    user_name VARCHAR(100) NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    duration_seconds BIGINT NOT NULL DEFAULT 0,
+    note VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_time_entries_todo_id ON time_entries(todo_id);
+CREATE INDEX idx_time_entries_started_at ON time_entries(started_at);
+
+-- At most one running timer per user
+CREATE UNIQUE INDEX idx_time_entries_running ON time_entries(user_name) WHERE ended_at IS NULL;
@@ -0,0 +1,3 @@
+-- Drop checklist_items table and indexes
+DROP INDEX IF EXISTS idx_checklist_items_todo_id;
+DROP TABLE IF EXISTS checklist_items;
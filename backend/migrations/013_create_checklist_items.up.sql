@@ -0,0 +1,12 @@
+-- Create checklist_items table, ordered per todo by position
+CREATE TABLE IF NOT EXISTS checklist_items (
+    id SERIAL PRIMARY KEY,
+    todo_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    checked BOOLEAN NOT NULL DEFAULT FALSE,
+    position VARCHAR(255) COLLATE "C" NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_checklist_items_todo_id ON checklist_items(todo_id, position);
@@ -0,0 +1,3 @@
+-- Drop custom field columns
+ALTER TABLE todos DROP COLUMN IF EXISTS custom_fields;
+ALTER TABLE categories DROP COLUMN IF EXISTS custom_fields;
@@ -0,0 +1,3 @@
+-- Add custom field definitions to categories and values to todos
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}';
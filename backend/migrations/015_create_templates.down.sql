@@ -0,0 +1,2 @@
+-- Drop templates table
+DROP TABLE IF EXISTS templates;
@@ -0,0 +1,8 @@
+-- Create calendar_tokens table; each user has at most one feed token, kept
+-- as a SHA-256 hash
+CREATE TABLE IF NOT EXISTS calendar_tokens (
+    id SERIAL PRIMARY KEY,
+    user_name VARCHAR(100) NOT NULL UNIQUE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
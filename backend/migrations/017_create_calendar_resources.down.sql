@@ -0,0 +1,2 @@
+-- Drop calendar_resources table
+DROP TABLE IF EXISTS calendar_resources;
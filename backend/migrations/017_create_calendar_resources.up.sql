@@ -0,0 +1,7 @@
+-- Create calendar_resources table; it keeps the names and UIDs CalDAV
+-- clients chose for the todos they created
+CREATE TABLE IF NOT EXISTS calendar_resources (
+    todo_id INTEGER PRIMARY KEY REFERENCES todos(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    uid VARCHAR(255) NOT NULL UNIQUE
+);
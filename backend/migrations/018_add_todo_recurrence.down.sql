@@ -0,0 +1,2 @@
+-- Drop the todo recurrence rule
+ALTER TABLE todos DROP COLUMN IF EXISTS recurrence;
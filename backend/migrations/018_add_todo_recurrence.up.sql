@@ -0,0 +1,2 @@
+-- Add a recurrence rule to todos
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS recurrence VARCHAR(100) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- Drop smart lists table
+DROP TABLE IF EXISTS smart_lists;
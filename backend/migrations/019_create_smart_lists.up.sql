@@ -0,0 +1,12 @@
+-- Create smart lists table; filter holds the saved todo filter as JSONB
+-- and key names the built-in lists
+CREATE TABLE IF NOT EXISTS smart_lists (
+    id SERIAL PRIMARY KEY,
+    key VARCHAR(20) NOT NULL DEFAULT '',
+    name VARCHAR(100) NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_smart_lists_key ON smart_lists(key) WHERE key <> '';
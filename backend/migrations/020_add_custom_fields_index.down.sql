@@ -0,0 +1,2 @@
+-- Drop the custom field values index
+DROP INDEX IF EXISTS idx_todos_custom_fields;
@@ -0,0 +1,2 @@
+-- Index custom field values for containment (@>) filters
+CREATE INDEX IF NOT EXISTS idx_todos_custom_fields ON todos USING GIN (custom_fields);
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/industrix-todo-app/backend/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAttachmentRepository is a mock implementation of AttachmentRepository
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) Create(attachment *models.Attachment) error {
+	args := m.Called(attachment)
+	attachment.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) GetByTodo(todoID uint) ([]models.Attachment, error) {
+	args := m.Called(todoID)
+	return args.Get(0).([]models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetByID(id uint) (*models.Attachment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) StorageKeys(todoID uint) ([]string, error) {
+	args := m.Called(todoID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newAttachmentService(t *testing.T, repo *MockAttachmentRepository, todoRepo *MockTodoRepository) (services.AttachmentService, storage.Storage) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	return services.NewAttachmentService(repo, todoRepo, store, 64, []string{"image/*", "text/plain"}), store
+}
+
+func TestAttachmentService_Upload(t *testing.T) {
+	todoRepo := new(MockTodoRepository)
+	todoRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+
+	t.Run("stores an allowed file", func(t *testing.T) {
+		repo := new(MockAttachmentRepository)
+		service, store := newAttachmentService(t, repo, todoRepo)
+		repo.On("Create", mock.AnythingOfType("*models.Attachment")).Return(nil).Once()
+
+		attachment, err := service.Upload(1, "alice", services.AttachmentUpload{
+			FileName: "../../shot.png",
+			Size:     int64(len(pngHeader)),
+			Body:     bytes.NewReader(pngHeader),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "shot.png", attachment.FileName)
+		assert.Equal(t, "image/png", attachment.ContentType)
+		assert.Equal(t, int64(len(pngHeader)), attachment.Size)
+		assert.Equal(t, "alice", attachment.UploadedBy)
+
+		object, err := store.Open(context.Background(), attachment.StorageKey)
+		require.NoError(t, err)
+		defer object.Close()
+		data, _ := io.ReadAll(object)
+		assert.Equal(t, pngHeader, data)
+	})
+
+	t.Run("falls back to the extension for plain text", func(t *testing.T) {
+		repo := new(MockAttachmentRepository)
+		service, _ := newAttachmentService(t, repo, todoRepo)
+		repo.On("Create", mock.AnythingOfType("*models.Attachment")).Return(nil).Once()
+
+		attachment, err := service.Upload(1, "alice", services.AttachmentUpload{
+			FileName: "notes.txt",
+			Size:     5,
+			Body:     strings.NewReader("notes"),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain; charset=utf-8", attachment.ContentType)
+	})
+
+	t.Run("rejects disallowed types", func(t *testing.T) {
+		repo := new(MockAttachmentRepository)
+		service, _ := newAttachmentService(t, repo, todoRepo)
+
+		_, err := service.Upload(1, "alice", services.AttachmentUpload{
+			FileName: "doc.pdf",
+			Size:     8,
+			Body:     strings.NewReader("%PDF-1.4"),
+		})
+
+		assert.Equal(t, services.ErrAttachmentTypeNotAllowed, err)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("rejects files above the limit even when the size is understated", func(t *testing.T) {
+		repo := new(MockAttachmentRepository)
+		service, _ := newAttachmentService(t, repo, todoRepo)
+
+		_, err := service.Upload(1, "alice", services.AttachmentUpload{
+			FileName: "big.txt",
+			Size:     10,
+			Body:     strings.NewReader(strings.Repeat("a", 65)),
+		})
+
+		assert.Equal(t, services.ErrAttachmentTooLarge, err)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("rejects empty files", func(t *testing.T) {
+		repo := new(MockAttachmentRepository)
+		service, _ := newAttachmentService(t, repo, todoRepo)
+
+		_, err := service.Upload(1, "alice", services.AttachmentUpload{FileName: "a.txt", Body: strings.NewReader("")})
+
+		assert.Equal(t, services.ErrAttachmentEmpty, err)
+	})
+}
+
+func TestAttachmentService_DeleteTodoRemovesBlobs(t *testing.T) {
+	todoRepo := new(MockTodoRepository)
+	repo := new(MockAttachmentRepository)
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	attachments := services.NewAttachmentService(repo, todoRepo, store, 64, nil)
+	todoService := services.NewTodoService(todoRepo, new(MockCategoryRepository), new(MockStatusRepository), attachments, false)
+
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "todos/1/blob", strings.NewReader("x"), 1, ""))
+
+	todoRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1, Version: 1}, nil).Once()
+	repo.On("StorageKeys", uint(1)).Return([]string{"todos/1/blob"}, nil).Once()
+	todoRepo.On("Delete", uint(1), uint(1)).Return(nil).Once()
+
+	require.NoError(t, todoService.Delete(1, nil))
+
+	_, err = store.Open(ctx, "todos/1/blob")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	repo.AssertExpectations(t)
+}
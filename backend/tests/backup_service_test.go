@@ -0,0 +1,386 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockBackupRepository is a mock implementation of BackupRepository. Created
+// records get IDs from 100 up and are kept in order. BlockerIDs answers from
+// links, standing for those in the database, and the created dependencies
+type MockBackupRepository struct {
+	mock.Mock
+	created []interface{}
+	nextID  uint
+	links   []models.TodoDependency
+}
+
+func (m *MockBackupRepository) Export() (*models.Backup, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Backup), args.Error(1)
+}
+
+func (m *MockBackupRepository) AttachmentCount() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockBackupRepository) Clear() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockBackupRepository) Create(record interface{}) (bool, error) {
+	args := m.Called(record)
+	if m.nextID == 0 {
+		m.nextID = 100
+	}
+	switch r := record.(type) {
+	case *models.Category:
+		r.ID = m.nextID
+	case *models.Status:
+		r.ID = m.nextID
+	case *models.Todo:
+		r.ID = m.nextID
+	case *models.Comment:
+		r.ID = m.nextID
+	case *models.ChecklistItem:
+		r.ID = m.nextID
+	case *models.TimeEntry:
+		r.ID = m.nextID
+	case *models.Template:
+		r.ID = m.nextID
+	}
+	m.nextID++
+	m.created = append(m.created, record)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBackupRepository) AddTag(todoID uint, name string) error {
+	args := m.Called(todoID, name)
+	return args.Error(0)
+}
+
+func (m *MockBackupRepository) BlockerIDs(todoIDs []uint) ([]uint, error) {
+	links := m.links
+	for _, dependency := range createdOf[models.TodoDependency](m) {
+		links = append(links, *dependency)
+	}
+	var ids []uint
+	for _, link := range links {
+		for _, id := range todoIDs {
+			if link.TodoID == id {
+				ids = append(ids, link.BlockerID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (m *MockBackupRepository) LockDependencies() error {
+	return nil
+}
+
+func (m *MockBackupRepository) Touch() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// Transaction runs fn directly against the mock since there is no database
+func (m *MockBackupRepository) Transaction(fn func(repo repository.BackupRepository) error) error {
+	return fn(m)
+}
+
+func createdOf[T any](m *MockBackupRepository) []*T {
+	var records []*T
+	for _, record := range m.created {
+		if r, ok := record.(*T); ok {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+func uintPtr(v uint) *uint { return &v }
+
+func sampleBackup() *models.Backup {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	return &models.Backup{
+		Format:     models.BackupFormat,
+		Version:    models.BackupVersion,
+		Categories: []models.Category{{ID: 1, Name: "Work"}, {ID: 2, Name: "Garden"}},
+		Statuses:   []models.Status{{ID: 1, Name: "To Do", Position: 0}, {ID: 2, Name: "Review", Position: 1}},
+		Todos: []models.BackupTodo{
+			{ID: 10, Title: "Existing", CategoryID: uintPtr(1), StatusID: uintPtr(1), CreatedAt: created},
+			{ID: 11, Title: "Plant tulips", CategoryID: uintPtr(2), StatusID: uintPtr(2), Tags: []string{"spring"}, Version: 3, CreatedAt: created},
+		},
+		Dependencies:   []models.TodoDependency{{TodoID: 11, BlockerID: 10}},
+		Comments:       []models.Comment{{ID: 5, TodoID: 10, Body: "old"}, {ID: 6, TodoID: 11, Body: "new"}},
+		ChecklistItems: []models.ChecklistItem{{ID: 7, TodoID: 11, Title: "Buy bulbs"}},
+		Templates: []models.Template{
+			{ID: 1, Name: "Weekly", Items: models.TemplateItems{{Title: "Review", CategoryID: uintPtr(2)}}},
+		},
+		SmartLists: []models.SmartList{
+			{ID: 1, Name: "Garden review", Filter: models.SmartListFilter{CategoryID: uintPtr(2), StatusID: uintPtr(2)}},
+			{ID: 2, Name: "Urgent", Filter: models.SmartListFilter{Priority: models.PriorityHigh}},
+		},
+	}
+}
+
+func TestBackupService_Export(t *testing.T) {
+	repo := new(MockBackupRepository)
+	service := services.NewBackupService(repo)
+	repo.On("Export").Return(&models.Backup{Categories: []models.Category{{ID: 1}}}, nil)
+
+	backup, err := service.Export()
+
+	require.NoError(t, err)
+	assert.Equal(t, models.BackupFormat, backup.Format)
+	assert.Equal(t, models.BackupVersion, backup.Version)
+	assert.False(t, backup.ExportedAt.IsZero())
+	assert.Len(t, backup.Categories, 1)
+}
+
+func TestBackupService_Restore(t *testing.T) {
+	t.Run("merge remaps IDs and skips duplicates", func(t *testing.T) {
+		repo := new(MockBackupRepository)
+		service := services.NewBackupService(repo)
+		existing := &models.Backup{
+			Categories: []models.Category{{ID: 3, Name: "work"}},
+			Statuses:   []models.Status{{ID: 4, Name: "To Do", Position: 0}, {ID: 5, Name: "Done", Position: 2}},
+			Todos:      []models.BackupTodo{{ID: 40, Title: "Existing", CreatedAt: sampleBackup().Todos[0].CreatedAt}},
+			SmartLists: []models.SmartList{{ID: 6, Name: "urgent"}},
+		}
+		repo.On("Export").Return(existing, nil).Once()
+		repo.On("Create", mock.Anything).Return(true, nil)
+		repo.On("AddTag", mock.Anything, "spring").Return(nil).Once()
+		repo.On("Touch").Return(nil).Once()
+
+		report, err := service.Restore(sampleBackup(), "")
+
+		require.NoError(t, err)
+		assert.Equal(t, models.RestoreMerge, report.Mode)
+		assert.Equal(t, models.RestoreCount{Created: 1, Skipped: 1}, report.Categories)
+		assert.Equal(t, models.RestoreCount{Created: 1, Skipped: 1}, report.Statuses)
+		assert.Equal(t, models.RestoreCount{Created: 1, Skipped: 1}, report.Todos)
+		assert.Equal(t, models.RestoreCount{Created: 1, Skipped: 1}, report.Comments)
+		assert.Equal(t, models.RestoreCount{Created: 1}, report.Dependencies)
+
+		categories := createdOf[models.Category](repo)
+		require.Len(t, categories, 1)
+		statuses := createdOf[models.Status](repo)
+		require.Len(t, statuses, 1)
+		assert.Equal(t, 3, statuses[0].Position, "appended after the existing columns")
+
+		todos := createdOf[models.Todo](repo)
+		require.Len(t, todos, 1)
+		assert.Equal(t, categories[0].ID, *todos[0].CategoryID)
+		assert.Equal(t, statuses[0].ID, *todos[0].StatusID)
+		assert.Equal(t, uint(3), todos[0].Version)
+		assert.Equal(t, sampleBackup().Todos[1].CreatedAt, todos[0].CreatedAt)
+		repo.AssertCalled(t, "AddTag", todos[0].ID, "spring")
+
+		dependencies := createdOf[models.TodoDependency](repo)
+		require.Len(t, dependencies, 1)
+		assert.Equal(t, models.TodoDependency{TodoID: todos[0].ID, BlockerID: 40}, *dependencies[0])
+
+		comments := createdOf[models.Comment](repo)
+		require.Len(t, comments, 1)
+		assert.Equal(t, "new", comments[0].Body)
+		assert.Equal(t, todos[0].ID, comments[0].TodoID)
+		assert.Equal(t, todos[0].ID, createdOf[models.ChecklistItem](repo)[0].TodoID)
+
+		templates := createdOf[models.Template](repo)
+		require.Len(t, templates, 1)
+		assert.Equal(t, categories[0].ID, *templates[0].Items[0].CategoryID)
+
+		assert.Equal(t, models.RestoreCount{Created: 1, Skipped: 1}, report.SmartLists)
+		lists := createdOf[models.SmartList](repo)
+		require.Len(t, lists, 1)
+		assert.Equal(t, categories[0].ID, *lists[0].Filter.CategoryID)
+		assert.Equal(t, statuses[0].ID, *lists[0].Filter.StatusID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("merge skips links that would close a loop", func(t *testing.T) {
+		repo := new(MockBackupRepository)
+		service := services.NewBackupService(repo)
+		created := sampleBackup().Todos[0].CreatedAt
+		// In the database Plant tulips already blocks Existing
+		repo.links = []models.TodoDependency{{TodoID: 40, BlockerID: 41}}
+		repo.On("Export").Return(&models.Backup{
+			Todos: []models.BackupTodo{
+				{ID: 40, Title: "Existing", CreatedAt: created},
+				{ID: 41, Title: "Plant tulips", CreatedAt: created},
+			},
+		}, nil).Once()
+		repo.On("Create", mock.Anything).Return(true, nil)
+		repo.On("Touch").Return(nil).Once()
+		backup := sampleBackup()
+		backup.Comments, backup.ChecklistItems = nil, nil
+
+		report, err := service.Restore(backup, models.RestoreMerge)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.RestoreCount{Skipped: 1}, report.Dependencies)
+		assert.Empty(t, createdOf[models.TodoDependency](repo))
+	})
+
+	t.Run("merge revalidates custom fields against the existing category", func(t *testing.T) {
+		repo := new(MockBackupRepository)
+		service := services.NewBackupService(repo)
+		repo.On("Export").Return(&models.Backup{
+			Categories: []models.Category{{ID: 3, Name: "garden", CustomFields: models.CustomFieldDefinitions{
+				{Key: "beds", Name: "Beds", Type: models.CustomFieldNumber},
+				{Key: "soil", Name: "Soil", Type: models.CustomFieldText},
+			}}},
+		}, nil).Once()
+		repo.On("Create", mock.Anything).Return(true, nil)
+		repo.On("AddTag", mock.Anything, "spring").Return(nil).Once()
+		repo.On("Touch").Return(nil).Once()
+		backup := sampleBackup()
+		backup.Categories[1].CustomFields = models.CustomFieldDefinitions{
+			{Key: "beds", Name: "Beds", Type: models.CustomFieldText},
+			{Key: "soil", Name: "Soil", Type: models.CustomFieldText},
+			{Key: "shade", Name: "Shade", Type: models.CustomFieldText},
+		}
+		backup.Todos[1].CustomFields = models.CustomFieldValues{"beds": "two", "soil": "clay", "shade": "partial"}
+
+		_, err := service.Restore(backup, models.RestoreMerge)
+
+		require.NoError(t, err)
+		todos := createdOf[models.Todo](repo)
+		require.Len(t, todos, 2)
+		assert.Equal(t, uint(3), *todos[1].CategoryID)
+		assert.Equal(t, models.CustomFieldValues{"soil": "clay"}, todos[1].CustomFields)
+	})
+
+	t.Run("replace clears existing data", func(t *testing.T) {
+		repo := new(MockBackupRepository)
+		service := services.NewBackupService(repo)
+
+		repo.On("AttachmentCount").Return(int64(0), nil).Once()
+		repo.On("Clear").Return(nil).Once()
+		repo.On("Export").Return(&models.Backup{}, nil).Once()
+		repo.On("Create", mock.Anything).Return(true, nil)
+		repo.On("AddTag", mock.Anything, "spring").Return(nil).Once()
+		repo.On("Touch").Return(nil).Once()
+
+		report, err := service.Restore(sampleBackup(), models.RestoreReplace)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.RestoreCount{Created: 2}, report.Todos)
+		assert.Equal(t, models.RestoreCount{Created: 2}, report.Comments)
+		statuses := createdOf[models.Status](repo)
+		assert.Equal(t, 0, statuses[0].Position)
+		assert.Equal(t, 1, statuses[1].Position)
+		todos := createdOf[models.Todo](repo)
+		assert.Equal(t, models.PriorityMedium, todos[0].Priority)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("replace is refused while attachments exist", func(t *testing.T) {
+		repo := new(MockBackupRepository)
+		service := services.NewBackupService(repo)
+		repo.On("AttachmentCount").Return(int64(2), nil).Once()
+
+		_, err := service.Restore(sampleBackup(), models.RestoreReplace)
+
+		assert.ErrorIs(t, err, services.ErrRestoreAttachments)
+		repo.AssertNotCalled(t, "Clear")
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("counts records the database skipped", func(t *testing.T) {
+		repo := new(MockBackupRepository)
+		service := services.NewBackupService(repo)
+		backup := &models.Backup{
+			Format:  models.BackupFormat,
+			Version: models.BackupVersion,
+			Todos:   []models.BackupTodo{{ID: 1, Title: "Timed"}},
+			TimeEntries: []models.TimeEntry{
+				{ID: 1, TodoID: 1, UserName: "alice"},
+			},
+		}
+		repo.On("Export").Return(&models.Backup{}, nil).Once()
+		repo.On("Create", mock.AnythingOfType("*models.Todo")).Return(true, nil).Once()
+		repo.On("Create", mock.AnythingOfType("*models.TimeEntry")).Return(false, nil).Once()
+		repo.On("Touch").Return(nil).Once()
+
+		report, err := service.Restore(backup, models.RestoreMerge)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.RestoreCount{Skipped: 1}, report.TimeEntries)
+	})
+
+	t.Run("rejects unknown formats and modes", func(t *testing.T) {
+		service := services.NewBackupService(new(MockBackupRepository))
+
+		_, err := service.Restore(&models.Backup{Format: "other", Version: 1}, models.RestoreMerge)
+		assert.ErrorIs(t, err, services.ErrUnsupportedBackup)
+
+		_, err = service.Restore(&models.Backup{Format: models.BackupFormat, Version: models.BackupVersion + 1}, models.RestoreMerge)
+		assert.ErrorIs(t, err, services.ErrUnsupportedBackup)
+
+		_, err = service.Restore(sampleBackup(), "overwrite")
+		assert.ErrorIs(t, err, services.ErrInvalidRestoreMode)
+	})
+
+	t.Run("rejects duplicate IDs and dangling references", func(t *testing.T) {
+		service := services.NewBackupService(new(MockBackupRepository))
+		backup := sampleBackup()
+		backup.Categories = append(backup.Categories, models.Category{ID: 1, Name: "Again"})
+		backup.Todos[1].StatusID = uintPtr(9)
+		backup.Dependencies = append(backup.Dependencies, models.TodoDependency{TodoID: 11, BlockerID: 99})
+		backup.Comments[0].TodoID = 99
+		backup.SmartLists[1].Filter.StatusID = uintPtr(9)
+
+		_, err := service.Restore(backup, models.RestoreMerge)
+
+		assert.ElementsMatch(t, []string{
+			"categories[2].id:unique",
+			"todos[1].status_id:exists",
+			"dependencies[1]:exists",
+			"comments[0].todo_id:exists",
+			"smart_lists[1].filter.status_id:exists",
+		}, fieldRules(t, err))
+	})
+	t.Run("validates record fields like their create endpoints", func(t *testing.T) {
+		service := services.NewBackupService(new(MockBackupRepository))
+		backup := sampleBackup()
+		backup.Categories[0].Color = "blue"
+		backup.Todos[0].Title = strings.Repeat("x", 256)
+		backup.Todos[0].Priority = "urgent"
+		backup.Todos[1].Position = strings.Repeat("V", 256)
+		backup.Todos[1].CustomFields = models.CustomFieldValues{"client": "Acme"}
+		backup.Comments[1].Body = "  "
+		backup.ChecklistItems[0].Position = "a-b"
+		backup.SmartLists[0].Name = ""
+		backup.SmartLists[1].Filter.TimeZone = "Mars/Olympus"
+
+		_, err := service.Restore(backup, models.RestoreMerge)
+
+		assert.ElementsMatch(t, []string{
+			"categories[0].color:hexcolor",
+			"todos[0].title:max",
+			"todos[0].priority:oneof",
+			"todos[1].position:rank",
+			"todos[1].custom_fields.client:defined",
+			"comments[1].body:required",
+			"checklist_items[0].position:rank",
+			"smart_lists[0].name:required",
+			"smart_lists[1].filter.time_zone:timezone",
+		}, fieldRules(t, err))
+	})
+}
@@ -0,0 +1,351 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/ical"
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCalendarResourceRepository is a mock implementation of
+// CalendarResourceRepository
+type MockCalendarResourceRepository struct {
+	mock.Mock
+}
+
+func (m *MockCalendarResourceRepository) Save(resource *models.CalendarResource) error {
+	args := m.Called(resource)
+	return args.Error(0)
+}
+
+func (m *MockCalendarResourceRepository) GetByName(name string) (*models.CalendarResource, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CalendarResource), args.Error(1)
+}
+
+func (m *MockCalendarResourceRepository) GetByUID(uid string) (*models.CalendarResource, error) {
+	args := m.Called(uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CalendarResource), args.Error(1)
+}
+
+func (m *MockCalendarResourceRepository) GetByTodoIDs(ids []uint) ([]models.CalendarResource, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.CalendarResource), args.Error(1)
+}
+
+var errRecordNotFound = errors.New("record not found")
+
+type caldavFixture struct {
+	service      services.CalDAVService
+	todoRepo     *MockTodoRepository
+	categoryRepo *MockCategoryRepository
+	resources    *MockCalendarResourceRepository
+}
+
+// newCalDAVFixture serves category 3 as the only calendar
+func newCalDAVFixture() *caldavFixture {
+	f := &caldavFixture{
+		todoRepo:     new(MockTodoRepository),
+		categoryRepo: new(MockCategoryRepository),
+		resources:    new(MockCalendarResourceRepository),
+	}
+	f.categoryRepo.On("GetByID", uint(3)).Return(&models.Category{ID: 3, Name: "Work"}, nil).Maybe()
+	f.categoryRepo.On("GetByID", mock.Anything).Return(nil, errRecordNotFound).Maybe()
+	todos := newTodoService(f.todoRepo, f.categoryRepo)
+	f.service = services.NewCalDAVService(f.categoryRepo, f.resources, todos)
+	return f
+}
+
+func vcalendar(lines ...string) []byte {
+	return []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n")
+}
+
+func TestCalDAVService_Objects(t *testing.T) {
+	f := newCalDAVFixture()
+	categoryID := uint(3)
+	early := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)
+	f.todoRepo.On("Each", mock.MatchedBy(func(filter models.TodoFilter) bool {
+		return filter.CategoryID != nil && *filter.CategoryID == 3 && filter.Completed == nil
+	})).Return([]models.Todo{
+		{ID: 1, Title: "Early", DueDate: &early, CategoryID: &categoryID},
+		{ID: 2, Title: "Late", DueDate: &late, CategoryID: &categoryID},
+		{ID: 4, Title: "Someday", CategoryID: &categoryID},
+	}, nil)
+	f.resources.On("GetByTodoIDs", []uint{2, 4}).Return([]models.CalendarResource{
+		{TodoID: 4, Name: "phone-made.ics", UID: "abc-123"},
+	}, nil).Once()
+
+	from := time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+	objects, err := f.service.Objects(3, models.CalDAVQuery{DueFrom: &from})
+
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "todo-2.ics", objects[0].Name)
+	assert.Equal(t, services.TodoUID(2), objects[0].UID)
+	assert.Equal(t, "phone-made.ics", objects[1].Name)
+	assert.Equal(t, "abc-123", objects[1].UID)
+
+	_, err = f.service.Objects(9, models.CalDAVQuery{})
+	assert.ErrorIs(t, err, services.ErrCalDAVCalendarNotFound)
+}
+
+func TestCalDAVService_Object(t *testing.T) {
+	categoryID, otherID := uint(3), uint(5)
+
+	t.Run("finds todos by resource name and by default name", func(t *testing.T) {
+		f := newCalDAVFixture()
+		f.resources.On("GetByName", "phone-made.ics").Return(&models.CalendarResource{TodoID: 4, Name: "phone-made.ics", UID: "abc-123"}, nil).Once()
+		f.todoRepo.On("GetByID", uint(4)).Return(&models.Todo{ID: 4, CategoryID: &categoryID}, nil).Once()
+		object, err := f.service.Object(3, "phone-made.ics")
+		require.NoError(t, err)
+		assert.Equal(t, "abc-123", object.UID)
+
+		f.resources.On("GetByName", "todo-7.ics").Return(nil, errRecordNotFound).Once()
+		f.resources.On("GetByTodoIDs", []uint{7}).Return([]models.CalendarResource{}, nil).Once()
+		f.todoRepo.On("GetByID", uint(7)).Return(&models.Todo{ID: 7, CategoryID: &categoryID}, nil).Once()
+		object, err = f.service.Object(3, "todo-7.ics")
+		require.NoError(t, err)
+		assert.Equal(t, "todo-7.ics", object.Name)
+		assert.Equal(t, services.TodoUID(7), object.UID)
+	})
+
+	t.Run("renamed todos are not found under their default name", func(t *testing.T) {
+		f := newCalDAVFixture()
+		f.resources.On("GetByName", "todo-4.ics").Return(nil, errRecordNotFound).Once()
+		f.resources.On("GetByTodoIDs", []uint{4}).Return([]models.CalendarResource{{TodoID: 4, Name: "phone-made.ics"}}, nil).Once()
+
+		_, err := f.service.Object(3, "todo-4.ics")
+		assert.ErrorIs(t, err, services.ErrCalDAVObjectNotFound)
+	})
+
+	t.Run("todos of other categories are not found", func(t *testing.T) {
+		f := newCalDAVFixture()
+		f.resources.On("GetByName", "todo-8.ics").Return(nil, errRecordNotFound).Once()
+		f.resources.On("GetByTodoIDs", []uint{8}).Return([]models.CalendarResource{}, nil).Once()
+		f.todoRepo.On("GetByID", uint(8)).Return(&models.Todo{ID: 8, CategoryID: &otherID}, nil).Once()
+
+		_, err := f.service.Object(3, "todo-8.ics")
+		assert.ErrorIs(t, err, services.ErrCalDAVObjectNotFound)
+
+		f.resources.On("GetByName", "notes.txt").Return(nil, errRecordNotFound).Once()
+		_, err = f.service.Object(3, "notes.txt")
+		assert.ErrorIs(t, err, services.ErrCalDAVObjectNotFound)
+	})
+}
+
+func TestCalDAVService_Put(t *testing.T) {
+	categoryID := uint(3)
+	body := vcalendar(
+		"BEGIN:VTODO",
+		"UID:abc-123",
+		"SUMMARY:Call the bank",
+		"DUE;VALUE=DATE:20261020",
+		"PRIORITY:1",
+		"CATEGORIES:Errands,Phone",
+		"END:VTODO",
+	)
+
+	t.Run("creates a todo in the calendar's category", func(t *testing.T) {
+		f := newCalDAVFixture()
+		f.resources.On("GetByName", "abc-123.ics").Return(nil, errRecordNotFound).Once()
+		f.resources.On("GetByUID", "abc-123").Return(nil, errRecordNotFound).Once()
+		f.todoRepo.On("MaxPosition").Return("", nil).Once()
+		f.todoRepo.On("LockPositions").Return(nil)
+		f.todoRepo.On("Create", mock.MatchedBy(func(todo *models.Todo) bool {
+			return todo.Title == "Call the bank" && todo.Priority == models.PriorityHigh &&
+				todo.CategoryID != nil && *todo.CategoryID == 3 && todo.DueDate != nil
+		})).Return(nil).Once()
+		f.todoRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1, Title: "Call the bank", CategoryID: &categoryID, Version: 1}, nil).Once()
+		f.resources.On("Save", &models.CalendarResource{TodoID: 1, Name: "abc-123.ics", UID: "abc-123"}).Return(nil).Once()
+
+		object, created, err := f.service.Put(3, "abc-123.ics", body, nil, true)
+
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "abc-123", object.UID)
+		// CATEGORIES do not become tags
+		f.todoRepo.AssertNotCalled(t, "AddTag", mock.Anything, mock.Anything)
+		f.todoRepo.AssertExpectations(t)
+		f.resources.AssertExpectations(t)
+	})
+
+	t.Run("removes the todo when its resource cannot be saved", func(t *testing.T) {
+		f := newCalDAVFixture()
+		f.resources.On("GetByName", "abc-123.ics").Return(nil, errRecordNotFound).Once()
+		f.resources.On("GetByUID", "abc-123").Return(nil, errRecordNotFound).Once()
+		f.todoRepo.On("MaxPosition").Return("", nil).Once()
+		f.todoRepo.On("LockPositions").Return(nil)
+		f.todoRepo.On("Create", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+		f.todoRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1, CategoryID: &categoryID, Version: 1}, nil).Twice()
+		f.resources.On("Save", mock.Anything).Return(errors.New("duplicate key")).Once()
+		f.todoRepo.On("Delete", uint(1), uint(1)).Return(nil).Once()
+
+		_, _, err := f.service.Put(3, "abc-123.ics", body, nil, false)
+
+		assert.Error(t, err)
+		f.todoRepo.AssertExpectations(t)
+	})
+
+	t.Run("replaces every field of an existing todo", func(t *testing.T) {
+		f := newCalDAVFixture()
+		due := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+		existing := &models.Todo{
+			ID: 4, Title: "Old", Description: "Details", Priority: models.PriorityLow,
+			DueDate: &due, Recurrence: "FREQ=DAILY", CategoryID: &categoryID, Version: 2,
+		}
+		f.resources.On("GetByName", "abc-123.ics").Return(&models.CalendarResource{TodoID: 4, Name: "abc-123.ics", UID: "abc-123"}, nil).Once()
+		f.todoRepo.On("GetByID", uint(4)).Return(existing, nil)
+		f.todoRepo.On("Update", mock.MatchedBy(func(todo *models.Todo) bool {
+			return todo.Title == "Renamed" && todo.Description == "" && todo.DueDate == nil && todo.Recurrence == "" &&
+				todo.Priority == models.PriorityMedium && todo.Completed &&
+				todo.CategoryID != nil && *todo.CategoryID == 3
+		})).Return(nil).Once()
+
+		version := uint(2)
+		object, created, err := f.service.Put(3, "abc-123.ics", vcalendar(
+			"BEGIN:VTODO",
+			"UID:abc-123",
+			"SUMMARY:Renamed",
+			"STATUS:COMPLETED",
+			"END:VTODO",
+		), &version, false)
+
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, "abc-123", object.UID)
+		f.todoRepo.AssertExpectations(t)
+	})
+
+	t.Run("keeps the recurrence of an object written back unchanged", func(t *testing.T) {
+		f := newCalDAVFixture()
+		due := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+		existing := &models.Todo{
+			ID: 4, Title: "Water plants", Priority: models.PriorityMedium, DueDate: &due,
+			Recurrence: "FREQ=WEEKLY;INTERVAL=2", CategoryID: &categoryID, Version: 2,
+		}
+		f.resources.On("GetByName", "abc-123.ics").Return(&models.CalendarResource{TodoID: 4, Name: "abc-123.ics", UID: "abc-123"}, nil).Once()
+		f.todoRepo.On("GetByID", uint(4)).Return(existing, nil)
+		f.todoRepo.On("Update", mock.MatchedBy(func(todo *models.Todo) bool {
+			return todo.Recurrence == "FREQ=WEEKLY;INTERVAL=2" && todo.DueDate.Equal(due)
+		})).Return(nil).Once()
+
+		var buf bytes.Buffer
+		object := &models.CalDAVObject{Name: "abc-123.ics", UID: "abc-123", Todo: existing}
+		require.NoError(t, ical.NewEncoder(&buf).Encode(services.CalDAVData(object)))
+		version := uint(2)
+		_, _, err := f.service.Put(3, "abc-123.ics", buf.Bytes(), &version, false)
+
+		require.NoError(t, err)
+		f.todoRepo.AssertExpectations(t)
+	})
+
+	t.Run("preconditions", func(t *testing.T) {
+		f := newCalDAVFixture()
+		f.resources.On("GetByName", "abc-123.ics").Return(&models.CalendarResource{TodoID: 4, Name: "abc-123.ics", UID: "abc-123"}, nil)
+		f.todoRepo.On("GetByID", uint(4)).Return(&models.Todo{ID: 4, CategoryID: &categoryID, Version: 3}, nil)
+		f.resources.On("GetByName", "new.ics").Return(nil, errRecordNotFound)
+
+		_, _, err := f.service.Put(3, "abc-123.ics", body, nil, true)
+		assert.ErrorIs(t, err, services.ErrCalDAVObjectExists)
+
+		stale := uint(2)
+		_, _, err = f.service.Put(3, "abc-123.ics", body, &stale, false)
+		assert.ErrorIs(t, err, services.ErrVersionConflict)
+
+		_, _, err = f.service.Put(3, "new.ics", body, &stale, false)
+		assert.ErrorIs(t, err, services.ErrVersionConflict)
+		f.todoRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("refuses UIDs and names already in use", func(t *testing.T) {
+		f := newCalDAVFixture()
+		f.resources.On("GetByName", "copy.ics").Return(nil, errRecordNotFound)
+		f.resources.On("GetByUID", "abc-123").Return(&models.CalendarResource{TodoID: 4}, nil).Once()
+		f.todoRepo.On("GetByID", uint(4)).Return(&models.Todo{ID: 4}, nil).Once()
+
+		_, _, err := f.service.Put(3, "copy.ics", body, nil, false)
+		assert.ErrorIs(t, err, services.ErrCalDAVUIDConflict)
+
+		f.resources.On("GetByUID", services.TodoUID(6)).Return(nil, errRecordNotFound).Once()
+		f.todoRepo.On("GetByID", uint(6)).Return(&models.Todo{ID: 6}, nil).Once()
+		_, _, err = f.service.Put(3, "copy.ics", vcalendar(
+			"BEGIN:VTODO",
+			"UID:"+services.TodoUID(6),
+			"SUMMARY:Copied from the feed",
+			"END:VTODO",
+		), nil, false)
+		assert.ErrorIs(t, err, services.ErrCalDAVUIDConflict)
+
+		f.resources.On("GetByName", "todo-99.ics").Return(nil, errRecordNotFound).Once()
+		f.resources.On("GetByTodoIDs", []uint{99}).Return([]models.CalendarResource{}, nil).Once()
+		f.todoRepo.On("GetByID", uint(99)).Return(nil, errRecordNotFound).Once()
+		_, _, err = f.service.Put(3, "todo-99.ics", body, nil, false)
+		assert.ErrorIs(t, err, services.ErrCalDAVReservedName)
+	})
+
+	t.Run("rejects bodies without exactly one valid VTODO", func(t *testing.T) {
+		f := newCalDAVFixture()
+
+		_, _, err := f.service.Put(3, "x.ics", []byte("not a calendar"), nil, false)
+		assert.ErrorIs(t, err, services.ErrCalDAVInvalidData)
+
+		_, _, err = f.service.Put(3, "x.ics", vcalendar("BEGIN:VEVENT", "UID:e", "END:VEVENT"), nil, false)
+		assert.ErrorIs(t, err, services.ErrCalDAVInvalidData)
+
+		_, _, err = f.service.Put(3, "x.ics", vcalendar("BEGIN:VTODO", "PRIORITY:12", "END:VTODO"), nil, false)
+		assert.ElementsMatch(t, []string{"uid:required", "priority:format"}, fieldRules(t, err))
+
+		_, _, err = f.service.Put(9, "x.ics", body, nil, false)
+		assert.ErrorIs(t, err, services.ErrCalDAVCalendarNotFound)
+	})
+}
+
+func TestCalDAVService_Delete(t *testing.T) {
+	f := newCalDAVFixture()
+	categoryID := uint(3)
+	f.resources.On("GetByName", "todo-7.ics").Return(nil, errRecordNotFound)
+	f.resources.On("GetByTodoIDs", []uint{7}).Return([]models.CalendarResource{}, nil)
+	f.todoRepo.On("GetByID", uint(7)).Return(&models.Todo{ID: 7, CategoryID: &categoryID, Version: 4}, nil)
+	f.todoRepo.On("Delete", uint(7), uint(4)).Return(nil).Once()
+
+	stale := uint(3)
+	assert.ErrorIs(t, f.service.Delete(3, "todo-7.ics", &stale), services.ErrVersionConflict)
+
+	current := uint(4)
+	assert.NoError(t, f.service.Delete(3, "todo-7.ics", &current))
+	f.todoRepo.AssertExpectations(t)
+}
+
+func TestCalDAVData(t *testing.T) {
+	object := &models.CalDAVObject{
+		Name: "abc-123.ics",
+		UID:  "abc-123",
+		Todo: &models.Todo{ID: 4, Title: "Call the bank", Version: 1},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ical.NewEncoder(&buf).Encode(services.CalDAVData(object)))
+
+	calendar, err := ical.Decode(&buf)
+	require.NoError(t, err)
+	vtodos := calendar.Components("VTODO")
+	require.Len(t, vtodos, 1)
+	assert.Equal(t, "abc-123", vtodos[0].Text("UID"))
+	assert.Equal(t, "Call the bank", vtodos[0].Text("SUMMARY"))
+	assert.Len(t, vtodos[0].All("UID"), 1)
+}
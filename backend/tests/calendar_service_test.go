@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCalendarTokenRepository is a mock implementation of
+// CalendarTokenRepository
+type MockCalendarTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockCalendarTokenRepository) Save(token *models.CalendarToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockCalendarTokenRepository) GetByHash(hash string) (*models.CalendarToken, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CalendarToken), args.Error(1)
+}
+
+func (m *MockCalendarTokenRepository) DeleteByUser(user string) (bool, error) {
+	args := m.Called(user)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestCalendarService_Tokens(t *testing.T) {
+	t.Run("stores only a hash and authenticates with the token", func(t *testing.T) {
+		repo := new(MockCalendarTokenRepository)
+		service := services.NewCalendarService(repo)
+		var saved *models.CalendarToken
+		repo.On("Save", mock.AnythingOfType("*models.CalendarToken")).Run(func(args mock.Arguments) {
+			saved = args.Get(0).(*models.CalendarToken)
+		}).Return(nil).Once()
+
+		token, err := service.CreateToken("alice")
+
+		require.NoError(t, err)
+		assert.Len(t, token, 43)
+		assert.Equal(t, "alice", saved.UserName)
+		assert.Len(t, saved.TokenHash, 64)
+		assert.NotContains(t, saved.TokenHash, token)
+
+		repo.On("GetByHash", saved.TokenHash).Return(saved, nil).Once()
+		user, err := service.Authenticate(token)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", user)
+	})
+
+	t.Run("rejects unknown and empty tokens", func(t *testing.T) {
+		repo := new(MockCalendarTokenRepository)
+		service := services.NewCalendarService(repo)
+		repo.On("GetByHash", mock.Anything).Return(nil, errors.New("record not found")).Once()
+
+		_, err := service.Authenticate("guess")
+		assert.ErrorIs(t, err, services.ErrCalendarTokenInvalid)
+		_, err = service.Authenticate("")
+		assert.ErrorIs(t, err, services.ErrCalendarTokenInvalid)
+		repo.AssertNumberOfCalls(t, "GetByHash", 1)
+	})
+
+	t.Run("revoking without a token is not found", func(t *testing.T) {
+		repo := new(MockCalendarTokenRepository)
+		service := services.NewCalendarService(repo)
+		repo.On("DeleteByUser", "bob").Return(false, nil).Once()
+
+		assert.ErrorIs(t, service.RevokeToken("bob"), services.ErrCalendarTokenNotFound)
+	})
+}
+
+func TestTodoVTODO(t *testing.T) {
+	updated := time.Date(2026, 10, 2, 9, 15, 0, 0, time.UTC)
+	todo := &models.Todo{
+		ID:          42,
+		Title:       "Renew passport",
+		Description: "Bring photos, old passport",
+		Priority:    models.PriorityHigh,
+		DueDate:     date(2026, 10, 20),
+		Recurrence:  "FREQ=YEARLY;INTERVAL=10",
+		Category:    &models.Category{Name: "Errands"},
+		Completed:   true,
+		Version:     3,
+		CreatedAt:   updated.AddDate(0, 0, -1),
+		UpdatedAt:   updated,
+	}
+
+	vtodo := services.TodoVTODO(todo)
+
+	assert.Equal(t, "todo-42@industrix-todo", vtodo.Text("UID"))
+	assert.Equal(t, "Renew passport", vtodo.Text("SUMMARY"))
+	assert.Equal(t, "Bring photos, old passport", vtodo.Text("DESCRIPTION"))
+	due, _ := vtodo.Get("DUE")
+	assert.Equal(t, "20261020", due.Value)
+	assert.Equal(t, "DATE", due.Params["VALUE"])
+	assert.Equal(t, "FREQ=YEARLY;INTERVAL=10", vtodo.Text("RRULE"))
+	assert.Equal(t, "1", vtodo.Text("PRIORITY"))
+	assert.Equal(t, "Errands", vtodo.Text("CATEGORIES"))
+	assert.Equal(t, "COMPLETED", vtodo.Text("STATUS"))
+	assert.Equal(t, "20261002T091500Z", vtodo.Text("COMPLETED"))
+	assert.Equal(t, "2", vtodo.Text("SEQUENCE"))
+
+	timed := *todo
+	dueAt := time.Date(2026, 10, 20, 17, 0, 0, 0, time.UTC)
+	timed.DueDate = &dueAt
+	event := services.TodoVEvent(&timed)
+	start, _ := event.Get("DTSTART")
+	assert.Equal(t, "20261020T170000Z", start.Value)
+	assert.Empty(t, start.Params)
+}
+
+func TestTodoService_ImportCalendar(t *testing.T) {
+	t.Run("imports VTODOs and reports them by line", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		categoryRepo := new(MockCategoryRepository)
+		service := newTodoService(mockRepo, categoryRepo)
+
+		errands := &models.Category{ID: 3, Name: "Errands"}
+		mockRepo.On("FindOrCreateCategory", "Errands").Return(errands, false, nil).Once()
+		categoryRepo.On("GetByID", uint(3)).Return(errands, nil)
+		mockRepo.On("MaxPosition").Return("", nil)
+		mockRepo.On("LockPositions").Return(nil)
+		var created []models.Todo
+		mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Run(func(args mock.Arguments) {
+			created = append(created, *args.Get(0).(*models.Todo))
+		}).Return(nil)
+		mockRepo.On("AddTag", uint(1), "paperwork").Return(nil).Once()
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+		data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+			"BEGIN:VTODO\r\nUID:a\r\nSUMMARY:Renew passport\r\nDUE;VALUE=DATE:20261020\r\nRRULE:FREQ=YEARLY;INTERVAL=10\r\nPRIORITY:2\r\nCATEGORIES:Errands,paperwork\r\nEND:VTODO\r\n" +
+			"BEGIN:VEVENT\r\nSUMMARY:Ignored\r\nEND:VEVENT\r\n" +
+			"BEGIN:VTODO\r\nSUMMARY:Done\r\nSTATUS:COMPLETED\r\nDUE;TZID=Asia/Jakarta:20261021T090000\r\nEND:VTODO\r\n" +
+			"END:VCALENDAR\r\n"
+
+		report, err := service.ImportCalendar(models.CalendarImportRequest{Data: []byte(data)})
+
+		require.NoError(t, err)
+		assert.True(t, report.Committed)
+		require.Len(t, report.Rows, 2)
+		assert.Equal(t, 3, report.Rows[0].Row)
+		assert.Equal(t, 14, report.Rows[1].Row)
+		require.Len(t, created, 2)
+		assert.Equal(t, models.PriorityHigh, created[0].Priority)
+		assert.Equal(t, uint(3), *created[0].CategoryID)
+		assert.True(t, date(2026, 10, 20).Equal(*created[0].DueDate))
+		assert.Equal(t, "FREQ=YEARLY;INTERVAL=10", created[0].Recurrence)
+		assert.Empty(t, created[1].Recurrence)
+		assert.True(t, created[1].Completed)
+		assert.True(t, time.Date(2026, 10, 21, 2, 0, 0, 0, time.UTC).Equal(*created[1].DueDate))
+	})
+
+	t.Run("reports invalid values", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+		mockRepo.On("MaxPosition").Return("", nil)
+		mockRepo.On("LockPositions").Return(nil)
+		mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Return(nil)
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+		data := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:Bad\r\nDUE:soon\r\nPRIORITY:high\r\nRRULE:FREQ=WEEKLY;BYDAY=MO\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+		report, err := service.ImportCalendar(models.CalendarImportRequest{Data: []byte(data)})
+
+		require.NoError(t, err)
+		assert.False(t, report.Committed)
+		assert.ElementsMatch(t, []models.ImportError{
+			{Field: "due_date", Message: services.ErrCalendarInvalidDue.Error()},
+			{Field: "priority", Message: services.ErrCalendarInvalidPriority.Error()},
+			{Field: "recurrence", Message: services.ErrInvalidRecurrence.Error()},
+		}, report.Rows[0].Errors)
+	})
+
+	t.Run("rejects files that are not calendars", func(t *testing.T) {
+		service := newTodoService(new(MockTodoRepository), new(MockCategoryRepository))
+
+		_, err := service.ImportCalendar(models.CalendarImportRequest{Data: []byte("title\nBuy milk\n")})
+		assert.Equal(t, []string{"file:format"}, fieldRules(t, err))
+		assert.ErrorIs(t, err, services.ErrCalendarInvalid)
+
+		_, err = service.ImportCalendar(models.CalendarImportRequest{Data: []byte("BEGIN:VCARD\r\nEND:VCARD\r\n")})
+		assert.Equal(t, []string{"file:format"}, fieldRules(t, err))
+
+		_, err = service.ImportCalendar(models.CalendarImportRequest{Data: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")})
+		assert.Equal(t, []string{"file:required"}, fieldRules(t, err))
+	})
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -31,6 +32,11 @@ func (m *MockCategoryRepository) GetAll() ([]models.Category, error) {
 	return args.Get(0).([]models.Category), args.Error(1)
 }
 
+func (m *MockCategoryRepository) ListState() (models.ListState, error) {
+	args := m.Called()
+	return args.Get(0).(models.ListState), args.Error(1)
+}
+
 func (m *MockCategoryRepository) GetByID(id uint) (*models.Category, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -44,8 +50,8 @@ func (m *MockCategoryRepository) Update(category *models.Category) error {
 	return args.Error(0)
 }
 
-func (m *MockCategoryRepository) Delete(id uint) error {
-	args := m.Called(id)
+func (m *MockCategoryRepository) Delete(id, version uint) error {
+	args := m.Called(id, version)
 	return args.Error(0)
 }
 
@@ -94,7 +100,19 @@ func TestCategoryService_Create(t *testing.T) {
 
 		assert.Error(t, err)
 		assert.Nil(t, category)
-		assert.Equal(t, services.ErrCategoryNameRequired, err)
+		assert.ErrorIs(t, err, services.ErrCategoryNameRequired)
+	})
+
+	t.Run("invalid color error", func(t *testing.T) {
+		req := models.CreateCategoryRequest{
+			Name:  "Work",
+			Color: "blue",
+		}
+
+		category, err := service.Create(req)
+
+		assert.Nil(t, category)
+		assert.ErrorIs(t, err, services.ErrInvalidColor)
 	})
 }
 
@@ -168,7 +186,7 @@ func TestCategoryService_Update(t *testing.T) {
 		mockRepo.On("GetByID", uint(1)).Return(existingCategory, nil).Once()
 		mockRepo.On("Update", mock.AnythingOfType("*models.Category")).Return(nil).Once()
 
-		category, err := service.Update(1, req)
+		category, err := service.Update(1, req, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, category)
@@ -177,11 +195,12 @@ func TestCategoryService_Update(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
-	t.Run("partial update - name only", func(t *testing.T) {
+	t.Run("omitted fields are reset", func(t *testing.T) {
 		existingCategory := &models.Category{
-			ID:    2,
-			Name:  "Old Name",
-			Color: "#000000",
+			ID:           2,
+			Name:         "Old Name",
+			Color:        "#000000",
+			CustomFields: models.CustomFieldDefinitions{{Key: "client", Type: models.CustomFieldText}},
 		}
 
 		req := models.UpdateCategoryRequest{
@@ -191,18 +210,28 @@ func TestCategoryService_Update(t *testing.T) {
 		mockRepo.On("GetByID", uint(2)).Return(existingCategory, nil).Once()
 		mockRepo.On("Update", mock.AnythingOfType("*models.Category")).Return(nil).Once()
 
-		category, err := service.Update(2, req)
+		category, err := service.Update(2, req, nil)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "New Name", category.Name)
-		assert.Equal(t, "#000000", category.Color)
+		assert.Equal(t, "#3B82F6", category.Color)
+		assert.Empty(t, category.CustomFields)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("name is required", func(t *testing.T) {
+		mockRepo.On("GetByID", uint(3)).Return(&models.Category{ID: 3, Name: "Old Name"}, nil).Once()
+
+		_, err := service.Update(3, models.UpdateCategoryRequest{Color: "#FFFFFF"}, nil)
+
+		assert.Equal(t, []string{"name:required"}, fieldRules(t, err))
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("not found error", func(t *testing.T) {
 		mockRepo.On("GetByID", uint(999)).Return(nil, services.ErrCategoryNotFound).Once()
 
-		category, err := service.Update(999, models.UpdateCategoryRequest{})
+		category, err := service.Update(999, models.UpdateCategoryRequest{}, nil)
 
 		assert.Error(t, err)
 		assert.Nil(t, category)
@@ -210,6 +239,33 @@ func TestCategoryService_Update(t *testing.T) {
 	})
 }
 
+func TestCategoryService_Patch(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	service := services.NewCategoryService(mockRepo)
+
+	t.Run("null color resets to default", func(t *testing.T) {
+		existingCategory := &models.Category{
+			ID:    1,
+			Name:  "Work",
+			Color: "#000000",
+		}
+
+		var req models.PatchCategoryRequest
+		err := json.Unmarshal([]byte(`{"color": null}`), &req)
+		assert.NoError(t, err)
+
+		mockRepo.On("GetByID", uint(1)).Return(existingCategory, nil).Once()
+		mockRepo.On("Update", mock.AnythingOfType("*models.Category")).Return(nil).Once()
+
+		category, err := service.Patch(1, req, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Work", category.Name)
+		assert.Equal(t, "#3B82F6", category.Color)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
 func TestCategoryService_Delete(t *testing.T) {
 	mockRepo := new(MockCategoryRepository)
 	service := services.NewCategoryService(mockRepo)
@@ -218,9 +274,9 @@ func TestCategoryService_Delete(t *testing.T) {
 		existingCategory := &models.Category{ID: 1}
 
 		mockRepo.On("GetByID", uint(1)).Return(existingCategory, nil).Once()
-		mockRepo.On("Delete", uint(1)).Return(nil).Once()
+		mockRepo.On("Delete", uint(1), uint(0)).Return(nil).Once()
 
-		err := service.Delete(1)
+		err := service.Delete(1, nil)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
@@ -229,9 +285,21 @@ func TestCategoryService_Delete(t *testing.T) {
 	t.Run("not found error", func(t *testing.T) {
 		mockRepo.On("GetByID", uint(999)).Return(nil, services.ErrCategoryNotFound).Once()
 
-		err := service.Delete(999)
+		err := service.Delete(999, nil)
 
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("stale expected version", func(t *testing.T) {
+		existingCategory := &models.Category{ID: 2, Version: 2}
+		staleVersion := uint(1)
+
+		mockRepo.On("GetByID", uint(2)).Return(existingCategory, nil).Once()
+
+		err := service.Delete(2, &staleVersion)
+
+		assert.ErrorIs(t, err, services.ErrVersionConflict)
+		mockRepo.AssertExpectations(t)
+	})
 }
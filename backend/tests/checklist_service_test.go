@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockChecklistRepository is a mock implementation of ChecklistRepository
+type MockChecklistRepository struct {
+	mock.Mock
+}
+
+func (m *MockChecklistRepository) Create(item *models.ChecklistItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockChecklistRepository) GetByTodo(todoID uint) ([]models.ChecklistItem, error) {
+	args := m.Called(todoID)
+	return args.Get(0).([]models.ChecklistItem), args.Error(1)
+}
+
+func (m *MockChecklistRepository) GetByID(id uint) (*models.ChecklistItem, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChecklistItem), args.Error(1)
+}
+
+func (m *MockChecklistRepository) Update(item *models.ChecklistItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockChecklistRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockChecklistRepository) MaxPosition(todoID uint) (string, error) {
+	args := m.Called(todoID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockChecklistRepository) SetPositions(positions map[uint]string) error {
+	args := m.Called(positions)
+	return args.Error(0)
+}
+
+func (m *MockChecklistRepository) Count(todoID uint) (int64, error) {
+	args := m.Called(todoID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestChecklistService_Add(t *testing.T) {
+	todoRepo := new(MockTodoRepository)
+	todoRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+
+	t.Run("appends after the last item", func(t *testing.T) {
+		repo := new(MockChecklistRepository)
+		service := services.NewChecklistService(repo, todoRepo)
+		repo.On("Count", uint(1)).Return(int64(2), nil).Once()
+		repo.On("MaxPosition", uint(1)).Return("V", nil).Once()
+		repo.On("Create", mock.AnythingOfType("*models.ChecklistItem")).Return(nil).Once()
+
+		item, err := service.Add(1, models.CreateChecklistItemRequest{Title: "  Buy milk "})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Buy milk", item.Title)
+		assert.Greater(t, item.Position, "V")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects blank titles", func(t *testing.T) {
+		repo := new(MockChecklistRepository)
+		service := services.NewChecklistService(repo, todoRepo)
+
+		_, err := service.Add(1, models.CreateChecklistItemRequest{Title: "   "})
+
+		assert.ErrorIs(t, err, services.ErrChecklistTitleRequired)
+	})
+
+	t.Run("rejects full checklists", func(t *testing.T) {
+		repo := new(MockChecklistRepository)
+		service := services.NewChecklistService(repo, todoRepo)
+		repo.On("Count", uint(1)).Return(int64(200), nil).Once()
+
+		_, err := service.Add(1, models.CreateChecklistItemRequest{Title: "One more"})
+
+		assert.Equal(t, services.ErrChecklistFull, err)
+	})
+}
+
+func TestChecklistService_Update(t *testing.T) {
+	repo := new(MockChecklistRepository)
+	service := services.NewChecklistService(repo, new(MockTodoRepository))
+	repo.On("GetByID", uint(5)).Return(&models.ChecklistItem{ID: 5, TodoID: 1, Title: "Step"}, nil)
+
+	t.Run("checks an item", func(t *testing.T) {
+		checked := true
+		repo.On("Update", mock.AnythingOfType("*models.ChecklistItem")).Return(nil).Once()
+
+		item, err := service.Update(1, 5, models.UpdateChecklistItemRequest{Checked: &checked})
+
+		require.NoError(t, err)
+		assert.True(t, item.Checked)
+		assert.Equal(t, "Step", item.Title)
+	})
+
+	t.Run("item must belong to the todo", func(t *testing.T) {
+		_, err := service.Update(2, 5, models.UpdateChecklistItemRequest{})
+
+		assert.Equal(t, services.ErrChecklistItemNotFound, err)
+	})
+}
+
+func TestChecklistService_Move(t *testing.T) {
+	items := []models.ChecklistItem{
+		{ID: 1, TodoID: 1, Position: "F"},
+		{ID: 2, TodoID: 1, Position: "M"},
+		{ID: 3, TodoID: 1, Position: "T"},
+	}
+
+	newService := func() (*MockChecklistRepository, services.ChecklistService) {
+		repo := new(MockChecklistRepository)
+		repo.On("GetByID", uint(3)).Return(&models.ChecklistItem{ID: 3, TodoID: 1, Position: "T"}, nil)
+		repo.On("GetByTodo", uint(1)).Return(items, nil)
+		repo.On("Update", mock.AnythingOfType("*models.ChecklistItem")).Return(nil).Maybe()
+		return repo, services.NewChecklistService(repo, new(MockTodoRepository))
+	}
+
+	t.Run("moves to the top", func(t *testing.T) {
+		_, service := newService()
+		before := uint(1)
+
+		item, err := service.Move(1, 3, models.MoveChecklistItemRequest{Before: &before})
+
+		require.NoError(t, err)
+		assert.Less(t, item.Position, "F")
+	})
+
+	t.Run("moves between two items", func(t *testing.T) {
+		_, service := newService()
+		after, before := uint(1), uint(2)
+
+		item, err := service.Move(1, 3, models.MoveChecklistItemRequest{After: &after, Before: &before})
+
+		require.NoError(t, err)
+		assert.Greater(t, item.Position, "F")
+		assert.Less(t, item.Position, "M")
+	})
+
+	t.Run("lands right after its anchor", func(t *testing.T) {
+		_, service := newService()
+		after := uint(1)
+
+		item, err := service.Move(1, 3, models.MoveChecklistItemRequest{After: &after})
+
+		require.NoError(t, err)
+		assert.Greater(t, item.Position, "F")
+		assert.Less(t, item.Position, "M")
+	})
+
+	t.Run("rejects anchors from other checklists", func(t *testing.T) {
+		repo, service := newService()
+		after := uint(99)
+
+		_, err := service.Move(1, 3, models.MoveChecklistItemRequest{After: &after})
+
+		assert.ErrorIs(t, err, services.ErrChecklistAnchorNotFound)
+		repo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("requires an anchor", func(t *testing.T) {
+		_, service := newService()
+
+		_, err := service.Move(1, 3, models.MoveChecklistItemRequest{})
+
+		assert.ErrorIs(t, err, services.ErrMoveAnchorRequired)
+	})
+}
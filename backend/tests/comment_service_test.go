@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCommentRepository is a mock implementation of CommentRepository
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) Create(comment *models.Comment) error {
+	args := m.Called(comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) GetByTodo(todoID uint, page, limit int) ([]models.Comment, int64, error) {
+	args := m.Called(todoID, page, limit)
+	return args.Get(0).([]models.Comment), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCommentRepository) GetByID(id uint) (*models.Comment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) Update(comment *models.Comment) error {
+	args := m.Called(comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestCommentService_Create(t *testing.T) {
+	mockRepo := new(MockCommentRepository)
+	todoRepo := new(MockTodoRepository)
+	service := services.NewCommentService(mockRepo, todoRepo)
+
+	todoRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+	todoRepo.On("GetByID", uint(9)).Return(nil, assert.AnError)
+
+	t.Run("successful creation", func(t *testing.T) {
+		mockRepo.On("Create", mock.AnythingOfType("*models.Comment")).Return(nil).Once()
+
+		comment, err := service.Create(1, "alice", models.CreateCommentRequest{Body: "Looks **good**"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), comment.TodoID)
+		assert.Equal(t, "alice", comment.Author)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("blank body", func(t *testing.T) {
+		_, err := service.Create(1, "alice", models.CreateCommentRequest{Body: "  \n"})
+
+		assert.ErrorIs(t, err, services.ErrCommentBodyRequired)
+	})
+
+	t.Run("body too long", func(t *testing.T) {
+		_, err := service.Create(1, "alice", models.CreateCommentRequest{Body: strings.Repeat("a", 10001)})
+
+		assert.ErrorIs(t, err, services.ErrCommentBodyTooLong)
+	})
+
+	t.Run("todo not found", func(t *testing.T) {
+		_, err := service.Create(9, "alice", models.CreateCommentRequest{Body: "hi"})
+
+		assert.Equal(t, services.ErrTodoNotFound, err)
+	})
+}
+
+func TestCommentService_List(t *testing.T) {
+	mockRepo := new(MockCommentRepository)
+	todoRepo := new(MockTodoRepository)
+	service := services.NewCommentService(mockRepo, todoRepo)
+
+	todoRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+	mockRepo.On("GetByTodo", uint(1), 1, 20).Return([]models.Comment{{ID: 1}, {ID: 2}}, int64(45), nil).Once()
+
+	response, err := service.List(1, 0, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, response.Pagination.TotalPages)
+	assert.Len(t, response.Data, 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCommentService_UpdateAndDelete(t *testing.T) {
+	mockRepo := new(MockCommentRepository)
+	service := services.NewCommentService(mockRepo, new(MockTodoRepository))
+
+	mockRepo.On("GetByID", uint(5)).Return(&models.Comment{ID: 5, TodoID: 1, Author: "alice", Body: "old"}, nil)
+
+	t.Run("author edits the body", func(t *testing.T) {
+		mockRepo.On("Update", mock.AnythingOfType("*models.Comment")).Return(nil).Once()
+
+		comment, err := service.Update(1, 5, "alice", models.UpdateCommentRequest{Body: "new"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "new", comment.Body)
+	})
+
+	t.Run("other users cannot edit", func(t *testing.T) {
+		_, err := service.Update(1, 5, "bob", models.UpdateCommentRequest{Body: "new"})
+
+		assert.Equal(t, services.ErrCommentForbidden, err)
+	})
+
+	t.Run("comment must belong to the todo", func(t *testing.T) {
+		err := service.Delete(2, 5, "alice")
+
+		assert.Equal(t, services.ErrCommentNotFound, err)
+	})
+
+	t.Run("author deletes", func(t *testing.T) {
+		mockRepo.On("Delete", uint(5)).Return(nil).Once()
+
+		err := service.Delete(1, 5, "alice")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var workFields = models.CustomFieldDefinitions{
+	{Key: "client", Name: "Client", Type: models.CustomFieldText, Required: true},
+	{Key: "hours", Name: "Hours", Type: models.CustomFieldNumber},
+	{Key: "kickoff", Name: "Kickoff", Type: models.CustomFieldDate},
+	{Key: "stage", Name: "Stage", Type: models.CustomFieldSelect, Options: []string{"lead", "won"}},
+	{Key: "billable", Name: "Billable", Type: models.CustomFieldCheckbox},
+}
+
+func fieldRules(t *testing.T, err error) []string {
+	var verr *services.ValidationError
+	require.ErrorAs(t, err, &verr)
+	rules := make([]string, len(verr.Fields))
+	for i, f := range verr.Fields {
+		rules[i] = f.Field + ":" + f.Rule
+	}
+	return rules
+}
+
+func TestCategoryService_CustomFieldDefinitions(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	service := services.NewCategoryService(mockRepo)
+
+	t.Run("accepts valid definitions", func(t *testing.T) {
+		mockRepo.On("Create", mock.AnythingOfType("*models.Category")).Return(nil).Once()
+
+		category, err := service.Create(models.CreateCategoryRequest{Name: "Work", CustomFields: workFields})
+
+		require.NoError(t, err)
+		assert.Len(t, category.CustomFields, 5)
+	})
+
+	t.Run("reports every invalid definition", func(t *testing.T) {
+		_, err := service.Create(models.CreateCategoryRequest{Name: "Work", CustomFields: models.CustomFieldDefinitions{
+			{Key: "Client Name", Name: "Client", Type: models.CustomFieldText},
+			{Key: "store", Name: "", Type: "color"},
+			{Key: "store", Name: "Store", Type: models.CustomFieldSelect},
+		}})
+
+		assert.ElementsMatch(t, []string{
+			"custom_fields[0].key:format",
+			"custom_fields[1].name:required",
+			"custom_fields[1].type:oneof",
+			"custom_fields[2].key:unique",
+			"custom_fields[2].options:required",
+		}, fieldRules(t, err))
+	})
+}
+
+func TestTodoService_CustomFieldValues(t *testing.T) {
+	categoryID := uint(3)
+	work := &models.Category{ID: categoryID, Name: "Work", CustomFields: workFields}
+
+	newService := func() (*MockTodoRepository, services.TodoService) {
+		mockRepo := new(MockTodoRepository)
+		categoryRepo := new(MockCategoryRepository)
+		categoryRepo.On("GetByID", categoryID).Return(work, nil)
+		return mockRepo, newTodoService(mockRepo, categoryRepo)
+	}
+
+	t.Run("stores valid values", func(t *testing.T) {
+		mockRepo, service := newService()
+		mockRepo.On("MaxPosition").Return("", nil).Once()
+		mockRepo.On("LockPositions").Return(nil)
+		mockRepo.On("Create", mock.MatchedBy(func(todo *models.Todo) bool {
+			return todo.CustomFields["client"] == "Acme" && todo.CustomFields["hours"] == 2.5 &&
+				todo.CustomFields["stage"] == "won" && todo.CustomFields["billable"] == true
+		})).Return(nil).Once()
+		mockRepo.On("GetByID", mock.Anything).Return(&models.Todo{ID: 1}, nil).Once()
+
+		_, err := service.Create(models.CreateTodoRequest{
+			Title:      "Proposal",
+			CategoryID: &categoryID,
+			CustomFields: models.CustomFieldValues{
+				"client":   "Acme",
+				"hours":    2.5,
+				"kickoff":  "2026-11-02",
+				"stage":    "won",
+				"billable": true,
+			},
+		})
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects wrong types, unknown keys and missing required fields", func(t *testing.T) {
+		_, service := newService()
+
+		_, err := service.Create(models.CreateTodoRequest{
+			Title:      "Proposal",
+			CategoryID: &categoryID,
+			CustomFields: models.CustomFieldValues{
+				"hours":    "two",
+				"kickoff":  "02/11/2026",
+				"stage":    "lost",
+				"billable": "yes",
+				"store":    "Aldi",
+			},
+		})
+
+		assert.ElementsMatch(t, []string{
+			"custom_fields.hours:number",
+			"custom_fields.kickoff:date",
+			"custom_fields.stage:select",
+			"custom_fields.billable:checkbox",
+			"custom_fields.store:defined",
+			"custom_fields.client:required",
+		}, fieldRules(t, err))
+	})
+
+	t.Run("todos without a category cannot have values", func(t *testing.T) {
+		_, service := newService()
+
+		_, err := service.Create(models.CreateTodoRequest{
+			Title:        "Loose",
+			CustomFields: models.CustomFieldValues{"client": "Acme"},
+		})
+
+		assert.ErrorIs(t, err, services.ErrCustomFieldUnknown)
+	})
+
+	t.Run("patch merges values and null removes one", func(t *testing.T) {
+		mockRepo, service := newService()
+		existing := &models.Todo{
+			ID:           1,
+			Version:      1,
+			CategoryID:   &categoryID,
+			Category:     work,
+			CustomFields: models.CustomFieldValues{"client": "Acme", "hours": 2.0},
+		}
+		mockRepo.On("GetByID", uint(1)).Return(existing, nil)
+		mockRepo.On("Update", mock.MatchedBy(func(todo *models.Todo) bool {
+			_, hasHours := todo.CustomFields["hours"]
+			return todo.CustomFields["client"] == "Acme" && todo.CustomFields["stage"] == "lead" && !hasHours
+		})).Return(nil).Once()
+
+		var req models.PatchTodoRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"custom_fields": {"stage": "lead", "hours": null}}`), &req))
+		_, err := service.Patch(1, req, nil)
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+	t.Run("bulk move revalidates values against the new category", func(t *testing.T) {
+		mockRepo, service := newService()
+		personalID := uint(4)
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{
+			ID:           1,
+			CategoryID:   &personalID,
+			CustomFields: models.CustomFieldValues{"client": "Acme", "mood": "good"},
+		}, nil).Once()
+		mockRepo.On("GetByID", uint(2)).Return(&models.Todo{ID: 2, CategoryID: &personalID}, nil).Once()
+		mockRepo.On("Update", mock.MatchedBy(func(todo *models.Todo) bool {
+			_, hasMood := todo.CustomFields["mood"]
+			return todo.ID == 1 && *todo.CategoryID == categoryID && todo.CustomFields["client"] == "Acme" && !hasMood
+		})).Return(nil).Once()
+
+		response, err := service.Bulk(models.BulkTodoRequest{
+			IDs:        []uint{1, 2},
+			Action:     models.BulkActionMoveToCategory,
+			CategoryID: &categoryID,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Succeeded)
+		assert.Equal(t, models.BulkStatusFailed, response.Results[1].Status)
+		assert.Contains(t, response.Results[1].Error, "custom_fields.client")
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/ical"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestICal_EncodeDecode(t *testing.T) {
+	todo := ical.NewComponent("VTODO")
+	todo.AddText("SUMMARY", "Buy milk, eggs; bread\nand a very long list of other things that does not fit on one line at all — really")
+	todo.Properties = append(todo.Properties, ical.Property{
+		Name:   "ATTENDEE",
+		Params: map[string]string{"CN": "Doe, Jane", "ROLE": "REQ-PARTICIPANT"},
+		Value:  "mailto:jane@example.com",
+	})
+	todo.AddTime("DUE", time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), true)
+	calendar := ical.NewComponent("VCALENDAR")
+	calendar.Add("VERSION", "2.0")
+	calendar.Children = append(calendar.Children, todo)
+
+	var buf bytes.Buffer
+	require.NoError(t, ical.NewEncoder(&buf).Encode(calendar))
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+	assert.Contains(t, buf.String(), `SUMMARY:Buy milk\, eggs\; bread\nand`)
+	assert.Contains(t, buf.String(), `ATTENDEE;CN="Doe, Jane";ROLE=REQ-PARTICIPANT:mailto:jane@example.com`)
+	assert.Contains(t, buf.String(), "DUE;VALUE=DATE:20261020\r\n")
+
+	decoded, err := ical.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "VCALENDAR", decoded.Name)
+	require.Len(t, decoded.Components("VTODO"), 1)
+	vtodo := decoded.Components("VTODO")[0]
+	assert.Equal(t, 3, vtodo.Line)
+	assert.Equal(t, todo.Text("SUMMARY"), vtodo.Text("SUMMARY"))
+	attendee, ok := vtodo.Get("ATTENDEE")
+	require.True(t, ok)
+	assert.Equal(t, "Doe, Jane", attendee.Params["CN"])
+	assert.Equal(t, "mailto:jane@example.com", attendee.Value)
+}
+
+func TestICal_Decode(t *testing.T) {
+	t.Run("accepts LF endings, lower case names and folded lines", func(t *testing.T) {
+		input := "begin:vcalendar\nBEGIN:VTODO\nSUMMARY:Call\n  the bank\nCATEGORIES:Work,Home\\, garden\nEND:VTODO\nEND:VCALENDAR\nBEGIN:VCALENDAR\n"
+
+		calendar, err := ical.Decode(strings.NewReader(input))
+
+		require.NoError(t, err)
+		vtodo := calendar.Components("VTODO")[0]
+		assert.Equal(t, "Call the bank", vtodo.Text("SUMMARY"))
+		categories, _ := vtodo.Get("CATEGORIES")
+		assert.Equal(t, []string{"Work", "Home, garden"}, ical.SplitText(categories.Value))
+	})
+
+	t.Run("rejects malformed input", func(t *testing.T) {
+		inputs := map[string]string{
+			"empty":              "",
+			"missing end":        "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\n",
+			"mismatched end":     "BEGIN:VCALENDAR\r\nEND:VTODO\r\n",
+			"property outside":   "SUMMARY:loose\r\n",
+			"line without colon": "BEGIN:VCALENDAR\r\nSUMMARY\r\nEND:VCALENDAR\r\n",
+		}
+		for name, input := range inputs {
+			_, err := ical.Decode(strings.NewReader(input))
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestICal_ParseTime(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		prop     ical.Property
+		want     time.Time
+		dateOnly bool
+	}{
+		{"date", ical.Property{Value: "20261020", Params: map[string]string{"VALUE": "DATE"}}, time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), true},
+		{"utc", ical.Property{Value: "20261020T083000Z"}, time.Date(2026, 10, 20, 8, 30, 0, 0, time.UTC), false},
+		{"zoned", ical.Property{Value: "20261020T083000", Params: map[string]string{"TZID": "Asia/Jakarta"}}, time.Date(2026, 10, 20, 8, 30, 0, 0, jakarta), false},
+		{"floating", ical.Property{Value: "20261020T083000"}, time.Date(2026, 10, 20, 8, 30, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dateOnly, err := ical.ParseTime(tt.prop, time.UTC)
+
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), got)
+			assert.Equal(t, tt.dateOnly, dateOnly)
+		})
+	}
+
+	_, _, err = ical.ParseTime(ical.Property{Name: "DUE", Value: "tomorrow"}, time.UTC)
+	assert.Error(t, err)
+	_, _, err = ical.ParseTime(ical.Property{Name: "DUE", Value: "20261020T083000", Params: map[string]string{"TZID": "Mars/Olympus"}}, time.UTC)
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockIdempotencyRepository is a mock implementation of IdempotencyRepository
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) CreateIfAbsent(record *models.IdempotencyRecord, staleBefore time.Time) (bool, error) {
+	args := m.Called(record, staleBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Get(scope, key string) (*models.IdempotencyRecord, error) {
+	args := m.Called(scope, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyRecord), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Complete(scope, key string, statusCode int, body []byte) error {
+	args := m.Called(scope, key, statusCode, body)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) Delete(scope, key string) error {
+	args := m.Called(scope, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	args := m.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepository)
+	service := services.NewIdempotencyService(mockRepo, time.Hour)
+
+	t.Run("claims a new key", func(t *testing.T) {
+		mockRepo.On("CreateIfAbsent", mock.AnythingOfType("*models.IdempotencyRecord"), mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+
+		record, err := service.Begin("/api/todos", "key-1", "hash")
+
+		assert.NoError(t, err)
+		assert.False(t, record.Completed())
+		assert.WithinDuration(t, time.Now().Add(time.Hour), record.ExpiresAt, time.Minute)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("takes over claims left without a response", func(t *testing.T) {
+		mockRepo.On("CreateIfAbsent", mock.AnythingOfType("*models.IdempotencyRecord"), mock.MatchedBy(func(staleBefore time.Time) bool {
+			return staleBefore.Before(time.Now().Add(-time.Minute)) && staleBefore.After(time.Now().Add(-time.Hour))
+		})).Return(true, nil).Once()
+
+		_, err := service.Begin("/api/todos", "key-5", "hash")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("replays a completed request", func(t *testing.T) {
+		stored := &models.IdempotencyRecord{
+			Scope:          "/api/todos",
+			IdempotencyKey: "key-2",
+			RequestHash:    "hash",
+			StatusCode:     201,
+			ResponseBody:   []byte(`{"id":1}`),
+		}
+
+		mockRepo.On("CreateIfAbsent", mock.AnythingOfType("*models.IdempotencyRecord"), mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+		mockRepo.On("Get", "/api/todos", "key-2").Return(stored, nil).Once()
+
+		record, err := service.Begin("/api/todos", "key-2", "hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, stored, record)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a key reused with a different body", func(t *testing.T) {
+		stored := &models.IdempotencyRecord{RequestHash: "other", StatusCode: 201}
+
+		mockRepo.On("CreateIfAbsent", mock.AnythingOfType("*models.IdempotencyRecord"), mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+		mockRepo.On("Get", "/api/todos", "key-3").Return(stored, nil).Once()
+
+		record, err := service.Begin("/api/todos", "key-3", "hash")
+
+		assert.Nil(t, record)
+		assert.ErrorIs(t, err, services.ErrIdempotencyKeyReused)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("reports a request still in progress", func(t *testing.T) {
+		stored := &models.IdempotencyRecord{RequestHash: "hash"}
+
+		mockRepo.On("CreateIfAbsent", mock.AnythingOfType("*models.IdempotencyRecord"), mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+		mockRepo.On("Get", "/api/todos", "key-4").Return(stored, nil).Once()
+
+		record, err := service.Begin("/api/todos", "key-4", "hash")
+
+		assert.Nil(t, record)
+		assert.ErrorIs(t, err, services.ErrIdempotencyInProgress)
+		mockRepo.AssertExpectations(t)
+	})
+}
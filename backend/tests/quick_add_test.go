@@ -0,0 +1,273 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	// A Sunday morning
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, jakarta)
+	at := func(year int, month time.Month, day, hour, minute int) *time.Time {
+		t := time.Date(year, month, day, hour, minute, 0, 0, jakarta)
+		return &t
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want models.QuickAddParse
+	}{
+		{
+			name: "everything at once",
+			text: "Pay rent tomorrow 9am !high #Personal every month",
+			want: models.QuickAddParse{
+				Title:      "Pay rent",
+				DueDate:    at(2026, 10, 19, 9, 0),
+				Priority:   models.PriorityHigh,
+				Category:   "Personal",
+				Tags:       []string{},
+				Recurrence: "FREQ=MONTHLY",
+			},
+		},
+		{
+			name: "a time that has passed is tomorrow",
+			text: "Call mom at 8 am",
+			want: models.QuickAddParse{Title: "Call mom", DueDate: at(2026, 10, 19, 8, 0), Tags: []string{}},
+		},
+		{
+			name: "tonight with tags and spaces in names",
+			text: "Dinner tonight #Home_Life @family @date_night",
+			want: models.QuickAddParse{
+				Title:    "Dinner",
+				DueDate:  at(2026, 10, 18, 20, 0),
+				Category: "Home Life",
+				Tags:     []string{"family", "date night"},
+			},
+		},
+		{
+			name: "later categories become tags",
+			text: "Sync #Work #planning",
+			want: models.QuickAddParse{Title: "Sync", Category: "Work", Tags: []string{"planning"}},
+		},
+		{
+			name: "recurrence without a date starts today",
+			text: "Water plants every other day",
+			want: models.QuickAddParse{
+				Title:      "Water plants",
+				DueDate:    at(2026, 10, 18, 0, 0),
+				Tags:       []string{},
+				Recurrence: "FREQ=DAILY;INTERVAL=2",
+			},
+		},
+		{
+			name: "every weekday starts on that day",
+			text: "Gym every friday 6:30pm",
+			want: models.QuickAddParse{
+				Title:      "Gym",
+				DueDate:    at(2026, 10, 23, 18, 30),
+				Tags:       []string{},
+				Recurrence: "FREQ=WEEKLY",
+			},
+		},
+		{
+			name: "next weekday is in the following week",
+			text: "Review next tue !1",
+			want: models.QuickAddParse{Title: "Review", DueDate: at(2026, 10, 20, 0, 0), Priority: models.PriorityHigh, Tags: []string{}},
+		},
+		{
+			name: "relative offset",
+			text: "Plan offsite in 2 weeks !low",
+			want: models.QuickAddParse{Title: "Plan offsite", DueDate: at(2026, 11, 1, 0, 0), Priority: models.PriorityLow, Tags: []string{}},
+		},
+		{
+			name: "month and day with a year",
+			text: "Renew passport on Nov 3rd, 2027",
+			want: models.QuickAddParse{Title: "Renew passport", DueDate: at(2027, 11, 3, 0, 0), Tags: []string{}},
+		},
+		{
+			name: "a passed day of month is next year",
+			text: "Dentist 3 Oct",
+			want: models.QuickAddParse{Title: "Dentist", DueDate: at(2027, 10, 3, 0, 0), Tags: []string{}},
+		},
+		{
+			name: "ISO date, 24 hour time and a frequency word",
+			text: "Deploy 2026-11-01 21:00 weekly",
+			want: models.QuickAddParse{Title: "Deploy", DueDate: at(2026, 11, 1, 21, 0), Tags: []string{}, Recurrence: "FREQ=WEEKLY"},
+		},
+		{
+			name: "only the first date counts",
+			text: "Read Tuesdays with Morrie monday today",
+			want: models.QuickAddParse{Title: "Read Tuesdays with Morrie today", DueDate: at(2026, 10, 19, 0, 0), Tags: []string{}},
+		},
+		{
+			name: "words that are not understood stay in the title",
+			text: "Buy 2 tickets for May 31st show !urgent at the 9 o'clock",
+			want: models.QuickAddParse{Title: "Buy 2 tickets for show !urgent at the 9 o'clock", DueDate: at(2027, 5, 31, 0, 0), Tags: []string{}},
+		},
+		{
+			name: "impossible dates are not dates",
+			text: "Pay Feb 30",
+			want: models.QuickAddParse{Title: "Pay Feb 30", Tags: []string{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := services.ParseQuickAdd(tt.text, now)
+			if tt.want.DueDate == nil {
+				assert.Nil(t, got.DueDate)
+			} else if assert.NotNil(t, got.DueDate) {
+				assert.True(t, tt.want.DueDate.Equal(*got.DueDate), "due %s, want %s", got.DueDate, tt.want.DueDate)
+			}
+			got.DueDate, tt.want.DueDate = nil, nil
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	r, err := services.ParseRecurrence("rrule:freq=weekly;interval=2")
+	require.NoError(t, err)
+	assert.Equal(t, services.Recurrence{Frequency: "WEEKLY", Interval: 2}, r)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2", r.String())
+
+	r, err = services.ParseRecurrence("FREQ=MONTHLY;INTERVAL=1")
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=MONTHLY", r.String())
+
+	for _, rule := range []string{"", "FREQ=HOURLY", "INTERVAL=2", "FREQ=DAILY;INTERVAL=0", "FREQ=DAILY;COUNT=3"} {
+		_, err := services.ParseRecurrence(rule)
+		assert.ErrorIs(t, err, services.ErrInvalidRecurrence, rule)
+	}
+}
+
+func TestRecurrence_Next(t *testing.T) {
+	monthly := services.Recurrence{Frequency: "MONTHLY", Interval: 1}
+	start := *date(2026, 1, 31)
+	assert.Equal(t, *date(2026, 2, 28), monthly.Next(start, start))
+	// The day of month comes back after a short month
+	assert.Equal(t, *date(2026, 3, 31), monthly.Next(start, *date(2026, 2, 28)))
+
+	fortnightly := services.Recurrence{Frequency: "WEEKLY", Interval: 2}
+	assert.Equal(t, *date(2026, 10, 29), fortnightly.Next(*date(2026, 10, 1), *date(2026, 10, 20)))
+
+	yearly := services.Recurrence{Frequency: "YEARLY", Interval: 1}
+	assert.Equal(t, *date(2029, 2, 28), yearly.Next(*date(2028, 2, 29), *date(2028, 2, 29)))
+}
+
+func TestTodoService_QuickAdd(t *testing.T) {
+	t.Run("creates the todo with a new category", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+		personal := &models.Category{ID: 4, Name: "Personal"}
+		mockRepo.On("FindOrCreateCategory", "personal").Return(personal, true, nil).Once()
+		mockRepo.On("MaxPosition").Return("", nil).Once()
+		mockRepo.On("LockPositions").Return(nil)
+		var created *models.Todo
+		mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Run(func(args mock.Arguments) {
+			created = args.Get(0).(*models.Todo)
+		}).Return(nil).Once()
+		mockRepo.On("AddTag", uint(1), "bills").Return(nil).Once()
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1, Title: "Pay rent"}, nil).Once()
+
+		response, err := service.QuickAdd(models.QuickAddRequest{
+			Text:     "Pay rent tomorrow 9am !high #personal @bills every month",
+			TimeZone: "Asia/Jakarta",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Pay rent", response.Parsed.Title)
+		assert.Equal(t, "Personal", response.Parsed.Category)
+		assert.True(t, response.CategoryCreated)
+		assert.Equal(t, uint(4), *response.CategoryID)
+		require.NotNil(t, response.Todo)
+		assert.Equal(t, uint(1), response.Todo.ID)
+		assert.False(t, response.DryRun)
+		require.NotNil(t, created)
+		assert.Equal(t, models.PriorityHigh, created.Priority)
+		assert.Equal(t, uint(4), *created.CategoryID)
+		assert.Equal(t, "FREQ=MONTHLY", created.Recurrence)
+		assert.Equal(t, "Asia/Jakarta", created.DueDate.Location().String())
+		assert.Equal(t, 9, created.DueDate.Hour())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("dry run only reports what was understood", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+		mockRepo.On("FindOrCreateCategory", "Errands").Return(&models.Category{ID: 9, Name: "Errands"}, true, nil).Once()
+		mockRepo.On("MaxPosition").Return("", nil).Once()
+		mockRepo.On("LockPositions").Return(nil)
+		mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil).Once()
+
+		response, err := service.QuickAdd(models.QuickAddRequest{Text: "Post letter #Errands", DryRun: true})
+
+		require.NoError(t, err)
+		assert.True(t, response.DryRun)
+		assert.Nil(t, response.Todo)
+		// The category was rolled back with the todo
+		assert.True(t, response.CategoryCreated)
+		assert.Nil(t, response.CategoryID)
+	})
+
+	t.Run("rejects lines without a title and unknown time zones", func(t *testing.T) {
+		service := newTodoService(new(MockTodoRepository), new(MockCategoryRepository))
+
+		_, err := service.QuickAdd(models.QuickAddRequest{Text: "tomorrow !high #Work"})
+		assert.Equal(t, []string{"text:required"}, fieldRules(t, err))
+
+		_, err = service.QuickAdd(models.QuickAddRequest{Text: "Pay rent", TimeZone: "Mars/Olympus"})
+		assert.Equal(t, []string{"time_zone:timezone"}, fieldRules(t, err))
+	})
+
+	t.Run("rejects invalid recurrence on create", func(t *testing.T) {
+		service := newTodoService(new(MockTodoRepository), new(MockCategoryRepository))
+
+		_, err := service.Create(models.CreateTodoRequest{Title: "Stretch", Recurrence: "FREQ=HOURLY"})
+		assert.Equal(t, []string{"recurrence:format"}, fieldRules(t, err))
+	})
+}
+
+func TestTodoService_CompleteRecurring(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
+	due := time.Date(2099, 1, 31, 9, 0, 0, 0, time.UTC)
+	mockRepo.On("GetByID", uint(5)).Return(&models.Todo{
+		ID:         5,
+		Title:      "Pay rent",
+		Priority:   models.PriorityHigh,
+		DueDate:    &due,
+		Recurrence: "FREQ=MONTHLY",
+		Tags:       []models.Tag{{Name: "bills"}},
+	}, nil).Once()
+	mockRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+	mockRepo.On("MaxPosition").Return("", nil).Once()
+	mockRepo.On("LockPositions").Return(nil)
+	var next *models.Todo
+	mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Run(func(args mock.Arguments) {
+		next = args.Get(0).(*models.Todo)
+	}).Return(nil).Once()
+	mockRepo.On("AddTag", uint(1), "bills").Return(nil).Once()
+	mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil).Once()
+
+	todo, err := service.ToggleComplete(5)
+
+	require.NoError(t, err)
+	assert.True(t, todo.Completed)
+	require.NotNil(t, next)
+	assert.Equal(t, "Pay rent", next.Title)
+	assert.False(t, next.Completed)
+	assert.Equal(t, models.PriorityHigh, next.Priority)
+	assert.Equal(t, "FREQ=MONTHLY", next.Recurrence)
+	assert.Equal(t, time.Date(2099, 2, 28, 9, 0, 0, 0, time.UTC), *next.DueDate)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name  string
+		lower string
+		upper string
+	}{
+		{"empty list", "", ""},
+		{"before first", "", "V"},
+		{"after last", "V", ""},
+		{"adjacent digits", "V", "W"},
+		{"shared prefix", "V1", "V2"},
+		{"prefix of upper", "V", "V1"},
+		{"end of digit range", "z", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rank := services.RankBetween(tt.lower, tt.upper)
+
+			assert.Greater(t, rank, tt.lower)
+			if tt.upper != "" {
+				assert.Less(t, rank, tt.upper)
+			}
+			assert.NotEqual(t, byte('0'), rank[len(rank)-1])
+		})
+	}
+}
+
+func TestRankAfter(t *testing.T) {
+	t.Run("appended ranks keep a fixed width", func(t *testing.T) {
+		ranks := []string{services.RankAfter("")}
+		for i := 0; i < 5000; i++ {
+			ranks = append(ranks, services.RankAfter(ranks[len(ranks)-1]))
+		}
+
+		assert.IsIncreasing(t, ranks)
+		for _, rank := range ranks[1:] {
+			assert.Len(t, rank, 6)
+			assert.NotEqual(t, byte('0'), rank[len(rank)-1])
+		}
+	})
+
+	t.Run("carries into the next digit", func(t *testing.T) {
+		assert.Equal(t, "V00011", services.RankAfter("V0000z"))
+		assert.Greater(t, services.RankAfter("V0000z"), "V0000z")
+		assert.Greater(t, services.RankAfter("zzzzzz"), "zzzzzz")
+	})
+}
+
+func TestRanksBetween(t *testing.T) {
+	t.Run("short increasing ranks", func(t *testing.T) {
+		ranks := services.RanksBetween("", "", 1000)
+
+		assert.Len(t, ranks, 1000)
+		assert.IsIncreasing(t, ranks)
+		for _, rank := range ranks {
+			assert.LessOrEqual(t, len(rank), 3)
+		}
+	})
+}
@@ -0,0 +1,233 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSmartListRepository is a mock implementation of SmartListRepository
+type MockSmartListRepository struct {
+	mock.Mock
+}
+
+func (m *MockSmartListRepository) Create(list *models.SmartList) error {
+	args := m.Called(list)
+	list.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockSmartListRepository) GetAll() ([]models.SmartList, error) {
+	args := m.Called()
+	return args.Get(0).([]models.SmartList), args.Error(1)
+}
+
+func (m *MockSmartListRepository) GetByID(id uint) (*models.SmartList, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SmartList), args.Error(1)
+}
+
+func (m *MockSmartListRepository) Keys() ([]string, error) {
+	args := m.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockSmartListRepository) Update(list *models.SmartList) error {
+	args := m.Called(list)
+	return args.Error(0)
+}
+
+func (m *MockSmartListRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type smartListMocks struct {
+	repo         *MockSmartListRepository
+	todoRepo     *MockTodoRepository
+	categoryRepo *MockCategoryRepository
+	statusRepo   *MockStatusRepository
+}
+
+func newSmartListService() (smartListMocks, services.SmartListService) {
+	m := smartListMocks{
+		repo:         new(MockSmartListRepository),
+		todoRepo:     new(MockTodoRepository),
+		categoryRepo: new(MockCategoryRepository),
+		statusRepo:   new(MockStatusRepository),
+	}
+	todos := newTodoService(m.todoRepo, m.categoryRepo)
+	return m, services.NewSmartListService(m.repo, m.categoryRepo, m.statusRepo, todos)
+}
+
+func TestSmartListService_Create(t *testing.T) {
+	t.Run("saves the filter without pagination", func(t *testing.T) {
+		m, service := newSmartListService()
+		m.categoryRepo.On("GetByID", uint(2)).Return(&models.Category{ID: 2, Name: "Work"}, nil).Once()
+		m.repo.On("Create", mock.AnythingOfType("*models.SmartList")).Return(nil).Once()
+		categoryID := uint(2)
+
+		list, err := service.Create(models.SmartListRequest{
+			Name: " Work this week ",
+			Filter: models.TodoFilter{
+				CategoryID: &categoryID,
+				Priority:   models.PriorityHigh,
+				Due:        models.DueUpcoming,
+				SortOrder:  "DESC",
+				Page:       3,
+				Limit:      50,
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Work this week", list.Name)
+		assert.Empty(t, list.Key)
+		assert.Equal(t, models.SmartListFilter{
+			CategoryID: &categoryID,
+			Priority:   models.PriorityHigh,
+			Due:        models.DueUpcoming,
+			SortOrder:  "desc",
+		}, list.Filter)
+		m.repo.AssertExpectations(t)
+	})
+
+	t.Run("reports every invalid field", func(t *testing.T) {
+		m, service := newSmartListService()
+		m.categoryRepo.On("GetByID", uint(9)).Return(nil, errors.New("record not found")).Once()
+		categoryID := uint(9)
+		from, to := *date(2026, 11, 1), *date(2026, 10, 1)
+
+		_, err := service.Create(models.SmartListRequest{
+			Name: "  ",
+			Filter: models.TodoFilter{
+				CategoryID:   &categoryID,
+				Priority:     "urgent",
+				Due:          "someday",
+				DueFrom:      &from,
+				DueTo:        &to,
+				TimeZone:     "Mars/Olympus",
+				Query:        "tag:",
+				CustomFields: map[string]string{"Bad Key": "x"},
+				SortOrder:    "up",
+			},
+		})
+
+		assert.ElementsMatch(t, []string{
+			"name:required",
+			"filter.category_id:exists",
+			"filter.priority:oneof",
+			"filter.due:oneof",
+			"filter.due_to:gtfield",
+			"filter.time_zone:timezone",
+			"filter.q:query",
+			"filter.custom_fields.Bad Key:format",
+			"filter.sort_order:oneof",
+		}, fieldRules(t, err))
+		m.repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestSmartListService_BuiltIn(t *testing.T) {
+	m, service := newSmartListService()
+	builtIn := &models.SmartList{ID: 1, Key: models.SmartListToday, Name: "Today"}
+	m.repo.On("GetByID", uint(1)).Return(builtIn, nil)
+
+	_, err := service.Update(1, models.SmartListRequest{Name: "Mine"})
+	assert.ErrorIs(t, err, services.ErrSmartListBuiltIn)
+	assert.ErrorIs(t, service.Delete(1), services.ErrSmartListBuiltIn)
+	m.repo.AssertNotCalled(t, "Update", mock.Anything)
+	m.repo.AssertNotCalled(t, "Delete", mock.Anything)
+
+	m.repo.On("GetByID", uint(7)).Return(nil, errors.New("record not found")).Once()
+	assert.ErrorIs(t, service.Delete(7), services.ErrSmartListNotFound)
+}
+
+func TestSmartListService_EnsureDefaults(t *testing.T) {
+	m, service := newSmartListService()
+	m.repo.On("Keys").Return([]string{models.SmartListToday, models.SmartListOverdue}, nil).Once()
+	var seeded []string
+	m.repo.On("Create", mock.AnythingOfType("*models.SmartList")).Run(func(args mock.Arguments) {
+		seeded = append(seeded, args.Get(0).(*models.SmartList).Key)
+	}).Return(nil)
+
+	require.NoError(t, service.EnsureDefaults())
+
+	assert.Equal(t, []string{models.SmartListUpcoming, models.SmartListNoDate}, seeded)
+}
+
+func TestSmartListService_Todos(t *testing.T) {
+	m, service := newSmartListService()
+	completed := false
+	m.repo.On("GetByID", uint(1)).Return(&models.SmartList{
+		ID:     1,
+		Key:    models.SmartListToday,
+		Filter: models.SmartListFilter{Due: models.DueToday, Completed: &completed, TimeZone: "UTC"},
+	}, nil).Once()
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	now := time.Now().In(jakarta)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, jakarta)
+	m.todoRepo.On("GetAll", mock.MatchedBy(func(filter models.TodoFilter) bool {
+		return filter.Page == 2 && filter.Limit == 10 && !*filter.Completed &&
+			filter.DueFrom.Equal(today) && filter.DueTo.Equal(today.AddDate(0, 0, 1))
+	})).Return([]models.Todo{{ID: 4}}, int64(11), nil).Once()
+
+	response, err := service.Todos(1, 2, 0, "Asia/Jakarta")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.Pagination.CurrentPage)
+	assert.Equal(t, 2, response.Pagination.TotalPages)
+	m.todoRepo.AssertExpectations(t)
+	_, err = service.Todos(1, 1, 10, "Mars/Olympus")
+	assert.Equal(t, []string{"time_zone:timezone"}, fieldRules(t, err))
+}
+
+func TestTodoService_GetAllDueWindows(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	// An absolute bound narrower than the window is kept
+	narrower := today.AddDate(0, 0, 3)
+
+	tests := []struct {
+		due      models.DueWindow
+		dueTo    *time.Time
+		wantFrom *time.Time
+		wantTo   time.Time
+	}{
+		{due: models.DueOverdue, wantTo: today},
+		{due: models.DueTomorrow, wantFrom: ptrTime(today.AddDate(0, 0, 1)), wantTo: today.AddDate(0, 0, 2)},
+		{due: models.DueUpcoming, wantFrom: ptrTime(today.AddDate(0, 0, 1)), wantTo: today.AddDate(0, 0, 8)},
+		{due: models.DueUpcoming, dueTo: &narrower, wantFrom: ptrTime(today.AddDate(0, 0, 1)), wantTo: narrower},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.due), func(t *testing.T) {
+			mockRepo.On("GetAll", mock.MatchedBy(func(filter models.TodoFilter) bool {
+				if (filter.DueFrom == nil) != (tt.wantFrom == nil) {
+					return false
+				}
+				return (tt.wantFrom == nil || filter.DueFrom.Equal(*tt.wantFrom)) && filter.DueTo.Equal(tt.wantTo)
+			})).Return([]models.Todo{}, int64(0), nil).Once()
+
+			_, err := service.GetAll(models.TodoFilter{Due: tt.due, DueTo: tt.dueTo})
+
+			require.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,291 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStatusRepository is a mock implementation of StatusRepository
+type MockStatusRepository struct {
+	mock.Mock
+}
+
+func (m *MockStatusRepository) Create(status *models.Status) error {
+	args := m.Called(status)
+	return args.Error(0)
+}
+
+func (m *MockStatusRepository) GetAll() ([]models.Status, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Status), args.Error(1)
+}
+
+func (m *MockStatusRepository) GetByID(id uint) (*models.Status, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Status), args.Error(1)
+}
+
+func (m *MockStatusRepository) Update(status *models.Status) error {
+	args := m.Called(status)
+	return args.Error(0)
+}
+
+func (m *MockStatusRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockStatusRepository) Count() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStatusRepository) MaxPosition() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStatusRepository) FirstByDone(done bool) (*models.Status, error) {
+	args := m.Called(done)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Status), args.Error(1)
+}
+
+func (m *MockStatusRepository) CountTodos(id uint) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStatusRepository) AssignMissing(done bool, statusID uint) error {
+	args := m.Called(done, statusID)
+	return args.Error(0)
+}
+
+func TestStatusService_Create(t *testing.T) {
+	mockRepo := new(MockStatusRepository)
+	service := services.NewStatusService(mockRepo, new(MockTodoRepository), nil)
+
+	t.Run("appends to the right of the board", func(t *testing.T) {
+		mockRepo.On("MaxPosition").Return(3, nil).Once()
+		mockRepo.On("Create", mock.AnythingOfType("*models.Status")).Return(nil).Once()
+
+		status, err := service.Create(models.CreateStatusRequest{Name: "Blocked"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, status.Position)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestStatusService_Update(t *testing.T) {
+	t.Run("done flag change syncs todo completion", func(t *testing.T) {
+		mockRepo := new(MockStatusRepository)
+		mockTodoRepo := new(MockTodoRepository)
+		todos := services.NewTodoService(mockTodoRepo, new(MockCategoryRepository), mockRepo, nil, false)
+		service := services.NewStatusService(mockRepo, mockTodoRepo, todos)
+		limit := 0
+		done := true
+		existing := &models.Status{ID: 2, Name: "Review", WIPLimit: new(int)}
+
+		mockRepo.On("GetByID", uint(2)).Return(existing, nil).Once()
+		mockTodoRepo.On("UpdateStatus", existing).Return(nil).Once()
+		mockTodoRepo.On("FindIDs", mock.MatchedBy(func(f models.TodoFilter) bool {
+			return *f.StatusID == 2 && !*f.Completed
+		})).Return([]uint{7}, nil).Once()
+		mockTodoRepo.On("GetByID", uint(7)).Return(&models.Todo{ID: 7, StatusID: &existing.ID}, nil).Once()
+		mockTodoRepo.On("Update", mock.MatchedBy(func(todo *models.Todo) bool {
+			return todo.ID == 7 && todo.Completed
+		})).Return(nil).Once()
+
+		status, err := service.Update(2, models.UpdateStatusRequest{IsDone: &done, WIPLimit: &limit})
+
+		assert.NoError(t, err)
+		assert.True(t, status.IsDone)
+		assert.Nil(t, status.WIPLimit)
+		mockRepo.AssertExpectations(t)
+		mockTodoRepo.AssertExpectations(t)
+	})
+
+	t.Run("completing a column repeats recurring todos", func(t *testing.T) {
+		mockRepo := new(MockStatusRepository)
+		mockTodoRepo := new(MockTodoRepository)
+		todos := services.NewTodoService(mockTodoRepo, new(MockCategoryRepository), mockRepo, nil, false)
+		service := services.NewStatusService(mockRepo, mockTodoRepo, todos)
+		done := true
+		existing := &models.Status{ID: 2, Name: "Review"}
+		open := &models.Status{ID: 1, Name: "To Do"}
+		due := date(2026, 10, 1)
+
+		mockRepo.On("GetByID", uint(2)).Return(existing, nil).Once()
+		mockTodoRepo.On("UpdateStatus", existing).Return(nil).Once()
+		mockTodoRepo.On("FindIDs", mock.Anything).Return([]uint{7}, nil).Once()
+		mockTodoRepo.On("GetByID", uint(7)).Return(&models.Todo{ID: 7, Title: "Water plants", DueDate: due, Recurrence: "FREQ=WEEKLY", StatusID: &existing.ID}, nil).Once()
+		mockTodoRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+		mockRepo.On("FirstByDone", false).Return(open, nil).Once()
+		mockTodoRepo.On("MaxPosition").Return("", nil).Once()
+		mockTodoRepo.On("LockPositions").Return(nil)
+		mockTodoRepo.On("Create", mock.MatchedBy(func(todo *models.Todo) bool {
+			return todo.Title == "Water plants" && !todo.Completed && *todo.StatusID == open.ID && todo.DueDate.After(*due)
+		})).Return(nil).Once()
+		mockTodoRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil).Once()
+
+		_, err := service.Update(2, models.UpdateStatusRequest{IsDone: &done})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockTodoRepo.AssertExpectations(t)
+	})
+
+	t.Run("completing a column with a blocked todo is refused", func(t *testing.T) {
+		mockRepo := new(MockStatusRepository)
+		mockTodoRepo := new(MockTodoRepository)
+		todos := services.NewTodoService(mockTodoRepo, new(MockCategoryRepository), mockRepo, nil, true)
+		service := services.NewStatusService(mockRepo, mockTodoRepo, todos)
+		done := true
+		existing := &models.Status{ID: 2, Name: "Review"}
+
+		mockRepo.On("GetByID", uint(2)).Return(existing, nil).Once()
+		mockTodoRepo.On("UpdateStatus", existing).Return(nil).Once()
+		mockTodoRepo.On("FindIDs", mock.Anything).Return([]uint{7, 8}, nil).Once()
+		mockTodoRepo.On("GetByID", uint(7)).Return(&models.Todo{ID: 7}, nil).Once()
+		mockTodoRepo.On("GetByID", uint(8)).Return(&models.Todo{ID: 8}, nil).Once()
+		// 7 waits for 8 in the same column; 8 waits for a todo elsewhere
+		mockTodoRepo.On("OpenBlockerCount", uint(7)).Return(int64(1), nil).Once()
+		mockTodoRepo.On("OpenBlockerCount", uint(8)).Return(int64(1), nil).Once()
+
+		_, err := service.Update(2, models.UpdateStatusRequest{IsDone: &done})
+
+		assert.ErrorIs(t, err, services.ErrTodoBlocked)
+		mockTodoRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestStatusService_Delete(t *testing.T) {
+	mockRepo := new(MockStatusRepository)
+	service := services.NewStatusService(mockRepo, new(MockTodoRepository), nil)
+
+	t.Run("refuses a status with todos", func(t *testing.T) {
+		mockRepo.On("GetByID", uint(1)).Return(&models.Status{ID: 1}, nil).Once()
+		mockRepo.On("CountTodos", uint(1)).Return(int64(2), nil).Once()
+
+		err := service.Delete(1)
+
+		assert.ErrorIs(t, err, services.ErrStatusInUse)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestStatusService_Board(t *testing.T) {
+	mockRepo := new(MockStatusRepository)
+	mockTodoRepo := new(MockTodoRepository)
+	service := services.NewStatusService(mockRepo, mockTodoRepo, nil)
+
+	t.Run("groups todos by status in manual order", func(t *testing.T) {
+		statuses := []models.Status{{ID: 1, Name: "To Do"}, {ID: 2, Name: "Done", IsDone: true}}
+
+		mockRepo.On("GetAll").Return(statuses, nil).Once()
+		mockTodoRepo.On("GetAll", mock.MatchedBy(func(f models.TodoFilter) bool {
+			return f.StatusID != nil && *f.StatusID == 1 && f.SortBy == "position" && f.Limit == 0
+		})).Return([]models.Todo{{ID: 5}, {ID: 6}}, int64(2), nil).Once()
+		mockTodoRepo.On("GetAll", mock.MatchedBy(func(f models.TodoFilter) bool {
+			return f.StatusID != nil && *f.StatusID == 2
+		})).Return([]models.Todo(nil), int64(0), nil).Once()
+
+		columns, err := service.Board(models.TodoFilter{Page: 3, Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Len(t, columns, 2)
+		assert.Equal(t, 2, columns[0].Count)
+		assert.NotNil(t, columns[1].Todos)
+		mockRepo.AssertExpectations(t)
+		mockTodoRepo.AssertExpectations(t)
+	})
+}
+
+func TestStatusService_EnsureDefaults(t *testing.T) {
+	mockRepo := new(MockStatusRepository)
+	service := services.NewStatusService(mockRepo, new(MockTodoRepository), nil)
+
+	t.Run("seeds an empty board and assigns todos", func(t *testing.T) {
+		open := &models.Status{ID: 1}
+		done := &models.Status{ID: 4, IsDone: true}
+
+		mockRepo.On("Count").Return(int64(0), nil).Once()
+		mockRepo.On("Create", mock.AnythingOfType("*models.Status")).Return(nil).Times(4)
+		mockRepo.On("FirstByDone", false).Return(open, nil).Once()
+		mockRepo.On("FirstByDone", true).Return(done, nil).Once()
+		mockRepo.On("AssignMissing", false, uint(1)).Return(nil).Once()
+		mockRepo.On("AssignMissing", true, uint(4)).Return(nil).Once()
+
+		err := service.EnsureDefaults()
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTodoService_StatusWorkflow(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	mockStatusRepo := new(MockStatusRepository)
+	service := services.NewTodoService(mockRepo, new(MockCategoryRepository), mockStatusRepo, nil, false)
+
+	inProgress := &models.Status{ID: 2, Name: "In Progress"}
+	done := &models.Status{ID: 4, Name: "Done", IsDone: true}
+
+	t.Run("completing moves the todo to the first done status", func(t *testing.T) {
+		existing := &models.Todo{ID: 1, StatusID: &inProgress.ID, Status: inProgress}
+
+		mockRepo.On("GetByID", uint(1)).Return(existing, nil).Once()
+		mockStatusRepo.On("FirstByDone", true).Return(done, nil).Once()
+		mockRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+
+		todo, err := service.ToggleComplete(1)
+
+		assert.NoError(t, err)
+		assert.True(t, todo.Completed)
+		assert.Equal(t, done.ID, *todo.StatusID)
+		mockRepo.AssertExpectations(t)
+		mockStatusRepo.AssertExpectations(t)
+	})
+
+	t.Run("choosing a done status completes the todo", func(t *testing.T) {
+		existing := &models.Todo{ID: 2, StatusID: &inProgress.ID, Status: inProgress}
+
+		mockRepo.On("GetByID", uint(2)).Return(existing, nil).Times(2)
+		mockStatusRepo.On("GetByID", uint(4)).Return(done, nil).Once()
+		mockRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+
+		todo, err := service.Update(2, models.UpdateTodoRequest{Title: "Ship", StatusID: &done.ID}, nil)
+
+		assert.NoError(t, err)
+		assert.True(t, todo.Completed)
+		mockRepo.AssertExpectations(t)
+		mockStatusRepo.AssertExpectations(t)
+	})
+
+	t.Run("entering a full status is refused", func(t *testing.T) {
+		limit := 1
+		review := &models.Status{ID: 3, Name: "Review", WIPLimit: &limit}
+		existing := &models.Todo{ID: 3, StatusID: &inProgress.ID, Status: inProgress}
+
+		mockRepo.On("GetByID", uint(3)).Return(existing, nil).Once()
+		mockStatusRepo.On("GetByID", uint(3)).Return(review, nil).Once()
+		mockRepo.On("CountInStatusForUpdate", uint(3)).Return(int64(1), nil).Once()
+
+		todo, err := service.Update(3, models.UpdateTodoRequest{Title: "Ship", StatusID: &review.ID}, nil)
+
+		assert.Nil(t, todo)
+		assert.ErrorIs(t, err, services.ErrWIPLimitReached)
+		mockRepo.AssertExpectations(t)
+		mockStatusRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a minimal stand-in for an S3-compatible server that keeps
+// objects in memory and requires signed requests
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-key/") {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.objects[r.URL.Path]
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+	case http.MethodHead:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	case http.MethodGet:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		start := 0
+		if rng := r.Header.Get("Range"); rng != "" {
+			start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		w.Write(data[start:])
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newStorages(t *testing.T) map[string]storage.Storage {
+	local, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	server := httptest.NewServer(&fakeS3{objects: map[string][]byte{}})
+	t.Cleanup(server.Close)
+	s3, err := storage.NewS3Storage(storage.S3Config{
+		Endpoint:        server.URL,
+		Bucket:          "attachments",
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "secret",
+	}, server.Client())
+	require.NoError(t, err)
+
+	return map[string]storage.Storage{"local": local, "s3": s3}
+}
+
+func TestStorage_Backends(t *testing.T) {
+	ctx := context.Background()
+	content := "hello attachment storage"
+
+	for name, store := range newStorages(t) {
+		t.Run(name, func(t *testing.T) {
+			key := "todos/1/abc"
+			require.NoError(t, store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"))
+
+			object, err := store.Open(ctx, key)
+			require.NoError(t, err)
+			data, err := io.ReadAll(object)
+			require.NoError(t, err)
+			assert.Equal(t, content, string(data))
+
+			// Seeking serves byte ranges
+			_, err = object.Seek(6, io.SeekStart)
+			require.NoError(t, err)
+			part := make([]byte, 10)
+			_, err = io.ReadFull(object, part)
+			require.NoError(t, err)
+			assert.Equal(t, "attachment", string(part))
+
+			size, err := object.Seek(0, io.SeekEnd)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(content)), size)
+			require.NoError(t, object.Close())
+
+			require.NoError(t, store.Delete(ctx, key))
+			_, err = store.Open(ctx, key)
+			assert.ErrorIs(t, err, storage.ErrNotFound)
+
+			// Deleting twice is not an error
+			assert.NoError(t, store.Delete(ctx, key))
+		})
+	}
+}
+
+func TestStorage_ServesRanges(t *testing.T) {
+	ctx := context.Background()
+	content := "0123456789"
+
+	for name, store := range newStorages(t) {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, store.Put(ctx, "k", strings.NewReader(content), int64(len(content)), ""))
+			object, err := store.Open(ctx, "k")
+			require.NoError(t, err)
+			defer object.Close()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Range", "bytes=2-5")
+			rec := httptest.NewRecorder()
+			http.ServeContent(rec, req, "k", time.Time{}, object)
+
+			assert.Equal(t, http.StatusPartialContent, rec.Code)
+			assert.Equal(t, "2345", rec.Body.String())
+			assert.Equal(t, "bytes 2-5/10", rec.Header().Get("Content-Range"))
+		})
+	}
+}
+
+func TestLocalStorage_RejectsEscapingKeys(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	err = store.Put(context.Background(), "../outside", strings.NewReader("x"), 1, "")
+	assert.Error(t, err)
+}
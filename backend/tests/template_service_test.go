@@ -0,0 +1,233 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTemplateRepository is a mock implementation of TemplateRepository
+type MockTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockTemplateRepository) Create(template *models.Template) error {
+	args := m.Called(template)
+	template.ID = 1
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) GetAll() ([]models.Template, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepository) GetByID(id uint) (*models.Template, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepository) Update(template *models.Template) error {
+	args := m.Called(template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type templateMocks struct {
+	repo          *MockTemplateRepository
+	todoRepo      *MockTodoRepository
+	categoryRepo  *MockCategoryRepository
+	checklistRepo *MockChecklistRepository
+}
+
+func newTemplateService() (templateMocks, services.TemplateService) {
+	m := templateMocks{
+		repo:          new(MockTemplateRepository),
+		todoRepo:      new(MockTodoRepository),
+		categoryRepo:  new(MockCategoryRepository),
+		checklistRepo: new(MockChecklistRepository),
+	}
+	todos := newTodoService(m.todoRepo, m.categoryRepo)
+	return m, services.NewTemplateService(m.repo, m.todoRepo, m.categoryRepo, m.checklistRepo, todos)
+}
+
+func TestTemplateService_Create(t *testing.T) {
+	t.Run("stores trimmed items", func(t *testing.T) {
+		m, service := newTemplateService()
+		m.repo.On("Create", mock.AnythingOfType("*models.Template")).Return(nil).Once()
+
+		template, err := service.Create(models.CreateTemplateRequest{
+			Name: " Release ",
+			Items: models.TemplateItems{
+				{Title: " Tag {{version}} ", Tags: []string{"release ", "release"}, Subtasks: []string{" Build "}},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Release", template.Name)
+		assert.Equal(t, "Tag {{version}}", template.Items[0].Title)
+		assert.Equal(t, []string{"release"}, template.Items[0].Tags)
+		assert.Equal(t, []string{"Build"}, template.Items[0].Subtasks)
+		m.repo.AssertExpectations(t)
+	})
+
+	t.Run("reports invalid items by index", func(t *testing.T) {
+		m, service := newTemplateService()
+		categoryID := uint(9)
+		offset := 5000
+		m.categoryRepo.On("GetByID", categoryID).Return(nil, errors.New("record not found")).Once()
+
+		_, err := service.Create(models.CreateTemplateRequest{
+			Name: "Onboarding",
+			Items: models.TemplateItems{
+				{Title: " "},
+				{Title: "Laptop", Priority: "urgent", CategoryID: &categoryID, DueOffsetDays: &offset, Subtasks: []string{""}},
+			},
+		})
+
+		assert.ElementsMatch(t, []string{
+			"items[0].title:required",
+			"items[1].priority:oneof",
+			"items[1].category_id:exists",
+			"items[1].due_offset_days:range",
+			"items[1].subtasks[0]:required",
+		}, fieldRules(t, err))
+		m.repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("requires at least one item", func(t *testing.T) {
+		_, service := newTemplateService()
+
+		_, err := service.Create(models.CreateTemplateRequest{Name: "Empty"})
+
+		assert.ErrorIs(t, err, services.ErrTemplateItemsRequired)
+	})
+}
+
+func TestTemplateService_Instantiate(t *testing.T) {
+	twoDays, minusOne := 2, -1
+	template := &models.Template{
+		ID:   4,
+		Name: "Onboarding",
+		Items: models.TemplateItems{
+			{Title: "Welcome {{ name }}", DueOffsetDays: &minusOne, Tags: []string{"hr"}},
+			{Title: "Laptop for {{name}} ({{date}})", Priority: models.PriorityHigh, DueOffsetDays: &twoDays, Subtasks: []string{"Order {{name}}'s laptop", "Install tools"}},
+		},
+	}
+
+	t.Run("creates every todo with variables and due offsets filled in", func(t *testing.T) {
+		m, service := newTemplateService()
+		m.repo.On("GetByID", uint(4)).Return(template, nil)
+		var created []models.Todo
+		m.todoRepo.On("MaxPosition").Return("", nil).Twice()
+		m.todoRepo.On("LockPositions").Return(nil)
+		m.todoRepo.On("Create", mock.AnythingOfType("*models.Todo")).Run(func(args mock.Arguments) {
+			created = append(created, *args.Get(0).(*models.Todo))
+		}).Return(nil).Twice()
+		m.todoRepo.On("AddTag", uint(1), "hr").Return(nil).Once()
+		m.todoRepo.On("AddChecklistItems", mock.MatchedBy(func(items []models.ChecklistItem) bool {
+			return len(items) == 2 && items[0].Title == "Order Budi's laptop"
+		})).Return(nil).Once()
+		m.todoRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil).Twice()
+
+		todos, err := service.Instantiate(4, models.InstantiateTemplateRequest{
+			Variables: map[string]string{"name": "Budi"},
+			BaseDate:  "2026-11-02",
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, todos, 2)
+		require.Len(t, created, 2)
+		assert.Equal(t, "Welcome Budi", created[0].Title)
+		assert.Equal(t, time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC), *created[0].DueDate)
+		assert.Equal(t, "Laptop for Budi (2026-11-02)", created[1].Title)
+		assert.Equal(t, models.PriorityHigh, created[1].Priority)
+		assert.Equal(t, time.Date(2026, time.November, 4, 0, 0, 0, 0, time.UTC), *created[1].DueDate)
+		m.todoRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects missing variables before creating anything", func(t *testing.T) {
+		m, service := newTemplateService()
+		m.repo.On("GetByID", uint(4)).Return(template, nil)
+
+		_, err := service.Instantiate(4, models.InstantiateTemplateRequest{BaseDate: "02/11/2026"})
+
+		assert.Equal(t, []string{"base_date:format", "variables.name:required"}, fieldRules(t, err))
+		m.todoRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("names the todo that fails validation", func(t *testing.T) {
+		m, service := newTemplateService()
+		m.repo.On("GetByID", uint(4)).Return(template, nil)
+		m.todoRepo.On("MaxPosition").Return("", nil).Once()
+		m.todoRepo.On("LockPositions").Return(nil)
+		m.todoRepo.On("Create", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+		m.todoRepo.On("AddTag", uint(1), "hr").Return(nil).Once()
+		m.todoRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil).Once()
+
+		long := make([]byte, 240)
+		for i := range long {
+			long[i] = 'x'
+		}
+		_, err := service.Instantiate(4, models.InstantiateTemplateRequest{
+			Variables: map[string]string{"name": string(long)},
+			BaseDate:  "2026-11-02",
+		})
+
+		assert.Equal(t, []string{"todos[1].title:max"}, fieldRules(t, err))
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		m, service := newTemplateService()
+		m.repo.On("GetByID", uint(99)).Return(nil, errors.New("record not found"))
+
+		_, err := service.Instantiate(99, models.InstantiateTemplateRequest{})
+
+		assert.Equal(t, services.ErrTemplateNotFound, err)
+	})
+}
+
+func TestTemplateService_SaveTodo(t *testing.T) {
+	m, service := newTemplateService()
+	categoryID := uint(3)
+	created := time.Date(2026, time.October, 5, 16, 30, 0, 0, time.UTC)
+	due := time.Date(2026, time.October, 9, 9, 0, 0, 0, time.UTC)
+	m.todoRepo.On("GetByID", uint(7)).Return(&models.Todo{
+		ID:          7,
+		Title:       "Release checklist",
+		Description: "Cut the release branch",
+		Priority:    models.PriorityHigh,
+		CategoryID:  &categoryID,
+		DueDate:     &due,
+		CreatedAt:   created,
+		Tags:        []models.Tag{{Name: "release"}},
+	}, nil)
+	m.categoryRepo.On("GetByID", categoryID).Return(&models.Category{ID: categoryID}, nil)
+	m.checklistRepo.On("GetByTodo", uint(7)).Return([]models.ChecklistItem{{Title: "Build"}, {Title: "Publish"}}, nil)
+	m.repo.On("Create", mock.AnythingOfType("*models.Template")).Return(nil).Once()
+
+	template, err := service.SaveTodo(7, models.SaveAsTemplateRequest{Name: "Release"})
+
+	require.NoError(t, err)
+	require.Len(t, template.Items, 1)
+	item := template.Items[0]
+	assert.Equal(t, "Release checklist", item.Title)
+	assert.Equal(t, models.PriorityHigh, item.Priority)
+	assert.Equal(t, &categoryID, item.CategoryID)
+	assert.Equal(t, 4, *item.DueOffsetDays)
+	assert.Equal(t, []string{"release"}, item.Tags)
+	assert.Equal(t, []string{"Build", "Publish"}, item.Subtasks)
+}
@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTimeEntryRepository is a mock implementation of TimeEntryRepository
+type MockTimeEntryRepository struct {
+	mock.Mock
+}
+
+func (m *MockTimeEntryRepository) Create(entry *models.TimeEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockTimeEntryRepository) StartTimer(entry *models.TimeEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockTimeEntryRepository) StopTimer(entry *models.TimeEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockTimeEntryRepository) Running(user string) (*models.TimeEntry, error) {
+	args := m.Called(user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) GetByID(id uint) (*models.TimeEntry, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) GetByTodo(todoID uint) ([]models.TimeEntry, error) {
+	args := m.Called(todoID)
+	return args.Get(0).([]models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTimeEntryRepository) Report(filter models.TimeReportFilter) ([]models.TimeReportRow, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.TimeReportRow), args.Error(1)
+}
+
+func TestTimeTrackingService_Timer(t *testing.T) {
+	todoRepo := new(MockTodoRepository)
+	todoRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+
+	t.Run("starts a timer", func(t *testing.T) {
+		repo := new(MockTimeEntryRepository)
+		service := services.NewTimeTrackingService(repo, todoRepo)
+		repo.On("StartTimer", mock.AnythingOfType("*models.TimeEntry")).Return(nil).Once()
+
+		entry, err := service.Start(1, "alice", models.StartTimerRequest{Note: "review"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "alice", entry.UserName)
+		assert.Nil(t, entry.EndedAt)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("refuses a second running timer", func(t *testing.T) {
+		repo := new(MockTimeEntryRepository)
+		service := services.NewTimeTrackingService(repo, todoRepo)
+		repo.On("StartTimer", mock.AnythingOfType("*models.TimeEntry")).Return(repository.ErrTimerRunning).Once()
+
+		_, err := service.Start(1, "alice", models.StartTimerRequest{})
+
+		assert.Equal(t, services.ErrTimerRunning, err)
+	})
+
+	t.Run("stopping records the duration", func(t *testing.T) {
+		repo := new(MockTimeEntryRepository)
+		service := services.NewTimeTrackingService(repo, todoRepo)
+		running := &models.TimeEntry{ID: 3, TodoID: 1, UserName: "alice", StartedAt: time.Now().Add(-90 * time.Second)}
+		repo.On("Running", "alice").Return(running, nil).Once()
+		repo.On("StopTimer", running).Return(nil).Once()
+
+		entry, err := service.Stop(1, "alice")
+
+		require.NoError(t, err)
+		require.NotNil(t, entry.EndedAt)
+		assert.InDelta(t, 90, entry.DurationSeconds, 2)
+	})
+
+	t.Run("stopping a timer running on another todo fails", func(t *testing.T) {
+		repo := new(MockTimeEntryRepository)
+		service := services.NewTimeTrackingService(repo, todoRepo)
+		repo.On("Running", "alice").Return(&models.TimeEntry{ID: 3, TodoID: 2}, nil).Once()
+
+		_, err := service.Stop(1, "alice")
+
+		assert.Equal(t, services.ErrTimerNotRunning, err)
+		repo.AssertNotCalled(t, "StopTimer", mock.Anything)
+	})
+
+	t.Run("no running timer is not an error, a failing lookup is", func(t *testing.T) {
+		repo := new(MockTimeEntryRepository)
+		service := services.NewTimeTrackingService(repo, todoRepo)
+		repo.On("Running", "alice").Return(nil, nil).Once()
+		repo.On("Running", "bob").Return(nil, assert.AnError).Twice()
+
+		entry, err := service.Running("alice")
+		assert.NoError(t, err)
+		assert.Nil(t, entry)
+
+		_, err = service.Running("bob")
+		assert.ErrorIs(t, err, assert.AnError)
+		_, err = service.Stop(1, "bob")
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestTimeTrackingService_AddEntry(t *testing.T) {
+	todoRepo := new(MockTodoRepository)
+	todoRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+	start := time.Now().Add(-3 * time.Hour)
+
+	t.Run("records a manual entry", func(t *testing.T) {
+		repo := new(MockTimeEntryRepository)
+		service := services.NewTimeTrackingService(repo, todoRepo)
+		repo.On("Create", mock.AnythingOfType("*models.TimeEntry")).Return(nil).Once()
+
+		entry, err := service.AddEntry(1, "alice", models.CreateTimeEntryRequest{StartedAt: start, EndedAt: start.Add(45 * time.Minute)})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(45*60), entry.DurationSeconds)
+	})
+
+	t.Run("rejects invalid spans", func(t *testing.T) {
+		repo := new(MockTimeEntryRepository)
+		service := services.NewTimeTrackingService(repo, todoRepo)
+
+		_, err := service.AddEntry(1, "alice", models.CreateTimeEntryRequest{StartedAt: start, EndedAt: start})
+		assert.ErrorIs(t, err, services.ErrTimeEntryOrder)
+
+		_, err = service.AddEntry(1, "alice", models.CreateTimeEntryRequest{StartedAt: start.Add(-48 * time.Hour), EndedAt: start})
+		assert.ErrorIs(t, err, services.ErrTimeEntryTooLong)
+
+		_, err = service.AddEntry(1, "alice", models.CreateTimeEntryRequest{StartedAt: start, EndedAt: time.Now().Add(time.Hour)})
+		assert.ErrorIs(t, err, services.ErrTimeEntryInFuture)
+
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestTimeTrackingService_Report(t *testing.T) {
+	repo := new(MockTimeEntryRepository)
+	service := services.NewTimeTrackingService(repo, new(MockTodoRepository))
+
+	t.Run("defaults to per todo totals", func(t *testing.T) {
+		repo.On("Report", models.TimeReportFilter{GroupBy: models.TimeReportByTodo}).Return([]models.TimeReportRow{
+			{Key: "1", Label: "Write docs", TotalSeconds: 3600},
+			{Key: "2", Label: "Review", TotalSeconds: 1800},
+		}, nil).Once()
+
+		report, err := service.Report(models.TimeReportFilter{})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(5400), report.TotalSeconds)
+		assert.Len(t, report.Rows, 2)
+	})
+
+	t.Run("rejects unknown groups and empty ranges", func(t *testing.T) {
+		from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+		_, err := service.Report(models.TimeReportFilter{GroupBy: "week"})
+		assert.ErrorIs(t, err, services.ErrInvalidReportGroup)
+
+		_, err = service.Report(models.TimeReportFilter{From: &from, To: &from})
+		assert.ErrorIs(t, err, services.ErrInvalidReportRange)
+
+		_, err = service.Report(models.TimeReportFilter{GroupBy: models.TimeReportByDay, TimeZone: "Mars/Olympus"})
+		assert.Equal(t, []string{"time_zone:timezone"}, fieldRules(t, err))
+	})
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTodoService_AddBlocker(t *testing.T) {
+	t.Run("links an existing todo", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+		mockRepo.On("GetByID", uint(2)).Return(&models.Todo{ID: 2}, nil)
+		mockRepo.On("LockDependencies").Return(nil).Once()
+		mockRepo.On("BlockerIDs", []uint{2}).Return([]uint{}, nil).Once()
+		mockRepo.On("AddBlocker", uint(1), uint(2)).Return(nil).Once()
+		mockRepo.On("Blockers", uint(1)).Return([]models.Todo{{ID: 2}}, nil).Once()
+
+		blockers, err := service.AddBlocker(1, 2)
+
+		assert.NoError(t, err)
+		assert.Len(t, blockers, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a todo blocking itself", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+
+		_, err := service.AddBlocker(1, 1)
+
+		assert.ErrorIs(t, err, services.ErrBlockerSelf)
+		mockRepo.AssertNotCalled(t, "AddBlocker", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a missing blocker", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+		mockRepo.On("GetByID", uint(9)).Return(nil, assert.AnError)
+
+		_, err := service.AddBlocker(1, 9)
+
+		assert.ErrorIs(t, err, services.ErrBlockerNotFound)
+	})
+
+	t.Run("rejects a transitive cycle", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+
+		// 3 is blocked by 2, which is blocked by 1; 1 blocked by 3 closes the loop
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+		mockRepo.On("GetByID", uint(3)).Return(&models.Todo{ID: 3}, nil)
+		mockRepo.On("LockDependencies").Return(nil).Once()
+		mockRepo.On("BlockerIDs", []uint{3}).Return([]uint{2}, nil).Once()
+		mockRepo.On("BlockerIDs", []uint{2}).Return([]uint{1}, nil).Once()
+
+		_, err := service.AddBlocker(1, 3)
+
+		assert.ErrorIs(t, err, services.ErrDependencyCycle)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "AddBlocker", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTodoService_RemoveBlocker(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
+
+	mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+	mockRepo.On("BlockerIDs", []uint{1}).Return([]uint{2}, nil)
+
+	t.Run("unlinks an existing blocker", func(t *testing.T) {
+		mockRepo.On("RemoveBlocker", uint(1), uint(2)).Return(nil).Once()
+
+		err := service.RemoveBlocker(1, 2)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("reports an unknown link", func(t *testing.T) {
+		err := service.RemoveBlocker(1, 5)
+
+		assert.ErrorIs(t, err, services.ErrDependencyAbsent)
+	})
+}
+
+func TestTodoService_EnforceBlockers(t *testing.T) {
+	newEnforcingService := func(repo *MockTodoRepository) services.TodoService {
+		statusRepo := new(MockStatusRepository)
+		statusRepo.On("FirstByDone", mock.Anything).Return(nil, services.ErrStatusNotFound).Maybe()
+		return services.NewTodoService(repo, new(MockCategoryRepository), statusRepo, nil, true)
+	}
+
+	t.Run("refuses to complete a blocked todo", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newEnforcingService(mockRepo)
+
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil).Once()
+		mockRepo.On("OpenBlockerCount", uint(1)).Return(int64(1), nil).Once()
+
+		_, err := service.ToggleComplete(1)
+
+		assert.ErrorIs(t, err, services.ErrTodoBlocked)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("completes once blockers are done", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newEnforcingService(mockRepo)
+
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil).Once()
+		mockRepo.On("OpenBlockerCount", uint(1)).Return(int64(0), nil).Once()
+		mockRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+
+		todo, err := service.ToggleComplete(1)
+
+		assert.NoError(t, err)
+		assert.True(t, todo.Completed)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("reopening ignores blockers", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newEnforcingService(mockRepo)
+
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1, Completed: true}, nil).Once()
+		mockRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+
+		todo, err := service.ToggleComplete(1)
+
+		assert.NoError(t, err)
+		assert.False(t, todo.Completed)
+		mockRepo.AssertNotCalled(t, "OpenBlockerCount", mock.Anything)
+	})
+}
@@ -0,0 +1,246 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// externalImport records what an import of another app's file creates
+type externalImport struct {
+	service   services.TodoService
+	repo      *MockTodoRepository
+	created   []models.Todo
+	tags      []string
+	checklist [][]models.ChecklistItem
+}
+
+// newExternalImport creates the given categories, numbered from 11
+func newExternalImport(categories ...string) *externalImport {
+	imp := &externalImport{repo: new(MockTodoRepository)}
+	categoryRepo := new(MockCategoryRepository)
+	imp.service = newTodoService(imp.repo, categoryRepo)
+
+	for i, name := range categories {
+		imp.repo.On("FindOrCreateCategory", name).Return(&models.Category{ID: uint(11 + i), Name: name}, true, nil).Once()
+	}
+	imp.repo.On("MaxPosition").Return("", nil)
+	imp.repo.On("LockPositions").Return(nil)
+	imp.repo.On("Create", mock.AnythingOfType("*models.Todo")).Run(func(args mock.Arguments) {
+		imp.created = append(imp.created, *args.Get(0).(*models.Todo))
+	}).Return(nil)
+	imp.repo.On("AddTag", uint(1), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		imp.tags = append(imp.tags, args.String(1))
+	}).Return(nil)
+	imp.repo.On("AddChecklistItems", mock.Anything).Run(func(args mock.Arguments) {
+		imp.checklist = append(imp.checklist, args.Get(0).([]models.ChecklistItem))
+	}).Return(nil)
+	imp.repo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+	return imp
+}
+
+func checklistState(items []models.ChecklistItem) map[string]bool {
+	state := make(map[string]bool, len(items))
+	for _, item := range items {
+		state[item.Title] = item.Checked
+	}
+	return state
+}
+
+func TestTodoService_ImportExternal_Todoist(t *testing.T) {
+	t.Run("reads a project CSV with subtasks, notes and labels", func(t *testing.T) {
+		imp := newExternalImport("Personal")
+		data := "\ufeffTYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\n" +
+			"section,Errands,,,,,,,,\n" +
+			"task,Renew passport @paperwork,Bring photos,1,1,Ana,,2026-10-20,en,UTC\n" +
+			"task,Book appointment,,4,2,Ana,,,en,UTC\n" +
+			"note,Office opens at 8,,,,Ana,,,,\n" +
+			",,,,,,,,,\n" +
+			"task,Buy stamps,,4,1,Ana,,,en,UTC\n"
+
+		report, err := imp.service.ImportExternal(models.ExternalImportRequest{
+			Source:   models.ImportSourceTodoist,
+			Data:     []byte(data),
+			Category: "Personal",
+		})
+
+		require.NoError(t, err)
+		assert.True(t, report.Committed)
+		require.Len(t, report.Rows, 2)
+		assert.Equal(t, []int{3, 7}, []int{report.Rows[0].Row, report.Rows[1].Row})
+		assert.Equal(t, "Renew passport", report.Rows[0].Title)
+		assert.Equal(t, "Personal", report.Rows[0].Category)
+		assert.Equal(t, []string{"Personal"}, report.CreatedCategories)
+
+		require.Len(t, imp.created, 2)
+		assert.Equal(t, models.PriorityHigh, imp.created[0].Priority)
+		assert.Equal(t, "Bring photos\n\nOffice opens at 8", imp.created[0].Description)
+		assert.True(t, date(2026, 10, 20).Equal(*imp.created[0].DueDate))
+		assert.Equal(t, models.PriorityMedium, imp.created[1].Priority)
+		assert.Equal(t, []string{"paperwork"}, imp.tags)
+		require.Len(t, imp.checklist, 1)
+		assert.Equal(t, map[string]bool{"Book appointment": false}, checklistState(imp.checklist[0]))
+	})
+
+	t.Run("reports dates it cannot read", func(t *testing.T) {
+		imp := newExternalImport()
+		data := "TYPE,CONTENT,PRIORITY,INDENT,DATE\ntask,Stretch,9,1,every day\n"
+
+		report, err := imp.service.ImportExternal(models.ExternalImportRequest{Source: models.ImportSourceTodoist, Data: []byte(data)})
+
+		require.NoError(t, err)
+		assert.False(t, report.Committed)
+		assert.ElementsMatch(t, []models.ImportError{
+			{Field: "priority", Message: services.ErrTodoistInvalidPriority.Error()},
+			{Field: "due_date", Message: services.ErrImportUnsupportedDate.Error()},
+		}, report.Rows[0].Errors)
+	})
+
+	t.Run("reads a JSON backup with nested subtasks", func(t *testing.T) {
+		imp := newExternalImport("Work", "Home")
+		data := `{
+			"projects": [{"id": "220", "name": "Work"}, {"id": 221, "name": "Home"}],
+			"items": [
+				{"id": "1", "project_id": "220", "content": "Ship release", "priority": 4, "labels": ["q4"],
+				 "due": {"date": "2026-11-02T09:00:00", "is_recurring": false}},
+				{"id": "2", "project_id": "220", "parent_id": "1", "content": "Tag build", "checked": true},
+				{"id": "3", "project_id": "220", "parent_id": "2", "content": "Write notes"},
+				{"id": 4, "project_id": 221, "parent_id": null, "content": "Water plants", "priority": 1, "checked": true}
+			]
+		}`
+
+		report, err := imp.service.ImportExternal(models.ExternalImportRequest{
+			Source: models.ImportSourceTodoist,
+			Data:   []byte(data),
+			DryRun: true,
+		})
+
+		require.NoError(t, err)
+		assert.False(t, report.Committed)
+		assert.Equal(t, 2, report.Valid)
+		assert.Equal(t, []int{1, 4}, []int{report.Rows[0].Row, report.Rows[1].Row})
+		assert.Equal(t, "Work", report.Rows[0].Category)
+		assert.Equal(t, "Home", report.Rows[1].Category)
+
+		require.Len(t, imp.created, 2)
+		assert.Equal(t, models.PriorityHigh, imp.created[0].Priority)
+		assert.True(t, time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC).Equal(*imp.created[0].DueDate))
+		assert.True(t, imp.created[1].Completed)
+		assert.Equal(t, models.PriorityMedium, imp.created[1].Priority)
+		assert.Equal(t, []string{"q4"}, imp.tags)
+		require.Len(t, imp.checklist, 1)
+		assert.Equal(t, map[string]bool{"Tag build": true, "Write notes": false}, checklistState(imp.checklist[0]))
+	})
+}
+
+func TestTodoService_ImportExternal_Trello(t *testing.T) {
+	imp := newExternalImport("Doing")
+	data := `{
+		"name": "Launch",
+		"lists": [
+			{"id": "l1", "name": "Doing", "closed": false},
+			{"id": "l2", "name": "Old ideas", "closed": true}
+		],
+		"cards": [
+			{"id": "c1", "idList": "l1", "name": "Design landing page", "desc": "Hero and pricing",
+			 "due": "2026-10-30T10:00:00.000Z", "dueComplete": true,
+			 "labels": [{"name": "web", "color": "green"}, {"name": "", "color": "red"}]},
+			{"id": "c2", "idList": "l1", "name": "Archived card", "closed": true},
+			{"id": "c3", "idList": "l2", "name": "On an archived list"},
+			{"id": "c4", "idList": "l1", "name": "Write copy", "due": null}
+		],
+		"checklists": [
+			{"id": "k2", "idCard": "c1", "pos": 2, "checkItems": [{"name": "Publish", "state": "incomplete", "pos": 1}]},
+			{"id": "k1", "idCard": "c1", "pos": 1, "checkItems": [
+				{"name": "Review", "state": "incomplete", "pos": 2},
+				{"name": "Sketch", "state": "complete", "pos": 1}
+			]}
+		]
+	}`
+
+	report, err := imp.service.ImportExternal(models.ExternalImportRequest{Source: models.ImportSourceTrello, Data: []byte(data)})
+
+	require.NoError(t, err)
+	assert.True(t, report.Committed)
+	require.Len(t, report.Rows, 2)
+	assert.Equal(t, []int{1, 4}, []int{report.Rows[0].Row, report.Rows[1].Row})
+	assert.Equal(t, []string{"Doing"}, report.CreatedCategories)
+
+	require.Len(t, imp.created, 2)
+	assert.Equal(t, "Hero and pricing", imp.created[0].Description)
+	assert.True(t, imp.created[0].Completed)
+	assert.True(t, time.Date(2026, 10, 30, 10, 0, 0, 0, time.UTC).Equal(*imp.created[0].DueDate))
+	assert.Nil(t, imp.created[1].DueDate)
+	assert.Equal(t, []string{"web", "red"}, imp.tags)
+
+	require.Len(t, imp.checklist, 1)
+	items := imp.checklist[0]
+	require.Len(t, items, 3)
+	assert.Equal(t, []string{"Sketch", "Review", "Publish"}, []string{items[0].Title, items[1].Title, items[2].Title})
+	assert.True(t, items[0].Checked)
+	assert.False(t, items[1].Checked)
+}
+
+func TestTodoService_ImportExternal_MicrosoftTodo(t *testing.T) {
+	imp := newExternalImport("Groceries", "Work")
+	data := `{
+		"lists": [
+			{"displayName": "Groceries", "tasks": [
+				{"title": "Buy milk", "importance": "high", "status": "notStarted",
+				 "body": {"contentType": "html", "content": "<html><head><style>p{}</style></head><body><p>Oat &amp; soy</p><p>2 liters</p></body></html>"},
+				 "dueDateTime": {"dateTime": "2026-10-20T00:00:00.0000000", "timeZone": "Asia/Jakarta"},
+				 "categories": ["Red category"],
+				 "checklistItems": [{"displayName": "Check fridge", "isChecked": true}]}
+			]},
+			{"displayName": "Work", "tasks": [
+				{"title": "Send invoice", "importance": "normal", "status": "completed",
+				 "body": {"contentType": "text", "content": "Net 30"},
+				 "dueDateTime": {"dateTime": "2026-10-21T00:00:00.0000000", "timeZone": "Pacific Standard Time"}}
+			]}
+		]
+	}`
+
+	report, err := imp.service.ImportExternal(models.ExternalImportRequest{Source: models.ImportSourceMicrosoftTodo, Data: []byte(data)})
+
+	require.NoError(t, err)
+	assert.True(t, report.Committed)
+	assert.Equal(t, []int{1, 2}, []int{report.Rows[0].Row, report.Rows[1].Row})
+	assert.Equal(t, []string{"Groceries", "Work"}, report.CreatedCategories)
+
+	require.Len(t, imp.created, 2)
+	assert.Equal(t, models.PriorityHigh, imp.created[0].Priority)
+	assert.Equal(t, "Oat & soy\n2 liters", imp.created[0].Description)
+	assert.True(t, time.Date(2026, 10, 19, 17, 0, 0, 0, time.UTC).Equal(*imp.created[0].DueDate))
+	assert.Equal(t, models.PriorityMedium, imp.created[1].Priority)
+	assert.True(t, imp.created[1].Completed)
+	assert.Equal(t, "Net 30", imp.created[1].Description)
+	assert.True(t, time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC).Equal(*imp.created[1].DueDate))
+	assert.Equal(t, []string{"Red category"}, imp.tags)
+	require.Len(t, imp.checklist, 1)
+	assert.Equal(t, map[string]bool{"Check fridge": true}, checklistState(imp.checklist[0]))
+}
+
+func TestTodoService_ImportExternal_Rejects(t *testing.T) {
+	service := newTodoService(new(MockTodoRepository), new(MockCategoryRepository))
+
+	_, err := service.ImportExternal(models.ExternalImportRequest{Source: "asana", Data: []byte("{}")})
+	assert.Equal(t, []string{"source:oneof"}, fieldRules(t, err))
+
+	_, err = service.ImportExternal(models.ExternalImportRequest{Source: models.ImportSourceTrello, Data: []byte("title\nBuy milk\n")})
+	assert.Equal(t, []string{"file:format"}, fieldRules(t, err))
+	assert.ErrorIs(t, err, services.ErrImportInvalidExport)
+
+	_, err = service.ImportExternal(models.ExternalImportRequest{Source: models.ImportSourceTrello, Data: []byte(`{"name": "Not a board"}`)})
+	assert.ErrorIs(t, err, services.ErrImportInvalidExport)
+
+	_, err = service.ImportExternal(models.ExternalImportRequest{Source: models.ImportSourceTodoist, Data: []byte("title,notes\nBuy milk,\n")})
+	assert.ErrorIs(t, err, services.ErrImportInvalidExport)
+
+	_, err = service.ImportExternal(models.ExternalImportRequest{Source: models.ImportSourceMicrosoftTodo, Data: []byte(`{"lists": []}`)})
+	assert.Equal(t, []string{"file:required"}, fieldRules(t, err))
+}
@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTodoService_Import(t *testing.T) {
+	t.Run("maps headers by name and creates missing categories once", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		categoryRepo := new(MockCategoryRepository)
+		service := newTodoService(mockRepo, categoryRepo)
+
+		work := &models.Category{ID: 7, Name: "Work"}
+		home := &models.Category{ID: 2, Name: "Home"}
+		mockRepo.On("FindOrCreateCategory", "Work").Return(work, true, nil).Once()
+		mockRepo.On("FindOrCreateCategory", "home").Return(home, false, nil).Once()
+		categoryRepo.On("GetByID", uint(2)).Return(home, nil)
+		var created []models.Todo
+		mockRepo.On("MaxPosition").Return("", nil)
+		mockRepo.On("LockPositions").Return(nil)
+		mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Run(func(args mock.Arguments) {
+			created = append(created, *args.Get(0).(*models.Todo))
+		}).Return(nil)
+		mockRepo.On("AddTag", uint(1), "q3").Return(nil).Once()
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+
+		report, err := service.Import(models.TodoImportRequest{
+			Header: []string{"id", "Title", "Due Date", "Category", "Completed", "Tags"},
+			Records: [][]string{
+				{"10", "Write report", "2026-11-02", "Work", "false", "q3,"},
+				{"11", "Plan sprint", "", "work", "yes", ""},
+				{"12", "Water plants", "", "home", "", ""},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.True(t, report.Committed)
+		assert.Equal(t, map[string]string{
+			"Title":     models.ImportFieldTitle,
+			"Due Date":  models.ImportFieldDueDate,
+			"Category":  models.ImportFieldCategory,
+			"Completed": models.ImportFieldCompleted,
+			"Tags":      models.ImportFieldTags,
+		}, report.Mapping)
+		assert.Equal(t, []string{"id"}, report.IgnoredColumns)
+		assert.Equal(t, []string{"Work"}, report.CreatedCategories)
+		assert.Equal(t, 3, report.Valid)
+		for _, row := range report.Rows {
+			assert.Equal(t, models.ImportStatusCreated, row.Status)
+		}
+		require.Len(t, created, 3)
+		assert.Equal(t, uint(7), *created[0].CategoryID)
+		assert.Equal(t, 2026, created[0].DueDate.Year())
+		assert.Equal(t, uint(7), *created[1].CategoryID)
+		assert.True(t, created[1].Completed)
+		assert.Equal(t, uint(2), *created[2].CategoryID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("reports every invalid row under its column without committing", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+		mockRepo.On("MaxPosition").Return("", nil)
+		mockRepo.On("LockPositions").Return(nil)
+		mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Return(nil)
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+
+		report, err := service.Import(models.TodoImportRequest{
+			Header:  []string{"Task", "Deadline", "Title"},
+			Mapping: map[string]string{"Task": "title", "Deadline": "due_date", "Title": ""},
+			Records: [][]string{
+				{"Book flights", "2026-12-01", "ignored"},
+				{"", "31/12/2026"},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.False(t, report.Committed)
+		assert.Equal(t, []string{"Title"}, report.IgnoredColumns)
+		assert.Equal(t, 1, report.Valid)
+		assert.Equal(t, 1, report.Invalid)
+		assert.Equal(t, models.ImportStatusValid, report.Rows[0].Status)
+		assert.Nil(t, report.Rows[0].TodoID)
+		assert.Equal(t, 3, report.Rows[1].Row)
+		assert.Equal(t, models.ImportStatusInvalid, report.Rows[1].Status)
+		assert.ElementsMatch(t, []models.ImportError{
+			{Column: "Deadline", Field: "due_date", Message: services.ErrImportInvalidDate.Error()},
+			{Column: "Task", Field: "title", Message: services.ErrTodoTitleRequired.Error()},
+		}, report.Rows[1].Errors)
+	})
+
+	t.Run("dry run never commits", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+		mockRepo.On("MaxPosition").Return("", nil)
+		mockRepo.On("LockPositions").Return(nil)
+		mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Return(nil)
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+
+		report, err := service.Import(models.TodoImportRequest{
+			Header:  []string{"title"},
+			Records: [][]string{{"Only a test"}},
+			DryRun:  true,
+		})
+
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.False(t, report.Committed)
+		assert.Equal(t, models.ImportStatusValid, report.Rows[0].Status)
+	})
+
+	t.Run("reads back text guarded by the CSV export", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+		var created *models.Todo
+		mockRepo.On("MaxPosition").Return("", nil)
+		mockRepo.On("LockPositions").Return(nil)
+		mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Run(func(args mock.Arguments) {
+			created = args.Get(0).(*models.Todo)
+		}).Return(nil)
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+
+		_, err := service.Import(models.TodoImportRequest{
+			Header:  []string{"title", "description"},
+			Records: [][]string{{"'=SUM(A1:A2)", "'don't touch"}},
+		})
+
+		require.NoError(t, err)
+		require.NotNil(t, created)
+		assert.Equal(t, "=SUM(A1:A2)", created.Title)
+		assert.Equal(t, "'don't touch", created.Description)
+	})
+
+	t.Run("rejects bad mappings", func(t *testing.T) {
+		service := newTodoService(new(MockTodoRepository), new(MockCategoryRepository))
+
+		_, err := service.Import(models.TodoImportRequest{
+			Header:  []string{"Task", "Notes", "Summary"},
+			Mapping: map[string]string{"Notes": "body", "Summary": "description", "Task": "description"},
+			Records: [][]string{{"a", "b", "c"}},
+		})
+
+		assert.ElementsMatch(t, []string{"mapping.Notes:oneof", "mapping.Summary:unique"}, fieldRules(t, err))
+
+		_, err = service.Import(models.TodoImportRequest{
+			Header:  []string{"Notes"},
+			Records: [][]string{{"b"}},
+		})
+
+		assert.ErrorIs(t, err, services.ErrImportTitleUnmapped)
+	})
+}
+
+func TestTodoService_Export(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
+	mockRepo.On("Each", models.TodoFilter{Search: "report", Limit: 50, Page: 1}).
+		Return([]models.Todo{{ID: 1}, {ID: 2}}, nil).Once()
+
+	var ids []uint
+	err := service.Export(models.TodoFilter{Search: "report", Limit: 50}, func(todo *models.Todo) error {
+		ids = append(ids, todo.ID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, ids)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func markdownTodos() []*models.Todo {
+	work := &models.Category{ID: 1, Name: "Work"}
+	home := &models.Category{ID: 2, Name: "home"}
+	return []*models.Todo{
+		{Title: "Ship *v2*", Priority: models.PriorityHigh, DueDate: date(2026, 10, 20), Category: work,
+			Description: "Release notes\r\n\r\nTag the build"},
+		{Title: "Water plants", Priority: models.PriorityLow, Completed: true, Category: home},
+		{Title: "Inbox zero", Priority: models.PriorityMedium, DueDate: date(2026, 10, 19)},
+		{Title: "Review PRs", Priority: models.PriorityMedium, DueDate: date(2026, 10, 19), Category: work},
+	}
+}
+
+func writeMarkdown(t *testing.T, groupBy models.MarkdownGroup, todos []*models.Todo) string {
+	document, err := services.NewTodoMarkdown(groupBy)
+	require.NoError(t, err)
+	for _, todo := range todos {
+		document.Add(todo)
+	}
+	var b strings.Builder
+	require.NoError(t, document.Write(&b))
+	return b.String()
+}
+
+func TestTodoMarkdown_ByCategory(t *testing.T) {
+	assert.Equal(t, "# Todos\n"+
+		"\n## home\n\n"+
+		"- [x] Water plants `🟢 low`\n"+
+		"\n## Work\n\n"+
+		"- [ ] Ship \\*v2\\* `🔴 high` · due 2026-10-20\n"+
+		"  > Release notes\n"+
+		"  >\n"+
+		"  > Tag the build\n"+
+		"- [ ] Review PRs `🟡 medium` · due 2026-10-19\n"+
+		"\n## No category\n\n"+
+		"- [ ] Inbox zero `🟡 medium` · due 2026-10-19\n",
+		writeMarkdown(t, "", markdownTodos()))
+}
+
+func TestTodoMarkdown_ByDue(t *testing.T) {
+	todos := markdownTodos()
+	todos[0].Description = ""
+	assert.Equal(t, "# Todos\n"+
+		"\n## 2026-10-19, Monday\n\n"+
+		"- [ ] Inbox zero `🟡 medium`\n"+
+		"- [ ] Review PRs `🟡 medium`\n"+
+		"\n## 2026-10-20, Tuesday\n\n"+
+		"- [ ] Ship \\*v2\\* `🔴 high`\n"+
+		"\n## No due date\n\n"+
+		"- [x] Water plants `🟢 low`\n",
+		writeMarkdown(t, models.MarkdownByDue, todos))
+}
+
+func TestTodoMarkdown_Empty(t *testing.T) {
+	assert.Equal(t, "# Todos\n\n_No todos._\n", writeMarkdown(t, models.MarkdownByDue, nil))
+}
+
+func TestTodoMarkdown_InvalidGroup(t *testing.T) {
+	_, err := services.NewTodoMarkdown("status")
+	assert.ErrorIs(t, err, services.ErrInvalidMarkdownGroup)
+	assert.Equal(t, []string{"group_by:oneof"}, fieldRules(t, err))
+}
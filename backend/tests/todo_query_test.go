@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTodoQuery(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 30, 0, 0, time.UTC)
+	match := func(field models.QueryField, value string) models.QueryMatch {
+		return models.QueryMatch{Field: field, Value: value}
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  models.TodoQuery
+	}{
+		{
+			name:  "empty",
+			query: "   ",
+			want:  nil,
+		},
+		{
+			name:  "example from the docs",
+			query: "priority:high AND (category:Work OR tag:urgent) AND due<2026-11-01 AND NOT completed",
+			want: models.QueryAnd{
+				Left: models.QueryAnd{
+					Left: models.QueryAnd{
+						Left:  match(models.QueryPriority, "high"),
+						Right: models.QueryOr{Left: match(models.QueryCategory, "Work"), Right: match(models.QueryTag, "urgent")},
+					},
+					Right: models.QueryDue{To: date(2026, 11, 1)},
+				},
+				Right: models.QueryNot{Operand: match(models.QueryCompleted, "true")},
+			},
+		},
+		{
+			name:  "AND binds tighter than OR and may be left out",
+			query: `tag:home OR "pay rent" blocked:false`,
+			want: models.QueryOr{
+				Left:  match(models.QueryTag, "home"),
+				Right: models.QueryAnd{Left: match(models.QueryText, "pay rent"), Right: match(models.QueryBlocked, "false")},
+			},
+		},
+		{
+			name:  "quoted names, lower case keywords and custom fields",
+			query: `status:"In Progress" and cf.client:Acme PRIORITY:Low`,
+			want: models.QueryAnd{
+				Left: models.QueryAnd{
+					Left:  models.QueryAnd{Left: match(models.QueryStatus, "In Progress"), Right: match(models.QueryText, "and")},
+					Right: models.QueryMatch{Field: models.QueryCustomField, Key: "client", Value: "Acme"},
+				},
+				Right: match(models.QueryPriority, "low"),
+			},
+		},
+		{
+			name:  "due comparisons include or exclude the whole day",
+			query: "due>=2026-10-01 due<=2026-10-31 due>today",
+			want: models.QueryAnd{
+				Left:  models.QueryAnd{Left: models.QueryDue{From: date(2026, 10, 1)}, Right: models.QueryDue{To: date(2026, 11, 1)}},
+				Right: models.QueryDue{From: date(2026, 10, 19)},
+			},
+		},
+		{
+			name:  "due windows and dates",
+			query: "(due:overdue OR due:2026-10-25 OR due:upcoming) NOT due:none",
+			want: models.QueryAnd{
+				Left: models.QueryOr{
+					Left: models.QueryOr{
+						Left:  models.QueryDue{To: date(2026, 10, 18)},
+						Right: models.QueryDue{From: date(2026, 10, 25), To: date(2026, 10, 26)},
+					},
+					Right: models.QueryDue{From: date(2026, 10, 19), To: date(2026, 10, 26)},
+				},
+				Right: models.QueryNot{Operand: models.QueryDue{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := services.ParseTodoQuery(tt.query, now)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTodoQuery_DatesInLocation(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	// Still the 18th in UTC, already the 19th in Jakarta
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC).In(jakarta)
+
+	got, err := services.ParseTodoQuery("due:today", now)
+
+	require.NoError(t, err)
+	due := got.(models.QueryDue)
+	assert.True(t, due.From.Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, jakarta)))
+	assert.True(t, due.To.Equal(time.Date(2026, 10, 20, 0, 0, 0, 0, jakarta)))
+}
+
+func TestParseTodoQuery_Errors(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+		message  string
+	}{
+		{"priority:urgent", 10, "priority must be one of high, medium or low"},
+		{"owner:me", 1, `unknown field "owner"`},
+		{"tag<urgent", 4, `tag only supports ":"`},
+		{"tag:", 5, `expected a value after ":", got end of query`},
+		{"due<soon", 5, "due must be a date (YYYY-MM-DD), today or tomorrow"},
+		{"due:someday", 5, "due must be a date (YYYY-MM-DD) or one of overdue"},
+		{"completed:maybe", 11, "completed must be true or false"},
+		{"cf.Bad-Key:x", 1, "custom field key"},
+		{"(tag:a OR tag:b", 1, `unclosed "("`},
+		{"tag:a)", 6, `unmatched ")"`},
+		{"tag:a AND", 10, "expected a term, got end of query"},
+		{"OR tag:a", 1, `expected a term, got "OR"`},
+		{`title "unfinished`, 7, "unterminated quoted string"},
+		{"due=2026-10-01", 4, `unexpected '='`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := services.ParseTodoQuery(tt.query, time.Now())
+
+			var qerr *services.QueryError
+			require.ErrorAs(t, err, &qerr)
+			assert.Equal(t, tt.position, qerr.Position)
+			assert.Contains(t, qerr.Message, tt.message)
+		})
+	}
+}
+
+func TestTodoService_GetAllQuery(t *testing.T) {
+	t.Run("passes the parsed query to the repository", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+		mockRepo.On("GetAll", mock.MatchedBy(func(filter models.TodoFilter) bool {
+			return filter.Expr == models.QueryMatch{Field: models.QueryTag, Value: "urgent"}
+		})).Return([]models.Todo{}, int64(0), nil).Once()
+
+		_, err := service.GetAll(models.TodoFilter{Query: "tag:urgent"})
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects an invalid query before querying", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+
+		_, err := service.GetAll(models.TodoFilter{Query: "priority:high AND"})
+
+		var qerr *services.QueryError
+		require.ErrorAs(t, err, &qerr)
+		assert.Equal(t, 18, qerr.Position)
+		mockRepo.AssertNotCalled(t, "GetAll", mock.Anything)
+	})
+}
@@ -1,10 +1,13 @@
 package tests
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/repository"
 	"github.com/industrix-todo-app/backend/internal/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -31,6 +34,11 @@ func (m *MockTodoRepository) GetAll(filter models.TodoFilter) ([]models.Todo, in
 	return args.Get(0).([]models.Todo), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockTodoRepository) ListState(filter models.TodoFilter) (models.ListState, error) {
+	args := m.Called(filter)
+	return args.Get(0).(models.ListState), args.Error(1)
+}
+
 func (m *MockTodoRepository) GetByID(id uint) (*models.Todo, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -44,14 +52,140 @@ func (m *MockTodoRepository) Update(todo *models.Todo) error {
 	return args.Error(0)
 }
 
-func (m *MockTodoRepository) Delete(id uint) error {
-	args := m.Called(id)
+func (m *MockTodoRepository) Delete(id, version uint) error {
+	args := m.Called(id, version)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) FindIDs(filter models.TodoFilter) ([]uint, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockTodoRepository) Each(filter models.TodoFilter, fn func(todo *models.Todo) error) error {
+	args := m.Called(filter)
+	for _, todo := range args.Get(0).([]models.Todo) {
+		if err := fn(&todo); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTodoRepository) MaxPosition() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTodoRepository) AdjacentPosition(scope models.PositionScope, scopeID *uint, position string, after bool, excludeID uint) (string, error) {
+	args := m.Called(scope, scopeID, position, after, excludeID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTodoRepository) IDsByPosition() ([]uint, error) {
+	args := m.Called()
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockTodoRepository) IDsWithoutPosition() ([]uint, error) {
+	args := m.Called()
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockTodoRepository) SetPosition(id uint, position string) error {
+	args := m.Called(id, position)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) AddTag(todoID uint, name string) error {
+	args := m.Called(todoID, name)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) RemoveTag(todoID uint, name string) error {
+	args := m.Called(todoID, name)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) AddChecklistItems(items []models.ChecklistItem) error {
+	args := m.Called(items)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) FindOrCreateCategory(name string) (*models.Category, bool, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*models.Category), args.Bool(1), args.Error(2)
+}
+
+func (m *MockTodoRepository) AddBlocker(todoID, blockerID uint) error {
+	args := m.Called(todoID, blockerID)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) RemoveBlocker(todoID, blockerID uint) error {
+	args := m.Called(todoID, blockerID)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) Blockers(todoID uint) ([]models.Todo, error) {
+	args := m.Called(todoID)
+	return args.Get(0).([]models.Todo), args.Error(1)
+}
+
+func (m *MockTodoRepository) Blocking(todoID uint) ([]models.Todo, error) {
+	args := m.Called(todoID)
+	return args.Get(0).([]models.Todo), args.Error(1)
+}
+
+func (m *MockTodoRepository) BlockerIDs(todoIDs []uint) ([]uint, error) {
+	args := m.Called(todoIDs)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockTodoRepository) LockPositions() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) LockDependencies() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) OpenBlockerCount(todoID uint) (int64, error) {
+	args := m.Called(todoID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTodoRepository) CountInStatusForUpdate(statusID uint) (int64, error) {
+	args := m.Called(statusID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTodoRepository) UpdateStatus(status *models.Status) error {
+	args := m.Called(status)
 	return args.Error(0)
 }
 
+// Transaction runs fn directly against the mock since there is no database
+func (m *MockTodoRepository) Transaction(fn func(repo repository.TodoRepository) error) error {
+	return fn(m)
+}
+
+// newTodoService builds a TodoService on a board without statuses, so
+// completion changes leave todos without a status
+func newTodoService(repo *MockTodoRepository, categoryRepo *MockCategoryRepository) services.TodoService {
+	statusRepo := new(MockStatusRepository)
+	statusRepo.On("FirstByDone", mock.Anything).Return(nil, services.ErrStatusNotFound).Maybe()
+	return services.NewTodoService(repo, categoryRepo, statusRepo, nil, false)
+}
+
 func TestTodoService_Create(t *testing.T) {
 	mockRepo := new(MockTodoRepository)
-	service := services.NewTodoService(mockRepo)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
 
 	t.Run("successful creation", func(t *testing.T) {
 		req := models.CreateTodoRequest{
@@ -60,7 +194,11 @@ func TestTodoService_Create(t *testing.T) {
 			Priority:    models.PriorityHigh,
 		}
 
-		mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+		mockRepo.On("MaxPosition").Return("V", nil).Once()
+		mockRepo.On("LockPositions").Return(nil)
+		mockRepo.On("Create", mock.MatchedBy(func(todo *models.Todo) bool {
+			return todo.Position > "V"
+		})).Return(nil).Once()
 		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{
 			ID:          1,
 			Title:       req.Title,
@@ -77,6 +215,49 @@ func TestTodoService_Create(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("rebalances the order when the last rank cannot grow", func(t *testing.T) {
+		var positions []string
+
+		mockRepo.On("MaxPosition").Return(strings.Repeat("z", 255), nil).Once()
+		mockRepo.On("IDsByPosition").Return([]uint{4, 5}, nil).Once()
+		mockRepo.On("SetPosition", mock.Anything, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			positions = append(positions, args.String(1))
+		}).Return(nil).Twice()
+		mockRepo.On("MaxPosition").Return("k", nil).Once()
+		mockRepo.On("LockPositions").Return(nil)
+		mockRepo.On("Create", mock.MatchedBy(func(todo *models.Todo) bool {
+			return todo.Position > "k" && len(todo.Position) <= 255
+		})).Return(nil).Once()
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil).Once()
+
+		_, err := service.Create(models.CreateTodoRequest{Title: "One more"})
+
+		assert.NoError(t, err)
+		assert.IsIncreasing(t, positions)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("creates tags and checklist with the todo", func(t *testing.T) {
+		mockRepo.On("MaxPosition").Return("", nil).Once()
+		mockRepo.On("LockPositions").Return(nil)
+		mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+		mockRepo.On("AddTag", uint(1), "release").Return(nil).Once()
+		mockRepo.On("AddChecklistItems", mock.MatchedBy(func(items []models.ChecklistItem) bool {
+			return len(items) == 2 && items[0].Title == "Tag build" && items[1].Title == "Publish notes" &&
+				items[0].TodoID == 1 && items[0].Position < items[1].Position
+		})).Return(nil).Once()
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil).Once()
+
+		_, err := service.Create(models.CreateTodoRequest{
+			Title:     "Ship 1.2",
+			Tags:      []string{" release", "release"},
+			Checklist: []string{"Tag build ", "Publish notes"},
+		})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("empty title error", func(t *testing.T) {
 		req := models.CreateTodoRequest{
 			Title: "",
@@ -86,7 +267,7 @@ func TestTodoService_Create(t *testing.T) {
 
 		assert.Error(t, err)
 		assert.Nil(t, todo)
-		assert.Equal(t, services.ErrTodoTitleRequired, err)
+		assert.ErrorIs(t, err, services.ErrTodoTitleRequired)
 	})
 
 	t.Run("invalid priority error", func(t *testing.T) {
@@ -99,13 +280,54 @@ func TestTodoService_Create(t *testing.T) {
 
 		assert.Error(t, err)
 		assert.Nil(t, todo)
-		assert.Equal(t, services.ErrInvalidPriority, err)
+		assert.ErrorIs(t, err, services.ErrInvalidPriority)
+	})
+
+	t.Run("negative estimate error", func(t *testing.T) {
+		estimate := -5
+		req := models.CreateTodoRequest{
+			Title:           "Test",
+			EstimateMinutes: &estimate,
+		}
+
+		todo, err := service.Create(req)
+
+		assert.Nil(t, todo)
+		assert.ErrorIs(t, err, services.ErrInvalidEstimate)
+	})
+
+	t.Run("reports every invalid field", func(t *testing.T) {
+		mockCategoryRepo := new(MockCategoryRepository)
+		service := newTodoService(mockRepo, mockCategoryRepo)
+
+		dueDate := time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC)
+		categoryID := uint(42)
+		req := models.CreateTodoRequest{
+			Title:      "",
+			Priority:   "urgent",
+			DueDate:    &dueDate,
+			CategoryID: &categoryID,
+		}
+
+		mockCategoryRepo.On("GetByID", uint(42)).Return(nil, services.ErrCategoryNotFound).Once()
+
+		todo, err := service.Create(req)
+
+		assert.Nil(t, todo)
+		var verr *services.ValidationError
+		assert.ErrorAs(t, err, &verr)
+		fields := make([]string, len(verr.Fields))
+		for i, f := range verr.Fields {
+			fields[i] = f.Field + ":" + f.Rule
+		}
+		assert.Equal(t, []string{"title:required", "priority:oneof", "due_date:range", "category_id:exists"}, fields)
+		mockCategoryRepo.AssertExpectations(t)
 	})
 }
 
 func TestTodoService_GetAll(t *testing.T) {
 	mockRepo := new(MockTodoRepository)
-	service := services.NewTodoService(mockRepo)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
 
 	t.Run("successful get all with pagination", func(t *testing.T) {
 		filter := models.TodoFilter{
@@ -146,9 +368,27 @@ func TestTodoService_GetAll(t *testing.T) {
 	})
 }
 
+func TestTodoService_ListState(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
+
+	t.Run("returns repository state for filter", func(t *testing.T) {
+		filter := models.TodoFilter{Priority: models.PriorityHigh}
+		expected := models.ListState{LastModified: time.Now(), Count: 3}
+
+		mockRepo.On("ListState", filter).Return(expected, nil).Once()
+
+		state, err := service.ListState(filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, state)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
 func TestTodoService_GetByID(t *testing.T) {
 	mockRepo := new(MockTodoRepository)
-	service := services.NewTodoService(mockRepo)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
 
 	t.Run("successful get by id", func(t *testing.T) {
 		expectedTodo := &models.Todo{
@@ -179,7 +419,7 @@ func TestTodoService_GetByID(t *testing.T) {
 
 func TestTodoService_Update(t *testing.T) {
 	mockRepo := new(MockTodoRepository)
-	service := services.NewTodoService(mockRepo)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
 
 	t.Run("successful update", func(t *testing.T) {
 		existingTodo := &models.Todo{
@@ -194,44 +434,170 @@ func TestTodoService_Update(t *testing.T) {
 		mockRepo.On("GetByID", uint(1)).Return(existingTodo, nil).Times(2)
 		mockRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
 
-		todo, err := service.Update(1, req)
+		todo, err := service.Update(1, req, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, todo)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("fields left out are cleared", func(t *testing.T) {
+		dueDate := time.Now()
+		categoryID := uint(3)
+		estimate := 30
+		existingTodo := &models.Todo{
+			ID:              2,
+			Title:           "Old Title",
+			Description:     "Old description",
+			Priority:        models.PriorityHigh,
+			DueDate:         &dueDate,
+			CategoryID:      &categoryID,
+			EstimateMinutes: &estimate,
+			Recurrence:      "FREQ=WEEKLY",
+		}
+
+		mockRepo.On("GetByID", uint(2)).Return(existingTodo, nil).Times(2)
+		mockRepo.On("Update", mock.MatchedBy(func(todo *models.Todo) bool {
+			return todo.Title == "New Title" && todo.Description == "" &&
+				todo.Priority == models.PriorityMedium && todo.DueDate == nil &&
+				todo.CategoryID == nil && todo.EstimateMinutes == nil &&
+				len(todo.CustomFields) == 0 && todo.Recurrence == "" && !todo.Completed
+		})).Return(nil).Once()
+
+		_, err := service.Update(2, models.UpdateTodoRequest{Title: "New Title"}, nil)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("title is required", func(t *testing.T) {
+		mockRepo.On("GetByID", uint(5)).Return(&models.Todo{ID: 5, Title: "Title"}, nil).Once()
+
+		_, err := service.Update(5, models.UpdateTodoRequest{Description: "only"}, nil)
+
+		assert.Equal(t, []string{"title:required"}, fieldRules(t, err))
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("not found error", func(t *testing.T) {
 		mockRepo.On("GetByID", uint(999)).Return(nil, services.ErrTodoNotFound).Once()
 
-		todo, err := service.Update(999, models.UpdateTodoRequest{})
+		todo, err := service.Update(999, models.UpdateTodoRequest{}, nil)
 
 		assert.Error(t, err)
 		assert.Nil(t, todo)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("stale expected version", func(t *testing.T) {
+		existingTodo := &models.Todo{ID: 3, Title: "Title", Version: 4}
+		staleVersion := uint(3)
+
+		mockRepo.On("GetByID", uint(3)).Return(existingTodo, nil).Once()
+
+		todo, err := service.Update(3, models.UpdateTodoRequest{Title: "New"}, &staleVersion)
+
+		assert.Nil(t, todo)
+		assert.ErrorIs(t, err, services.ErrVersionConflict)
+		assert.Equal(t, "Title", existingTodo.Title)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("concurrent write detected by repository", func(t *testing.T) {
+		existingTodo := &models.Todo{ID: 4, Title: "Title", Version: 1}
+		version := uint(1)
+
+		mockRepo.On("GetByID", uint(4)).Return(existingTodo, nil).Once()
+		mockRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(repository.ErrVersionConflict).Once()
+
+		todo, err := service.Update(4, models.UpdateTodoRequest{Title: "New"}, &version)
+
+		assert.Nil(t, todo)
+		assert.ErrorIs(t, err, services.ErrVersionConflict)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTodoService_Patch(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
+
+	t.Run("null clears fields and absent fields are untouched", func(t *testing.T) {
+		dueDate := time.Now()
+		categoryID := uint(3)
+		existingTodo := &models.Todo{
+			ID:          1,
+			Title:       "Keep me",
+			Description: "Old description",
+			Priority:    models.PriorityHigh,
+			DueDate:     &dueDate,
+			CategoryID:  &categoryID,
+		}
+
+		var req models.PatchTodoRequest
+		err := json.Unmarshal([]byte(`{"description": null, "due_date": null, "category_id": null}`), &req)
+		assert.NoError(t, err)
+
+		mockRepo.On("GetByID", uint(1)).Return(existingTodo, nil).Times(2)
+		mockRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+
+		todo, err := service.Patch(1, req, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Keep me", todo.Title)
+		assert.Equal(t, "", todo.Description)
+		assert.Equal(t, models.PriorityHigh, todo.Priority)
+		assert.Nil(t, todo.DueDate)
+		assert.Nil(t, todo.CategoryID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("null title is rejected", func(t *testing.T) {
+		mockRepo.On("GetByID", uint(2)).Return(&models.Todo{ID: 2, Title: "Title"}, nil).Once()
+
+		var req models.PatchTodoRequest
+		err := json.Unmarshal([]byte(`{"title": null}`), &req)
+		assert.NoError(t, err)
+
+		todo, err := service.Patch(2, req, nil)
+
+		assert.Nil(t, todo)
+		assert.ErrorIs(t, err, services.ErrTodoTitleRequired)
+		mockRepo.AssertExpectations(t)
+	})
 }
 
 func TestTodoService_Delete(t *testing.T) {
 	mockRepo := new(MockTodoRepository)
-	service := services.NewTodoService(mockRepo)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
 
 	t.Run("successful delete", func(t *testing.T) {
 		existingTodo := &models.Todo{ID: 1}
 
 		mockRepo.On("GetByID", uint(1)).Return(existingTodo, nil).Once()
-		mockRepo.On("Delete", uint(1)).Return(nil).Once()
+		mockRepo.On("Delete", uint(1), uint(0)).Return(nil).Once()
 
-		err := service.Delete(1)
+		err := service.Delete(1, nil)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("concurrent write detected by repository", func(t *testing.T) {
+		version := uint(2)
+		mockRepo.On("GetByID", uint(2)).Return(&models.Todo{ID: 2, Version: 2}, nil).Once()
+		mockRepo.On("Delete", uint(2), uint(2)).Return(repository.ErrVersionConflict).Once()
+
+		err := service.Delete(2, &version)
+
+		assert.ErrorIs(t, err, services.ErrVersionConflict)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("not found error", func(t *testing.T) {
 		mockRepo.On("GetByID", uint(999)).Return(nil, services.ErrTodoNotFound).Once()
 
-		err := service.Delete(999)
+		err := service.Delete(999, nil)
 
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
@@ -240,7 +606,7 @@ func TestTodoService_Delete(t *testing.T) {
 
 func TestTodoService_ToggleComplete(t *testing.T) {
 	mockRepo := new(MockTodoRepository)
-	service := services.NewTodoService(mockRepo)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
 
 	t.Run("toggle from incomplete to complete", func(t *testing.T) {
 		existingTodo := &models.Todo{
@@ -274,3 +640,143 @@ func TestTodoService_ToggleComplete(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestTodoService_Bulk(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
+
+	t.Run("reports each item", func(t *testing.T) {
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil).Once()
+		mockRepo.On("GetByID", uint(2)).Return(nil, services.ErrTodoNotFound).Once()
+		mockRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+
+		response, err := service.Bulk(models.BulkTodoRequest{
+			IDs:    []uint{1, 2, 1},
+			Action: models.BulkActionComplete,
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, response.RolledBack)
+		assert.Equal(t, 1, response.Succeeded)
+		assert.Equal(t, 1, response.Failed)
+		assert.Equal(t, []models.BulkItemResult{
+			{ID: 1, Status: models.BulkStatusOK},
+			{ID: 2, Status: models.BulkStatusFailed, Error: services.ErrTodoNotFound.Error()},
+		}, response.Results)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("all or nothing rolls back on failure", func(t *testing.T) {
+		filter := models.TodoFilter{Priority: models.PriorityLow}
+
+		mockRepo.On("FindIDs", filter).Return([]uint{3, 4, 5}, nil).Once()
+		mockRepo.On("GetByID", uint(3)).Return(&models.Todo{ID: 3}, nil).Once()
+		mockRepo.On("AddTag", uint(3), "urgent").Return(nil).Once()
+		mockRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+		mockRepo.On("GetByID", uint(4)).Return(nil, services.ErrTodoNotFound).Once()
+
+		response, err := service.Bulk(models.BulkTodoRequest{
+			Filter:       &filter,
+			Action:       models.BulkActionAddTag,
+			Tag:          " urgent ",
+			AllOrNothing: true,
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, response.RolledBack)
+		assert.Equal(t, 0, response.Succeeded)
+		assert.Equal(t, []string{models.BulkStatusRolledBack, models.BulkStatusFailed, models.BulkStatusSkipped},
+			[]string{response.Results[0].Status, response.Results[1].Status, response.Results[2].Status})
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		response, err := service.Bulk(models.BulkTodoRequest{
+			Action:   models.BulkActionSetPriority,
+			Priority: "urgent",
+		})
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, services.ErrBulkSelectorRequired)
+		assert.ErrorIs(t, err, services.ErrInvalidPriority)
+	})
+}
+
+func TestTodoService_Move(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
+	categoryID := uint(1)
+	mockRepo.On("LockPositions").Return(nil)
+
+	t.Run("before an anchor", func(t *testing.T) {
+		todo := &models.Todo{ID: 1, CategoryID: &categoryID, Position: "x"}
+		anchor := &models.Todo{ID: 2, CategoryID: &categoryID, Position: "c"}
+
+		mockRepo.On("GetByID", uint(1)).Return(todo, nil).Once()
+		mockRepo.On("GetByID", uint(2)).Return(anchor, nil).Once()
+		mockRepo.On("AdjacentPosition", models.PositionScopeCategory, &categoryID, "c", false, uint(1)).Return("a", nil).Once()
+		mockRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+
+		moved, err := service.Move(1, models.MoveTodoRequest{Before: &anchor.ID})
+
+		assert.NoError(t, err)
+		assert.Greater(t, moved.Position, "a")
+		assert.Less(t, moved.Position, "c")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("between two anchors", func(t *testing.T) {
+		todo := &models.Todo{ID: 3, CategoryID: &categoryID, Position: "a"}
+		after := &models.Todo{ID: 4, CategoryID: &categoryID, Position: "V"}
+		before := &models.Todo{ID: 5, CategoryID: &categoryID, Position: "V1"}
+
+		mockRepo.On("GetByID", uint(3)).Return(todo, nil).Once()
+		mockRepo.On("GetByID", uint(4)).Return(after, nil).Once()
+		mockRepo.On("GetByID", uint(5)).Return(before, nil).Once()
+		mockRepo.On("Update", mock.AnythingOfType("*models.Todo")).Return(nil).Once()
+
+		moved, err := service.Move(3, models.MoveTodoRequest{After: &after.ID, Before: &before.ID})
+
+		assert.NoError(t, err)
+		assert.Greater(t, moved.Position, "V")
+		assert.Less(t, moved.Position, "V1")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("anchor in another category", func(t *testing.T) {
+		todo := &models.Todo{ID: 6, CategoryID: &categoryID}
+		anchor := &models.Todo{ID: 7}
+
+		mockRepo.On("GetByID", uint(6)).Return(todo, nil).Once()
+		mockRepo.On("GetByID", uint(7)).Return(anchor, nil).Once()
+
+		moved, err := service.Move(6, models.MoveTodoRequest{After: &anchor.ID})
+
+		assert.Nil(t, moved)
+		assert.ErrorIs(t, err, services.ErrMoveAnchorCategory)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTodoService_BackfillPositions(t *testing.T) {
+	mockRepo := new(MockTodoRepository)
+	service := newTodoService(mockRepo, new(MockCategoryRepository))
+
+	t.Run("ranks unpositioned todos after the last one", func(t *testing.T) {
+		var positions []string
+
+		mockRepo.On("IDsWithoutPosition").Return([]uint{7, 8, 9}, nil).Once()
+		mockRepo.On("MaxPosition").Return("V", nil).Once()
+		mockRepo.On("SetPosition", mock.Anything, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			positions = append(positions, args.String(1))
+		}).Return(nil).Times(3)
+
+		err := service.BackfillPositions()
+
+		assert.NoError(t, err)
+		assert.Len(t, positions, 3)
+		assert.Greater(t, positions[0], "V")
+		assert.IsIncreasing(t, positions)
+		mockRepo.AssertExpectations(t)
+	})
+}
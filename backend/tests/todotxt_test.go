@@ -0,0 +1,231 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/industrix-todo-app/backend/internal/models"
+	"github.com/industrix-todo-app/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestParseTodoTxt(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want models.TodoTxtTask
+	}{
+		{
+			name: "plain task",
+			line: "Call mom",
+			want: models.TodoTxtTask{Title: "Call mom"},
+		},
+		{
+			name: "priority, creation date, project, contexts and due date",
+			line: "(A) 2026-10-01 Write report +Work_Stuff @phone due:2026-10-20 @office",
+			want: models.TodoTxtTask{
+				Priority:  models.PriorityHigh,
+				CreatedOn: date(2026, 10, 1),
+				Title:     "Write report",
+				Projects:  []string{"Work Stuff"},
+				Contexts:  []string{"phone", "office"},
+				DueDate:   date(2026, 10, 20),
+			},
+		},
+		{
+			name: "letters past C are low",
+			line: "(D) Someday",
+			want: models.TodoTxtTask{Priority: models.PriorityLow, Title: "Someday"},
+		},
+		{
+			name: "completed with dates and kept priority",
+			line: "x 2026-10-05 2026-10-01 Pay rent pri:B",
+			want: models.TodoTxtTask{
+				Completed:   true,
+				CompletedOn: date(2026, 10, 5),
+				CreatedOn:   date(2026, 10, 1),
+				Priority:    models.PriorityMedium,
+				Title:       "Pay rent",
+			},
+		},
+		{
+			name: "markers only count in their place",
+			line: "Email x (A) about +1 and me@ and x@y pri:A",
+			want: models.TodoTxtTask{
+				Title:    "Email x (A) about and me@ and x@y pri:A",
+				Projects: []string{"1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := services.ParseTodoTxt(tt.line)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, task)
+		})
+	}
+
+	t.Run("reports an unreadable due date", func(t *testing.T) {
+		task, err := services.ParseTodoTxt("Renew passport due:next-week")
+
+		assert.Equal(t, []string{"due_date:format"}, fieldRules(t, err))
+		assert.Equal(t, "Renew passport", task.Title)
+	})
+}
+
+func TestFormatTodoTxt(t *testing.T) {
+	created := time.Date(2026, 10, 1, 14, 30, 0, 0, time.UTC)
+
+	open := &models.Todo{
+		Title:     "Write report",
+		Priority:  models.PriorityHigh,
+		DueDate:   date(2026, 10, 20),
+		Category:  &models.Category{Name: "Work Stuff"},
+		Tags:      []models.Tag{{Name: "phone"}},
+		CreatedAt: created,
+	}
+	assert.Equal(t, "(A) 2026-10-01 Write report +Work_Stuff @phone due:2026-10-20", services.FormatTodoTxt(open))
+
+	done := &models.Todo{
+		Title:     "Pay rent",
+		Completed: true,
+		Priority:  models.PriorityLow,
+		CreatedAt: created,
+		UpdatedAt: created.AddDate(0, 0, 4),
+	}
+	assert.Equal(t, "x 2026-10-05 2026-10-01 Pay rent pri:C", services.FormatTodoTxt(done))
+
+	markers := &models.Todo{Title: "x Email @bob about +1 offer due:soon", Priority: models.PriorityMedium}
+	assert.Equal(t, `(B) \x Email \@bob about \+1 offer \due:soon`, services.FormatTodoTxt(markers))
+}
+
+func TestTodoTxt_RoundTrip(t *testing.T) {
+	todos := []*models.Todo{
+		{Title: "Plain", Priority: models.PriorityMedium},
+		{
+			Title:     "Write quarterly report",
+			Priority:  models.PriorityHigh,
+			DueDate:   date(2026, 12, 31),
+			Category:  &models.Category{Name: "Home Office"},
+			Tags:      []models.Tag{{Name: "deep work"}, {Name: "q4"}},
+			CreatedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Title:     "Done already",
+			Completed: true,
+			Priority:  models.PriorityLow,
+			CreatedAt: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2026, 9, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{Title: "Email @bob about +1 offer due:soon", Priority: models.PriorityMedium},
+		{Title: `x marks \the spot pri:A`, Completed: true, Priority: models.PriorityHigh},
+		{Title: "2026-10-01 kickoff", Priority: models.PriorityLow},
+		{Title: "(A) is not a priority", Priority: models.PriorityMedium},
+	}
+
+	for _, todo := range todos {
+		t.Run(todo.Title, func(t *testing.T) {
+			task, err := services.ParseTodoTxt(services.FormatTodoTxt(todo))
+			require.NoError(t, err)
+
+			assert.Equal(t, todo.Title, task.Title)
+			assert.Equal(t, todo.Completed, task.Completed)
+			assert.Equal(t, todo.Priority, task.Priority)
+			assert.Equal(t, todo.DueDate, task.DueDate)
+			if todo.Category != nil {
+				assert.Equal(t, []string{todo.Category.Name}, task.Projects)
+			}
+			var tags []string
+			for _, tag := range todo.Tags {
+				tags = append(tags, tag.Name)
+			}
+			assert.Equal(t, tags, task.Contexts)
+			if !todo.CreatedAt.IsZero() {
+				assert.True(t, todo.CreatedAt.Equal(*task.CreatedOn))
+			}
+		})
+	}
+}
+
+func TestTodoService_ImportTodoTxt(t *testing.T) {
+	t.Run("imports lines that export back unchanged", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		categoryRepo := new(MockCategoryRepository)
+		service := newTodoService(mockRepo, categoryRepo)
+
+		work := &models.Category{ID: 7, Name: "Work Stuff"}
+		mockRepo.On("FindOrCreateCategory", "Work Stuff").Return(work, true, nil).Once()
+		mockRepo.On("MaxPosition").Return("", nil)
+		mockRepo.On("LockPositions").Return(nil)
+		var created []models.Todo
+		mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Run(func(args mock.Arguments) {
+			created = append(created, *args.Get(0).(*models.Todo))
+		}).Return(nil)
+		var tags []string
+		mockRepo.On("AddTag", uint(1), mock.Anything).Run(func(args mock.Arguments) {
+			tags = append(tags, args.String(1))
+		}).Return(nil)
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+
+		lines := []string{
+			"(A) Write report +Work_Stuff @phone @deep_work due:2026-10-20",
+			"",
+			"x Pay rent pri:C",
+			"(B) Water plants",
+		}
+		report, err := service.ImportTodoTxt(models.TodoTxtImportRequest{Lines: lines})
+
+		require.NoError(t, err)
+		assert.True(t, report.Committed)
+		require.Len(t, report.Rows, 3)
+		assert.Equal(t, []int{1, 3, 4}, []int{report.Rows[0].Row, report.Rows[1].Row, report.Rows[2].Row})
+		assert.Equal(t, []string{"Work Stuff"}, report.CreatedCategories)
+		assert.Equal(t, []string{"phone", "deep work"}, tags)
+
+		require.Len(t, created, 3)
+		created[0].Category = work
+		created[0].Tags = []models.Tag{{Name: "phone"}, {Name: "deep work"}}
+		for i, line := range []string{lines[0], lines[2], lines[3]} {
+			assert.Equal(t, line, services.FormatTodoTxt(&created[i]))
+		}
+	})
+
+	t.Run("reports invalid lines by line number", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		service := newTodoService(mockRepo, new(MockCategoryRepository))
+		mockRepo.On("MaxPosition").Return("", nil)
+		mockRepo.On("LockPositions").Return(nil)
+		mockRepo.On("Create", mock.AnythingOfType("*models.Todo")).Return(nil)
+		mockRepo.On("GetByID", uint(1)).Return(&models.Todo{ID: 1}, nil)
+
+		report, err := service.ImportTodoTxt(models.TodoTxtImportRequest{Lines: []string{
+			"Fine",
+			"(A) @phone due:tomorrow",
+		}})
+
+		require.NoError(t, err)
+		assert.False(t, report.Committed)
+		assert.Equal(t, 2, report.Rows[1].Row)
+		assert.ElementsMatch(t, []models.ImportError{
+			{Field: "due_date", Message: services.ErrTodoTxtInvalidDue.Error()},
+			{Field: "title", Message: services.ErrTodoTitleRequired.Error()},
+		}, report.Rows[1].Errors)
+	})
+
+	t.Run("rejects a file without tasks", func(t *testing.T) {
+		service := newTodoService(new(MockTodoRepository), new(MockCategoryRepository))
+
+		_, err := service.ImportTodoTxt(models.TodoTxtImportRequest{Lines: []string{"", "  "}})
+
+		assert.Equal(t, []string{"file:required"}, fieldRules(t, err))
+	})
+}
@@ -7,7 +7,7 @@ import {
   DatePicker,
 } from 'antd';
 import dayjs from 'dayjs';
-import { Todo, CreateTodoRequest, UpdateTodoRequest, Priority } from '../types';
+import { Todo, CreateTodoRequest, Priority } from '../types';
 import { useTodo } from '../contexts/TodoContext';
 
 interface TodoFormProps {
@@ -47,7 +47,7 @@ const TodoForm: React.FC<TodoFormProps> = ({ visible, onClose, todo }) => {
     due_date?: dayjs.Dayjs;
   }) => {
     try {
-      const data: CreateTodoRequest | UpdateTodoRequest = {
+      const data: CreateTodoRequest = {
         title: values.title,
         description: values.description || '',
         priority: values.priority,
@@ -56,9 +56,14 @@ const TodoForm: React.FC<TodoFormProps> = ({ visible, onClose, todo }) => {
       };
 
       if (todo) {
-        await updateTodo(todo.id, data as UpdateTodoRequest);
+        // Cleared fields are sent as null so the patch removes them
+        await updateTodo(todo.id, {
+          ...data,
+          category_id: data.category_id ?? null,
+          due_date: data.due_date ?? null,
+        });
       } else {
-        await createTodo(data as CreateTodoRequest);
+        await createTodo(data);
       }
 
       onClose();
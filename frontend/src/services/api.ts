@@ -48,7 +48,7 @@ export const todoApi = {
   },
 
   update: async (id: number, data: UpdateTodoRequest): Promise<Todo> => {
-    const response = await api.put<Todo>(`/todos/${id}`, data);
+    const response = await api.patch<Todo>(`/todos/${id}`, data);
     return response.data;
   },
 
@@ -80,7 +80,7 @@ export const categoryApi = {
   },
 
   update: async (id: number, data: UpdateCategoryRequest): Promise<Category> => {
-    const response = await api.put<Category>(`/categories/${id}`, data);
+    const response = await api.patch<Category>(`/categories/${id}`, data);
     return response.data;
   },
 
@@ -29,13 +29,15 @@ export interface CreateTodoRequest {
   category_id?: number;
 }
 
+// UpdateTodoRequest is sent as a JSON merge patch: absent fields are kept
+// and null clears a field
 export interface UpdateTodoRequest {
   title?: string;
   description?: string;
   completed?: boolean;
   priority?: Priority;
-  due_date?: string;
-  category_id?: number;
+  due_date?: string | null;
+  category_id?: number | null;
 }
 
 export interface CreateCategoryRequest {
@@ -43,6 +45,8 @@ export interface CreateCategoryRequest {
   color?: string;
 }
 
+// UpdateCategoryRequest is sent as a JSON merge patch, so custom fields the
+// form does not show are kept
 export interface UpdateCategoryRequest {
   name?: string;
   color?: string;